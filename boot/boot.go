@@ -0,0 +1,199 @@
+/*
+package boot executes the declarative stages
+configured in gaffer.json which initialize the
+operating system before Gaffer launches services.
+*/
+package boot
+
+import (
+	"fmt"
+	"github.com/mesanine/gaffer/config"
+	"github.com/mesanine/gaffer/log"
+	"github.com/mesanine/gaffer/network"
+	"github.com/mesanine/ginit"
+	"go.uber.org/zap"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Stage failure policies
+const (
+	// Abort returns the error
+	// ending initialization.
+	Abort = "abort"
+	// Continue logs the error and
+	// moves on to the next stage.
+	Continue = "continue"
+	// Recovery launches a recovery shell.
+	Recovery = "recovery"
+)
+
+// RecoveryShell is executed when a stage
+// with the Recovery policy fails.
+const RecoveryShell = "/bin/sh"
+
+// Run executes each configured stage in order
+// applying the stage failure policy if it returns
+// an error. The default stages are run if none
+// are configured.
+func Run(cfg config.Config) error {
+	stages := cfg.Init.Stages
+	if stages == nil {
+		stages = config.Default.Init.Stages
+	}
+	for _, stage := range stages {
+		switch stage.OnFailure {
+		case "", Abort, Continue, Recovery:
+		default:
+			return fmt.Errorf("stage %s has unknown failure policy %s", stage.Name, stage.OnFailure)
+		}
+	}
+	for _, stage := range stages {
+		log.Log.Info(fmt.Sprintf("running boot stage %s", stage.Name))
+		start := time.Now()
+		err := run(stage, cfg.Network)
+		if err == nil {
+			log.Log.Info(fmt.Sprintf("boot stage %s completed", stage.Name), zap.Duration("duration", time.Since(start)))
+			continue
+		}
+		log.Log.Error(fmt.Sprintf("boot stage %s failed", stage.Name), zap.Error(err), zap.Duration("duration", time.Since(start)))
+		switch stage.OnFailure {
+		case Continue:
+			continue
+		case Recovery:
+			log.Log.Info("dropping into recovery shell")
+			return ginit.Exec(RecoveryShell)
+		default:
+			return fmt.Errorf("boot stage %s failed: %s", stage.Name, err.Error())
+		}
+	}
+	return nil
+}
+
+func run(stage config.Stage, netCfg config.Network) error {
+	mounts := stage.Mounts
+	if stage.Fstab != "" {
+		entries, err := ReadFstab(stage.Fstab)
+		if err != nil {
+			return err
+		}
+		mounts = append(mounts, entries...)
+	}
+	for _, mount := range mounts {
+		log.Log.Info(fmt.Sprintf("mounting %s (%s) @ %s", mount.Source, mount.Type, mount.Target))
+		if err := Mount(mount); err != nil {
+			return err
+		}
+	}
+	if stage.Cgroups != nil {
+		if err := MountCgroups(*stage.Cgroups); err != nil {
+			return err
+		}
+	}
+	for _, module := range stage.Modules {
+		log.Log.Info(fmt.Sprintf("loading kernel module %s", module))
+		if err := call(config.Command{Cmd: "modprobe", Args: []string{module}}); err != nil {
+			return err
+		}
+	}
+	for key, value := range stage.Sysctls {
+		log.Log.Info(fmt.Sprintf("setting sysctl %s=%s", key, value))
+		if err := Sysctl(key, value); err != nil {
+			return err
+		}
+	}
+	hostname := stage.Hostname
+	if stage.HostnamePrefix != "" {
+		name, err := ginit.Hostname(stage.HostnamePrefix)
+		if err != nil {
+			return err
+		}
+		hostname = name
+	}
+	if hostname != "" {
+		log.Log.Info(fmt.Sprintf("setting hostname %s", hostname))
+		if err := syscall.Sethostname([]byte(hostname)); err != nil {
+			return err
+		}
+	}
+	if stage.Network {
+		if err := network.Configure(netCfg); err != nil {
+			return err
+		}
+	}
+	for _, file := range stage.Files {
+		log.Log.Info(fmt.Sprintf("writing file %s", file.Path))
+		if err := WriteFile(file); err != nil {
+			return err
+		}
+	}
+	for _, cmd := range stage.Commands {
+		log.Log.Info(fmt.Sprintf("calling command %s", cmd.Cmd))
+		if err := call(cmd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Sysctl writes a kernel parameter
+// to its path in /proc/sys.
+func Sysctl(key, value string) error {
+	path := filepath.Join("/proc/sys", strings.Replace(key, ".", "/", -1))
+	return ioutil.WriteFile(path, []byte(value), 0644)
+}
+
+// WriteFile writes a file creating
+// any missing parent directories.
+func WriteFile(file config.File) error {
+	mode := os.FileMode(0644)
+	if file.Mode != "" {
+		m, err := strconv.ParseUint(file.Mode, 8, 32)
+		if err != nil {
+			return fmt.Errorf("bad file mode %s: %s", file.Mode, err.Error())
+		}
+		mode = os.FileMode(m)
+	}
+	if err := os.MkdirAll(filepath.Dir(file.Path), 0755); err != nil {
+		return err
+	}
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if file.Append {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+	fd, err := os.OpenFile(file.Path, flags, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := fd.Write([]byte(file.Content)); err != nil {
+		fd.Close()
+		return err
+	}
+	if err := fd.Close(); err != nil {
+		return err
+	}
+	if file.Mode == "" {
+		return nil
+	}
+	// OpenFile does not change the
+	// mode of an existing file.
+	return os.Chmod(file.Path, mode)
+}
+
+func call(cmd config.Command) error {
+	return ginit.Call(ginit.ScriptArgs{
+		Cmd:  cmd.Cmd,
+		Args: cmd.Args,
+		OnStdout: func(out string) {
+			log.Log.Info("stdout", zap.String("cmd", cmd.Cmd), zap.String("output", out))
+		},
+		OnStderr: func(out string) {
+			log.Log.Info("stderr", zap.String("cmd", cmd.Cmd), zap.String("output", out))
+		},
+	})
+}
@@ -0,0 +1,181 @@
+package boot
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/mesanine/gaffer/config"
+	"github.com/mesanine/gaffer/log"
+	"github.com/mesanine/ginit"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// CgroupV1 mounts each controller
+	// as a separate hierarchy.
+	CgroupV1 = "v1"
+	// CgroupV2 mounts the
+	// unified hierarchy.
+	CgroupV2 = "v2"
+	// CgroupRoot is the default path
+	// the hierarchy is mounted at.
+	CgroupRoot = "/sys/fs/cgroup"
+)
+
+// CgroupMount is a mounted cgroup hierarchy.
+type CgroupMount struct {
+	Path    string
+	Version string
+	// Controllers bound to the hierarchy
+	Controllers []string
+}
+
+// MountCgroups mounts the cgroup hierarchy and
+// delegates controllers to the Gaffer subtree.
+func MountCgroups(cg config.Cgroups) error {
+	root := cg.Root
+	if root == "" {
+		root = CgroupRoot
+	}
+	switch cg.Version {
+	case "", CgroupV1:
+		return mountV1(root, cg.Subtree)
+	case CgroupV2:
+		return mountV2(root, cg.Subtree)
+	}
+	return fmt.Errorf("unknown cgroup version %s", cg.Version)
+}
+
+func mountV1(root, subtree string) error {
+	controllers, err := ginit.ReadControllers()
+	if err != nil {
+		return err
+	}
+	err = Mount(config.Mount{
+		Source:  "cgroup_root",
+		Target:  root,
+		Type:    "tmpfs",
+		Options: "nodev,nosuid,noexec,mode=755",
+	})
+	if err != nil {
+		return err
+	}
+	for _, controller := range controllers {
+		if !controller.Enabled {
+			log.Log.Info(fmt.Sprintf("skipping disabled cgroup controller %s", controller.Name))
+			continue
+		}
+		target := filepath.Join(root, controller.Name)
+		log.Log.Info(fmt.Sprintf("mounting cgroup controller %s @ %s", controller.Name, target))
+		err := Mount(config.Mount{
+			Source:  "cgroup",
+			Target:  target,
+			Type:    "cgroup",
+			Options: fmt.Sprintf("nodev,nosuid,noexec,%s", controller.Name),
+		})
+		if err != nil {
+			return err
+		}
+		if subtree != "" {
+			if err := os.MkdirAll(filepath.Join(target, subtree), 0755); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func mountV2(root, subtree string) error {
+	log.Log.Info(fmt.Sprintf("mounting unified cgroup hierarchy @ %s", root))
+	err := Mount(config.Mount{
+		Source:  "cgroup2",
+		Target:  root,
+		Type:    "cgroup2",
+		Options: "nodev,nosuid,noexec,relatime",
+	})
+	if err != nil {
+		return err
+	}
+	if subtree == "" {
+		return nil
+	}
+	controllers, err := readControllers(root)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(root, subtree)
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return err
+	}
+	// Controllers are enabled in the root so they are
+	// available in the subtree and then in the subtree
+	// itself to delegate them to its children.
+	for _, parent := range []string{root, path} {
+		for _, controller := range controllers {
+			log.Log.Info(fmt.Sprintf("enabling cgroup controller %s @ %s", controller, parent))
+			err := ioutil.WriteFile(filepath.Join(parent, "cgroup.subtree_control"), []byte("+"+controller), 0644)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// CgroupMounts returns the cgroup
+// hierarchies listed in /proc/mounts.
+func CgroupMounts() ([]CgroupMount, error) {
+	fd, err := os.Open("/proc/mounts")
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+	controllers, err := ginit.ReadControllers()
+	if err != nil {
+		return nil, err
+	}
+	mounts := []CgroupMount{}
+	scanner := bufio.NewScanner(fd)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 {
+			continue
+		}
+		switch fields[2] {
+		case "cgroup":
+			mount := CgroupMount{Path: fields[1], Version: CgroupV1}
+			for _, controller := range controllers {
+				if hasOption(fields[3], controller.Name) {
+					mount.Controllers = append(mount.Controllers, controller.Name)
+				}
+			}
+			mounts = append(mounts, mount)
+		case "cgroup2":
+			unified, err := readControllers(fields[1])
+			if err != nil {
+				return nil, err
+			}
+			mounts = append(mounts, CgroupMount{
+				Path:        fields[1],
+				Version:     CgroupV2,
+				Controllers: unified,
+			})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return mounts, nil
+}
+
+// readControllers returns the controllers
+// available in a cgroup v2 hierarchy.
+func readControllers(root string) ([]string, error) {
+	raw, err := ioutil.ReadFile(filepath.Join(root, "cgroup.controllers"))
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(raw)), nil
+}
@@ -0,0 +1,130 @@
+package boot
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/mesanine/gaffer/config"
+	"github.com/mesanine/ginit"
+	"golang.org/x/sys/unix"
+	"os"
+	"strings"
+)
+
+// flags maps mount options
+// to their mount(2) flags.
+var flags = map[string]uintptr{
+	"ro":          unix.MS_RDONLY,
+	"rw":          0,
+	"defaults":    0,
+	"nosuid":      unix.MS_NOSUID,
+	"nodev":       unix.MS_NODEV,
+	"noexec":      unix.MS_NOEXEC,
+	"sync":        unix.MS_SYNCHRONOUS,
+	"dirsync":     unix.MS_DIRSYNC,
+	"remount":     unix.MS_REMOUNT,
+	"mand":        unix.MS_MANDLOCK,
+	"noatime":     unix.MS_NOATIME,
+	"nodiratime":  unix.MS_NODIRATIME,
+	"relatime":    unix.MS_RELATIME,
+	"strictatime": unix.MS_STRICTATIME,
+	"bind":        unix.MS_BIND,
+	"rbind":       unix.MS_BIND | unix.MS_REC,
+	"private":     unix.MS_PRIVATE,
+	"rprivate":    unix.MS_PRIVATE | unix.MS_REC,
+	"shared":      unix.MS_SHARED,
+	"rshared":     unix.MS_SHARED | unix.MS_REC,
+	"slave":       unix.MS_SLAVE,
+	"rslave":      unix.MS_SLAVE | unix.MS_REC,
+	// fstab options interpreted
+	// by userspace only
+	"auto":    0,
+	"noauto":  0,
+	"nofail":  0,
+	"nouser":  0,
+	"_netdev": 0,
+}
+
+// ParseOptions splits comma separated mount
+// options into mount(2) flags and the data
+// string passed to the filesystem.
+func ParseOptions(options string) (uintptr, string) {
+	var (
+		f    uintptr
+		data []string
+	)
+	for _, opt := range strings.Split(options, ",") {
+		if opt == "" || strings.HasPrefix(opt, "x-") {
+			continue
+		}
+		if flag, ok := flags[opt]; ok {
+			f |= flag
+			continue
+		}
+		data = append(data, opt)
+	}
+	return f, strings.Join(data, ",")
+}
+
+// Mount mounts the filesystem
+// creating the target if missing.
+func Mount(mount config.Mount) error {
+	f, data := ParseOptions(mount.Options)
+	return ginit.Mount(ginit.MountArgs{
+		Source: mount.Source,
+		Target: mount.Target,
+		FSType: mount.Type,
+		Flags:  f,
+		Data:   data,
+		Before: func() error {
+			return os.MkdirAll(mount.Target, 0755)
+		},
+	})
+}
+
+// ReadFstab reads mounts from an fstab formatted
+// file. Swap entries and entries with the noauto
+// option are skipped.
+func ReadFstab(path string) ([]config.Mount, error) {
+	fd, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+	mounts := []config.Mount{}
+	scanner := bufio.NewScanner(fd)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("bad fstab entry: %s", line)
+		}
+		mount := config.Mount{
+			Source: fields[0],
+			Target: fields[1],
+			Type:   fields[2],
+		}
+		if len(fields) > 3 {
+			mount.Options = fields[3]
+		}
+		if mount.Type == "swap" || hasOption(mount.Options, "noauto") {
+			continue
+		}
+		mounts = append(mounts, mount)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return mounts, nil
+}
+
+func hasOption(options, option string) bool {
+	for _, opt := range strings.Split(options, ",") {
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package boot
+
+import (
+	"github.com/mesanine/gaffer/config"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sys/unix"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestParseOptions(t *testing.T) {
+	f, data := ParseOptions("nosuid,noexec,relatime,size=10m,mode=755")
+	assert.Equal(t, uintptr(unix.MS_NOSUID|unix.MS_NOEXEC|unix.MS_RELATIME), f)
+	assert.Equal(t, "size=10m,mode=755", data)
+	f, data = ParseOptions("defaults,nofail,x-systemd.automount")
+	assert.Equal(t, uintptr(0), f)
+	assert.Equal(t, "", data)
+}
+
+func TestReadFstab(t *testing.T) {
+	fd, err := ioutil.TempFile("", "fstab")
+	assert.NoError(t, err)
+	defer os.Remove(fd.Name())
+	fd.WriteString(`
+# comment
+proc     /proc     proc   nosuid,noexec  0 0
+/dev/sda1 none     swap   sw             0 0
+/dev/sdb1 /mnt/usb vfat   noauto         0 0
+tmpfs    /tmp      tmpfs
+`)
+	fd.Close()
+	mounts, err := ReadFstab(fd.Name())
+	assert.NoError(t, err)
+	assert.Equal(t, []config.Mount{
+		{Source: "proc", Target: "/proc", Type: "proc", Options: "nosuid,noexec"},
+		{Source: "tmpfs", Target: "/tmp", Type: "tmpfs"},
+	}, mounts)
+}
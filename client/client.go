@@ -1,11 +1,14 @@
 package client
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	etcd "github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/clientv3/concurrency"
 	"github.com/mesanine/gaffer/config"
+	"github.com/mesanine/gaffer/event"
 	"github.com/mesanine/gaffer/host"
 	"github.com/mesanine/gaffer/log"
 	"time"
@@ -13,8 +16,10 @@ import (
 
 const (
 	RegistrationKey      = "gaffer_host_"
+	ElectionKey          = "gaffer_leader"
 	DailTimeout          = 5 * time.Second
 	RegistrationLeaseTTL = 60
+	ElectionSessionTTL   = 10
 )
 
 // Client is an HTTP client for
@@ -36,11 +41,9 @@ func New(cfg config.Config) (*Client, error) {
 
 func (s Client) Close() error { return s.etcd.Close() }
 
-func (c Client) Register() error {
-	self, err := host.Self()
-	if err != nil {
-		return err
-	}
+// Register writes the host to etcd under
+// a lease which expires after RegistrationLeaseTTL.
+func (c Client) Register(self *host.Host) error {
 	raw, err := json.Marshal(self)
 	if err != nil {
 		return err
@@ -58,19 +61,103 @@ func (c Client) Register() error {
 	return nil
 }
 
-func (c Client) Hosts() ([]*host.Host, error) {
+// Hosts returns the registered hosts and the etcd
+// revision they were read at which can be passed
+// to Watch to follow changes from that point.
+func (c Client) Hosts() ([]*host.Host, int64, error) {
 	resp, err := c.etcd.Get(context.TODO(), RegistrationKey, etcd.WithPrefix(), etcd.WithSort(etcd.SortByKey, etcd.SortDescend))
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	hosts := []*host.Host{}
 	for _, kv := range resp.Kvs {
 		host := &host.Host{}
 		err = json.Unmarshal(kv.Value, host)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		hosts = append(hosts, host)
 	}
-	return hosts, nil
+	return hosts, resp.Header.Revision, nil
+}
+
+// Watch watches host registrations in etcd and
+// passes HOST_JOINED, HOST_UPDATED and HOST_LEFT
+// events to fn until the context is canceled.
+// Changes after the revision rev are watched or
+// those from now on if rev is zero. Registrations
+// refreshed without any changes are ignored.
+func (c Client) Watch(ctx context.Context, rev int64, fn func(event.Event)) error {
+	opts := []etcd.OpOption{etcd.WithPrefix(), etcd.WithPrevKV()}
+	if rev > 0 {
+		opts = append(opts, etcd.WithRev(rev+1))
+	}
+	wch := c.etcd.Watch(ctx, RegistrationKey, opts...)
+	for resp := range wch {
+		if err := resp.Err(); err != nil {
+			return err
+		}
+		for _, evt := range resp.Events {
+			var (
+				et  event.EventType
+				raw []byte
+			)
+			switch {
+			case evt.Type == etcd.EventTypeDelete:
+				// The previous value is missing
+				// if the revision was compacted.
+				if evt.PrevKv == nil {
+					log.Log.Warn(fmt.Sprintf("host %s left without a previous registration", string(evt.Kv.Key)))
+					continue
+				}
+				et, raw = event.HOST_LEFT, evt.PrevKv.Value
+			case evt.IsCreate():
+				et, raw = event.HOST_JOINED, evt.Kv.Value
+			case evt.IsModify():
+				if evt.PrevKv != nil && bytes.Equal(evt.PrevKv.Value, evt.Kv.Value) {
+					continue
+				}
+				et, raw = event.HOST_UPDATED, evt.Kv.Value
+			}
+			h := &host.Host{}
+			if err := json.Unmarshal(raw, h); err != nil {
+				return err
+			}
+			fn(event.New(et, event.WithHost(h)))
+		}
+	}
+	return ctx.Err()
+}
+
+// Elect campaigns for cluster leadership and
+// blocks until elected. The returned channel is
+// closed when leadership is lost because the
+// election session expired or the context was
+// canceled in which case leadership is resigned.
+func (c Client) Elect(ctx context.Context, value string) (<-chan struct{}, error) {
+	session, err := concurrency.NewSession(c.etcd, concurrency.WithTTL(ElectionSessionTTL))
+	if err != nil {
+		return nil, err
+	}
+	election := concurrency.NewElection(session, ElectionKey)
+	if err := election.Campaign(ctx, value); err != nil {
+		session.Close()
+		return nil, err
+	}
+	log.Log.Info(fmt.Sprintf("elected cluster leader: %s", value))
+	lost := make(chan struct{})
+	go func() {
+		defer close(lost)
+		select {
+		case <-session.Done():
+		case <-ctx.Done():
+			resignCtx, cancel := context.WithTimeout(context.Background(), DailTimeout)
+			defer cancel()
+			if err := election.Resign(resignCtx); err != nil {
+				log.Log.Warn(fmt.Sprintf("failed to resign cluster leadership: %s", err.Error()))
+			}
+			session.Close()
+		}
+	}()
+	return lost, nil
 }
@@ -1,23 +1,39 @@
 package cmd
 
 import (
-	"encoding/json"
+	"context"
 	"github.com/jawher/mow.cli"
 	"github.com/mesanine/gaffer/client"
 	"github.com/mesanine/gaffer/config"
+	"github.com/mesanine/gaffer/event"
+	"github.com/mesanine/gaffer/host"
 	"github.com/mesanine/gaffer/util"
-	"os"
 )
 
 func hostsCMD(cfg *config.Config) func(*cli.Cmd) {
 	return func(cmd *cli.Cmd) {
 		cmd.Spec = "[OPTIONS]"
+		labels := cmd.Strings(cli.StringsOpt{
+			Name:  "l label",
+			Desc:  "Only list hosts with this label formatted as KEY=VALUE",
+			Value: []string{},
+		})
+		watch := cmd.BoolOpt("w watch", false, "Stream host membership changes after listing")
 		cmd.Action = func() {
+			filter, err := host.ParseLabels(*labels)
+			util.Maybe(err)
 			cli, err := client.New(*cfg)
 			util.Maybe(err)
-			hosts, err := cli.Hosts()
+			hosts, rev, err := cli.Hosts()
 			util.Maybe(err)
-			util.Maybe(json.NewEncoder(os.Stdout).Encode(hosts))
+			util.Print(*cfg, host.Hosts(hosts).Filter(host.ByLabels(filter)))
+			if *watch {
+				util.Maybe(cli.Watch(context.Background(), rev, func(evt event.Event) {
+					if host.ByLabels(filter)(evt.Host) {
+						util.Print(*cfg, evt)
+					}
+				}))
+			}
 		}
 	}
 }
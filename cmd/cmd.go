@@ -4,12 +4,18 @@ import (
 	"fmt"
 	"github.com/jawher/mow.cli"
 	"github.com/mesanine/gaffer/config"
+	"github.com/mesanine/gaffer/fatal"
 	"github.com/mesanine/gaffer/log"
 	"github.com/mesanine/gaffer/plugin"
+	"github.com/mesanine/gaffer/plugin/external"
+	"github.com/mesanine/gaffer/plugin/kmsg"
 	"github.com/mesanine/gaffer/plugin/logger"
 	"github.com/mesanine/gaffer/plugin/metrics"
+	"github.com/mesanine/gaffer/plugin/network"
 	"github.com/mesanine/gaffer/plugin/register"
 	"github.com/mesanine/gaffer/plugin/supervisor"
+	"github.com/mesanine/gaffer/plugin/system"
+	"github.com/mesanine/gaffer/plugin/watchdog"
 	"github.com/mesanine/gaffer/util"
 	"github.com/mesanine/gaffer/version"
 
@@ -17,85 +23,102 @@ import (
 )
 
 func Run() {
-	cfg := &config.Config{}
+	cfg := config.New()
+	sources := config.Sources{}
 	app := cli.App("gaffer", "Distributed Init System")
 	app.Spec = "[OPTIONS]"
 	var configPath = app.StringOpt("c config", "", "Path to a gaffer.json configuration file")
 	app.Version("version", fmt.Sprintf("version=%s\ngitsha=%s", version.Version, version.GitSHA))
-	enabled := app.Strings(cli.StringsOpt{
+	opts := newBinder(app.Cmd, sources)
+	opts.Strings("enabled_plugins", cli.StringsOpt{
 		Name:   "p plugin",
 		Desc:   "Toggled plugins",
 		Value:  config.Default.EnabledPlugins,
 		EnvVar: "GAFFER_ENABLED_PLUGINS",
-	})
-	disabled := app.Strings(cli.StringsOpt{
+	}, &cfg.EnabledPlugins)
+	opts.Strings("disabled_plugins", cli.StringsOpt{
 		Name:   "disable",
 		Desc:   "Disable the specified plugin",
 		Value:  config.Default.DisabledPlugins,
 		EnvVar: "GAFFER_DISABLED_PLUGINS",
-	})
-	device := app.String(cli.StringOpt{
+	}, &cfg.DisabledPlugins)
+	opts.String("logger.device", cli.StringOpt{
 		Name:   "d device",
 		Desc:   "Send log output to a block device",
 		Value:  config.Default.Logger.Device,
 		EnvVar: "GAFFER_LOGGER_DEVICE",
-	})
-	logDir := app.String(cli.StringOpt{
+	}, &cfg.Logger.Device)
+	opts.String("logger.log_dir", cli.StringOpt{
 		Name:   "log-dir",
 		Desc:   "Send log output to files in this directory",
 		Value:  config.Default.Logger.LogDir,
 		EnvVar: "GAFFER_LOGGER_DIRECTORY",
-	})
-	maxLogSize := app.Int(cli.IntOpt{
+	}, &cfg.Logger.LogDir)
+	opts.Int("logger.max_size", cli.IntOpt{
 		Name:   "max-log-size",
 		Desc:   "Maximum log file size in mb",
 		Value:  config.Default.Logger.MaxSize,
 		EnvVar: "GAFFER_LOGGER_MAX_SIZE",
-	})
-	maxBackups := app.Int(cli.IntOpt{
+	}, &cfg.Logger.MaxSize)
+	opts.Int("logger.max_backups", cli.IntOpt{
 		Name:   "max-backups",
 		Desc:   "Maximum number of backups to rotate",
 		Value:  config.Default.Logger.MaxBackups,
 		EnvVar: "GAFFER_LOGGER_MAX_BACKUPS",
-	})
-	compress := app.Bool(cli.BoolOpt{
+	}, &cfg.Logger.MaxBackups)
+	opts.Bool("logger.compress", cli.BoolOpt{
 		Name:   "compress",
 		Desc:   "Compress rotated log files",
 		Value:  config.Default.Logger.Compress,
 		EnvVar: "GAFFER_LOGGER_COMPRESS",
-	})
-	debug := app.Bool(cli.BoolOpt{
+	}, &cfg.Logger.Compress)
+	opts.Bool("logger.debug", cli.BoolOpt{
 		Name:   "debug",
 		Desc:   "Output debugging information",
 		Value:  config.Default.Logger.Debug,
 		EnvVar: "GAFFER_LOGGER_DEBUG",
-	})
-	endpoints := app.Strings(cli.StringsOpt{
+	}, &cfg.Logger.Debug)
+	opts.Strings("endpoints", cli.StringsOpt{
 		Name:   "e endpoints",
 		Desc:   "Etcd endpoint",
 		Value:  config.Default.Endpoints,
 		EnvVar: "GAFFER_ENDPOINT",
-	})
+	}, &cfg.Endpoints)
+	opts.String("user", cli.StringOpt{
+		Name:   "u user",
+		Desc:   "User credentials formatted as ID:TOKEN",
+		Value:  config.Default.User,
+		EnvVar: "GAFFER_USER",
+	}, &cfg.User)
+	opts.String("output.format", cli.StringOpt{
+		Name:   "o output",
+		Desc:   "Output format: table, wide, json, yaml or template",
+		Value:  config.Default.Output.Format,
+		EnvVar: "GAFFER_OUTPUT",
+	}, &cfg.Output.Format)
+	opts.String("output.template", cli.StringOpt{
+		Name:   "template",
+		Desc:   "Go template executed with each response if the output format is template",
+		Value:  config.Default.Output.Template,
+		EnvVar: "GAFFER_TEMPLATE",
+	}, &cfg.Output.Template)
 	app.Before = func() {
+		// Options override the configuration
+		// file which overrides the defaults.
 		if *configPath != "" {
-			util.Maybe(config.Load(*configPath, cfg))
+			util.Maybe(config.Load(*configPath, cfg, sources))
 		}
-		cfg.Endpoints = *endpoints
-		cfg.EnabledPlugins = *enabled
-		cfg.DisabledPlugins = *disabled
-		cfg.Logger.Device = *device
-		cfg.Logger.LogDir = *logDir
-		cfg.Logger.MaxSize = *maxLogSize
-		cfg.Logger.MaxBackups = *maxBackups
-		cfg.Logger.Compress = *compress
-		cfg.Logger.Debug = *debug
+		util.Maybe(opts.Apply())
 		// Initialize the logger
 		util.Maybe(log.Setup(*cfg))
+		fatal.Setup(*cfg)
+		util.Maybe(util.CheckOutput(cfg.Output))
 	}
 	app.Command("init", "Bootstrap the operating system", initCMD(cfg))
-	app.Command("launch", "Launch plugin subsystems", launchCMD(cfg))
+	app.Command("launch", "Launch plugin subsystems", launchCMD(cfg, sources))
 	app.Command("hosts", "List remote hosts", hostsCMD(cfg))
-	app.Command("remote", "Make RPC calls against a host", remoteCMD(cfg))
+	app.Command("remote", "Make RPC calls against a host", remoteCMD(cfg, sources))
+	app.Command("config", "Inspect and validate configuration", configCMD(cfg, sources))
 	util.Maybe(app.Run(os.Args))
 }
 
@@ -111,6 +134,16 @@ func getPlugins(cfg *config.Config) []plugin.Plugin {
 			plugins = append(plugins, supervisor.New())
 		case "register":
 			plugins = append(plugins, register.New())
+		case "system":
+			plugins = append(plugins, system.New())
+		case "network":
+			plugins = append(plugins, network.New())
+		case "watchdog":
+			plugins = append(plugins, watchdog.New())
+		case "external":
+			plugins = append(plugins, external.New())
+		case "kmsg":
+			plugins = append(plugins, kmsg.New())
 		default:
 			util.Maybe(fmt.Errorf("unknown plugin: %s", p))
 		}
@@ -119,5 +152,5 @@ func getPlugins(cfg *config.Config) []plugin.Plugin {
 }
 
 func allPlugins() []plugin.Plugin {
-	return []plugin.Plugin{logger.New(), metrics.New(), supervisor.New(), register.New()}
+	return []plugin.Plugin{logger.New(), metrics.New(), supervisor.New(), register.New(), system.New(), network.New(), watchdog.New(), external.New(), kmsg.New()}
 }
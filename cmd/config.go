@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/jawher/mow.cli"
+	"github.com/mesanine/gaffer/config"
+	"github.com/mesanine/gaffer/util"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+// maxValue is the width of values
+// shown in the configuration table.
+const maxValue = 60
+
+// Effective is the merged configuration
+// and the source of each value.
+type Effective struct {
+	Config  *config.Config `json:"config"`
+	Sources config.Sources `json:"sources"`
+}
+
+// Table lists each configuration value with its source.
+// Arrays and maps are shown as JSON on a single line
+// which is truncated unless the table is wide.
+func (e Effective) Table(wide bool) ([]string, [][]string) {
+	raw, _ := json.Marshal(e.Config)
+	var generic map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	decoder.Decode(&generic)
+	rows := [][]string{}
+	config.Walk(generic, func(path string, value interface{}) {
+		var str string
+		switch t := value.(type) {
+		case string:
+			str = t
+		case nil:
+			str = "-"
+		default:
+			encoded, _ := json.Marshal(t)
+			str = string(encoded)
+		}
+		if !wide && len(str) > maxValue {
+			str = str[:maxValue-3] + "..."
+		}
+		rows = append(rows, []string{path, str, e.Sources.Of(path)})
+	})
+	sort.Slice(rows, func(i, j int) bool { return rows[i][0] < rows[j][0] })
+	return []string{"PATH", "VALUE", "SOURCE"}, rows
+}
+
+// redact hides the token of user
+// credentials formatted as ID:TOKEN.
+func redact(user string) string {
+	if user == "" {
+		return ""
+	}
+	if i := strings.Index(user, ":"); i >= 0 {
+		return user[:i] + ":****"
+	}
+	return "****"
+}
+
+func pluginNames() []string {
+	names := []string{}
+	for _, p := range allPlugins() {
+		names = append(names, p.Name())
+	}
+	return names
+}
+
+func configCMD(cfg *config.Config, sources config.Sources) cli.CmdInitializer {
+	return func(cmd *cli.Cmd) {
+		cmd.Command("validate", "Validate a configuration file", func(cmd *cli.Cmd) {
+			cmd.Spec = "FILE"
+			path := cmd.StringArg("FILE", "", "Path to a gaffer.json configuration file")
+			cmd.Action = func() {
+				raw, err := ioutil.ReadFile(*path)
+				util.Maybe(err)
+				// Unknown fields are rejected
+				// here but ignored at boot.
+				util.Maybe(config.Decode(raw, &config.Config{}))
+				loaded := config.New()
+				util.Maybe(config.Load(*path, loaded, nil))
+				errs := config.Validate(*loaded, pluginNames())
+				for _, err := range errs {
+					fmt.Println(err.Error())
+				}
+				if len(errs) > 0 {
+					util.Maybe(fmt.Errorf("%s has %d error(s)", *path, len(errs)))
+				}
+				fmt.Printf("%s is valid\n", *path)
+			}
+		})
+		cmd.Command("show", "Show the effective configuration and the source of each value", func(cmd *cli.Cmd) {
+			cmd.Spec = "[OPTIONS]"
+			opts := newBinder(cmd, sources)
+			launchOptions(opts, cfg)
+			cmd.Before = func() {
+				util.Maybe(opts.Apply())
+			}
+			cmd.Action = func() {
+				shown := *cfg
+				shown.User = redact(cfg.User)
+				util.Print(*cfg, Effective{Config: &shown, Sources: sources})
+			}
+		})
+		cmd.Command("schema", "Show the JSON Schema of the configuration file", func(cmd *cli.Cmd) {
+			cmd.Action = func() {
+				out := *cfg
+				// The schema is a document
+				// rather than a table.
+				if out.Output.Format == util.Table || out.Output.Format == util.Wide {
+					out.Output.Format = util.JSON
+				}
+				util.Print(out, config.Schema())
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"github.com/jawher/mow.cli"
+	"github.com/mesanine/gaffer/boot"
 	"github.com/mesanine/gaffer/config"
 	"github.com/mesanine/gaffer/log"
 	"github.com/mesanine/gaffer/util"
@@ -21,9 +22,6 @@ func initCMD(cfg *config.Config) func(*cli.Cmd) {
 		})
 		cmd.Spec = "[OPTIONS]"
 		cmd.Action = func() {
-			// TODO: Need a clean way to initialize the OS
-			// in "stages" but I am not sure what the best
-			// abstraction is to do this at the moment.
 			if !ginit.IsRoot() {
 				util.Maybe(fmt.Errorf("init can only be run as root"))
 			}
@@ -47,38 +45,27 @@ func initCMD(cfg *config.Config) func(*cli.Cmd) {
 				log.Log.Info("dropping into single user mode")
 				util.Maybe(ginit.Exec("/bin/sh"))
 			}
-			// Load the procfs file system
-			log.Log.Info("Loading procfs")
-			util.Maybe(ginit.Mount(ginit.MountArgs{
-				Source: "proc",
-				Target: "/proc",
-				FSType: "proc",
-				Flags:  0,
-				Data:   "nodev,nosuid,noexec,relatime"}))
-			// Load devfs filesystem
-			log.Log.Info("Loading devfs")
-			util.Maybe(ginit.Mount(
-				ginit.MountArgs{
-					Source: "dev",
-					Target: "/dev",
-					FSType: "devtmpfs",
-					Data:   "nosuid,noexec,relatime,size=10m,nr_inodes=248418,mode=755",
-				}))
-			log.Log.Info(fmt.Sprintf("calling init helper script: %s", cfg.Init.Helper))
-			// Call the init helper script which does most of the
-			// heavy lifting for now.
-			util.Maybe(
-				ginit.Call(ginit.ScriptArgs{
-					Cmd: cfg.Init.Helper,
-					OnStdout: func(out string) {
-						log.Log.Info("stdout", zap.String("output", out))
-					},
-					OnStderr: func(out string) {
-						log.Log.Info("stderr", zap.String("output", out))
-					}}))
-			log.Log.Info("helper script ran successfully")
+			// Stages default to mounting
+			// procfs and devfs if none are
+			// configured.
+			util.Maybe(boot.Run(*cfg))
+			if cfg.Init.Helper != "" {
+				log.Log.Info(fmt.Sprintf("calling init helper script: %s", cfg.Init.Helper))
+				// Call the init helper script for anything
+				// not handled by the configured stages.
+				util.Maybe(
+					ginit.Call(ginit.ScriptArgs{
+						Cmd: cfg.Init.Helper,
+						OnStdout: func(out string) {
+							log.Log.Info("stdout", zap.String("output", out))
+						},
+						OnStderr: func(out string) {
+							log.Log.Info("stderr", zap.String("output", out))
+						}}))
+				log.Log.Info("helper script ran successfully")
+			}
 			// Perform an exec syscall which becomes PID 1
-			util.Maybe(ginit.Exec(os.Args[0], "--config=/etc/gaffer.json", "run"))
+			util.Maybe(ginit.Exec(os.Args[0], "--config=/etc/gaffer.json", "launch"))
 		}
 	}
 }
@@ -1,64 +1,36 @@
 package cmd
 
 import (
+	"fmt"
 	"github.com/jawher/mow.cli"
 	"github.com/mesanine/gaffer/config"
 	"github.com/mesanine/gaffer/log"
 	"github.com/mesanine/gaffer/plugin"
 	"github.com/mesanine/gaffer/plugin/supervisor"
+	"github.com/mesanine/gaffer/reaper"
+	"github.com/mesanine/gaffer/shutdown"
 	"github.com/mesanine/gaffer/store"
 	"github.com/mesanine/gaffer/util"
 	"github.com/mesanine/ginit"
 )
 
-func launchCMD(cfg *config.Config) cli.CmdInitializer {
+func launchCMD(cfg *config.Config, sources config.Sources) cli.CmdInitializer {
 	return func(cmd *cli.Cmd) {
 		cmd.Spec = "[OPTIONS]"
-		address := cmd.String(cli.StringOpt{
-			Name:   "a address",
-			Desc:   "RPC server address",
-			Value:  config.Default.Address,
-			EnvVar: "GAFFER_ADDRESS",
-		})
-		configPath := cmd.String(cli.StringOpt{
-			Name:   "config-path",
-			Desc:   "Service configuration path",
-			Value:  config.Default.Store.ConfigPath,
-			EnvVar: "GAFFER_STORE_CONFIG_PATH",
-		})
-		basePath := cmd.String(cli.StringOpt{
-			Name:   "store-path",
-			Desc:   "Container store path",
-			Value:  config.Default.Store.BasePath,
-			EnvVar: "GAFFER_STORE_PATH",
-		})
-		runcRoot := cmd.String(cli.StringOpt{
-			Name:   "runc-root",
-			Desc:   "Runc root path",
-			Value:  config.Default.RuncRoot,
-			EnvVar: "GAFFER_RUNC_ROOT",
-		})
-		mount := cmd.Bool(cli.BoolOpt{
-			Name:   "mount",
-			Desc:   "Handle filesystem mounts",
-			Value:  config.Default.Store.Mount,
-			EnvVar: "GAFFER_STORE_MOUNT",
-		})
-		moveRoot := cmd.Bool(cli.BoolOpt{
-			Name:   "move-root",
-			Desc:   "Migrate moby created lower path to rootfs",
-			Value:  config.Default.Store.MoveRoot,
-			EnvVar: "GAFFER_STORE_MOVE_ROOT",
-		})
+		opts := newBinder(cmd, sources)
+		launchOptions(opts, cfg)
 		cmd.Before = func() {
-			cfg.Address = *address
-			cfg.RuncRoot = *runcRoot
-			cfg.Store.ConfigPath = *configPath
-			cfg.Store.BasePath = *basePath
-			cfg.Store.Mount = *mount
-			cfg.Store.MoveRoot = *moveRoot
+			util.Maybe(opts.Apply())
+			// Launch runs as PID 1 so problems
+			// are logged rather than fatal.
+			for _, err := range config.Validate(*cfg, pluginNames()) {
+				log.Log.Warn(fmt.Sprintf("invalid configuration: %s", err.Error()))
+			}
 		}
 		cmd.Action = func() {
+			// Reap orphaned processes and wait
+			// on runc commands with the reaper.
+			util.Maybe(reaper.Default.Install())
 			log.Log.Info("starting onboot services")
 			// Launch any containers synchronously
 			// that exist in directory "onboot" in
@@ -72,13 +44,12 @@ func launchCMD(cfg *config.Config) cli.CmdInitializer {
 			// "services" path in the store root.
 			db = store.New(*cfg, "services")
 			util.Maybe(db.Init())
-			handlers := []ginit.Handler{}
+			handlers := []ginit.Handler{reaper.Default}
 			reg := plugin.NewRegistry()
 			for _, p := range getPlugins(cfg) {
 				util.Maybe(reg.Register(p))
 			}
 			util.Maybe(reg.Configure(*cfg))
-			handlers = append(handlers, reg)
 			if cfg.Address != "" {
 				server, err := plugin.NewServer(*cfg)
 				util.Maybe(err)
@@ -87,11 +58,68 @@ func launchCMD(cfg *config.Config) cli.CmdInitializer {
 					util.Maybe(server.Run(reg))
 				}()
 			}
+			if cfg.HTTPAddress != "" {
+				gateway, err := plugin.NewGateway(*cfg)
+				util.Maybe(err)
+				handlers = append(handlers, gateway)
+				go func() {
+					util.Maybe(gateway.Run(reg))
+				}()
+			}
 			go func() {
 				util.Maybe(reg.Run())
 			}()
-			util.Maybe(ginit.Init(handlers...))
-			util.Maybe(db.Close())
+			// The shutdown manager stops the plugins
+			// and unmounts the services store.
+			util.Maybe(shutdown.New(reg, db).Init(handlers...))
 		}
 	}
 }
+
+// launchOptions binds the options of launch which
+// are also bound by config show so it reports the
+// configuration launch would use.
+func launchOptions(opts *binder, cfg *config.Config) {
+	opts.String("address", cli.StringOpt{
+		Name:   "a address",
+		Desc:   "RPC server address",
+		Value:  config.Default.Address,
+		EnvVar: "GAFFER_ADDRESS",
+	}, &cfg.Address)
+	opts.String("http_address", cli.StringOpt{
+		Name:   "http-address",
+		Desc:   "HTTP gateway address",
+		Value:  config.Default.HTTPAddress,
+		EnvVar: "GAFFER_HTTP_ADDRESS",
+	}, &cfg.HTTPAddress)
+	opts.String("store.config_path", cli.StringOpt{
+		Name:   "config-path",
+		Desc:   "Service configuration path",
+		Value:  config.Default.Store.ConfigPath,
+		EnvVar: "GAFFER_STORE_CONFIG_PATH",
+	}, &cfg.Store.ConfigPath)
+	opts.String("store.base_path", cli.StringOpt{
+		Name:   "store-path",
+		Desc:   "Container store path",
+		Value:  config.Default.Store.BasePath,
+		EnvVar: "GAFFER_STORE_PATH",
+	}, &cfg.Store.BasePath)
+	opts.String("runc_root", cli.StringOpt{
+		Name:   "runc-root",
+		Desc:   "Runc root path",
+		Value:  config.Default.RuncRoot,
+		EnvVar: "GAFFER_RUNC_ROOT",
+	}, &cfg.RuncRoot)
+	opts.Bool("store.mount", cli.BoolOpt{
+		Name:   "mount",
+		Desc:   "Handle filesystem mounts",
+		Value:  config.Default.Store.Mount,
+		EnvVar: "GAFFER_STORE_MOUNT",
+	}, &cfg.Store.Mount)
+	opts.Bool("store.move_root", cli.BoolOpt{
+		Name:   "move-root",
+		Desc:   "Migrate moby created lower path to rootfs",
+		Value:  config.Default.Store.MoveRoot,
+		EnvVar: "GAFFER_STORE_MOVE_ROOT",
+	}, &cfg.Store.MoveRoot)
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"github.com/jawher/mow.cli"
+	"github.com/mesanine/gaffer/config"
+	"os"
+)
+
+// binding applies an option to the
+// configuration value at path.
+type binding struct {
+	path   string
+	envVar string
+	set    *bool
+	apply  func() error
+}
+
+// binder declares options bound to configuration
+// values. Options only override values from the
+// configuration file if they are given on the
+// command line or by their environment variable.
+type binder struct {
+	cmd      *cli.Cmd
+	sources  config.Sources
+	bindings []binding
+}
+
+func newBinder(cmd *cli.Cmd, sources config.Sources) *binder {
+	return &binder{cmd: cmd, sources: sources}
+}
+
+func (b *binder) add(path, envVar string, set *bool, apply func() error) {
+	b.bindings = append(b.bindings, binding{path: path, envVar: envVar, set: set, apply: apply})
+}
+
+func (b *binder) String(path string, opt cli.StringOpt, dst *string) {
+	opt.SetByUser = new(bool)
+	value := b.cmd.String(opt)
+	b.add(path, opt.EnvVar, opt.SetByUser, func() error {
+		*dst = *value
+		return nil
+	})
+}
+
+func (b *binder) Strings(path string, opt cli.StringsOpt, dst *[]string) {
+	opt.SetByUser = new(bool)
+	value := b.cmd.Strings(opt)
+	b.add(path, opt.EnvVar, opt.SetByUser, func() error {
+		*dst = *value
+		return nil
+	})
+}
+
+func (b *binder) Int(path string, opt cli.IntOpt, dst *int) {
+	opt.SetByUser = new(bool)
+	value := b.cmd.Int(opt)
+	b.add(path, opt.EnvVar, opt.SetByUser, func() error {
+		*dst = *value
+		return nil
+	})
+}
+
+func (b *binder) Bool(path string, opt cli.BoolOpt, dst *bool) {
+	opt.SetByUser = new(bool)
+	value := b.cmd.Bool(opt)
+	b.add(path, opt.EnvVar, opt.SetByUser, func() error {
+		*dst = *value
+		return nil
+	})
+}
+
+// Apply copies each option given on the command
+// line or by its environment variable into the
+// configuration recording where it was set from.
+func (b *binder) Apply() error {
+	for _, binding := range b.bindings {
+		source := ""
+		switch {
+		case *binding.set:
+			source = config.SourceFlag
+		case binding.envVar != "" && os.Getenv(binding.envVar) != "":
+			source = config.SourceEnv
+		default:
+			continue
+		}
+		if err := binding.apply(); err != nil {
+			return err
+		}
+		b.sources[binding.path] = source
+	}
+	return nil
+}
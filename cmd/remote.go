@@ -4,23 +4,75 @@ import (
 	"fmt"
 	"github.com/jawher/mow.cli"
 	"github.com/mesanine/gaffer/config"
+	"github.com/mesanine/gaffer/host"
 	"github.com/mesanine/gaffer/plugin"
+	"github.com/mesanine/gaffer/plugin/supervisor"
+	"github.com/mesanine/gaffer/util"
 )
 
-func remoteCMD(cfg *config.Config) func(*cli.Cmd) {
+func remoteCMD(cfg *config.Config, sources config.Sources) func(*cli.Cmd) {
 	return func(cmd *cli.Cmd) {
 		cmd.Spec = "[OPTIONS]"
+		opts := newBinder(cmd, sources)
 		// Default RPC target is a local socket connection
 		// to gaffer running on the host.
-		address := cmd.String(cli.StringOpt{
+		opts.String("address", cli.StringOpt{
 			Name:   "a address",
 			Desc:   "RPC server address",
 			Value:  config.Default.Address,
 			EnvVar: "GAFFER_ADDRESS",
+		}, &cfg.Address)
+		opts.String("remote.name", cli.StringOpt{
+			Name:   "host-name",
+			Desc:   "Call registered hosts with names matching this regular expression",
+			Value:  config.Default.Remote.Name,
+			EnvVar: "GAFFER_REMOTE_NAME",
+		}, &cfg.Remote.Name)
+		opts.String("remote.ip", cli.StringOpt{
+			Name:   "ip",
+			Desc:   "Call the registered host with this IP address",
+			Value:  config.Default.Remote.IP,
+			EnvVar: "GAFFER_REMOTE_IP",
+		}, &cfg.Remote.IP)
+		opts.String("remote.mac", cli.StringOpt{
+			Name:   "mac",
+			Desc:   "Call the registered host with this MAC address",
+			Value:  config.Default.Remote.MAC,
+			EnvVar: "GAFFER_REMOTE_MAC",
+		}, &cfg.Remote.MAC)
+		labelsOpt := cli.StringsOpt{
+			Name:      "l label",
+			Desc:      "Call registered hosts with this label formatted as KEY=VALUE",
+			Value:     []string{},
+			EnvVar:    "GAFFER_REMOTE_LABELS",
+			SetByUser: new(bool),
+		}
+		labels := cmd.Strings(labelsOpt)
+		opts.add("remote.labels", labelsOpt.EnvVar, labelsOpt.SetByUser, func() error {
+			parsed, err := host.ParseLabels(*labels)
+			if err != nil {
+				return err
+			}
+			cfg.Remote.Labels = parsed
+			return nil
 		})
+		opts.Bool("remote.all", cli.BoolOpt{
+			Name:   "all",
+			Desc:   "Call all registered hosts",
+			Value:  config.Default.Remote.All,
+			EnvVar: "GAFFER_REMOTE_ALL",
+		}, &cfg.Remote.All)
+		opts.Int("remote.port", cli.IntOpt{
+			Name:   "port",
+			Desc:   "RPC port of registered hosts which did not advertise one",
+			Value:  config.Default.Remote.Port,
+			EnvVar: "GAFFER_REMOTE_PORT",
+		}, &cfg.Remote.Port)
 		cmd.Before = func() {
-			cfg.Address = *address
+			util.Maybe(opts.Apply())
 		}
+		cmd.Command("plugins", "plugins commands", plugin.NewRegistry().CLI(cfg))
+		cmd.Command("top", "Show a live table of services", supervisor.TopCMD(cfg))
 		for _, p := range allPlugins() {
 			if c, ok := p.(plugin.CLI); ok {
 				cmd.Command(p.Name(), fmt.Sprintf("%s commands", p.Name()), c.CLI(cfg))
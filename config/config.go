@@ -1,10 +1,9 @@
 package config
 
 import (
-	"encoding/json"
 	"fmt"
+	"github.com/mesanine/gaffer/user"
 	"google.golang.org/grpc"
-	"io/ioutil"
 	"net"
 	"net/url"
 	"time"
@@ -16,8 +15,25 @@ type Config struct {
 	Init   Init   `json:"init"`
 	Store  Store  `json:"store"`
 	Logger Logger `json:"logger"`
+	Remote Remote `json:"remote"`
+	// Network interface configuration
+	Network Network `json:"network"`
+	// Watchdog configuration
+	Watchdog Watchdog `json:"watchdog"`
+	// Diagnostics collected on fatal errors
+	Diagnostics Diagnostics `json:"diagnostics"`
+	// External plugins run out of process
+	External []External `json:"external"`
+	// FailurePolicy is applied when a plugin
+	// returns an error: restart, ignore or fatal.
+	FailurePolicy string `json:"failure_policy"`
+	// FailurePolicies overrides the
+	// failure policy of each plugin.
+	FailurePolicies map[string]string `json:"failure_policies"`
 	// RPC Address
 	Address string `json:"address"`
+	// HTTP gateway address
+	HTTPAddress string `json:"http_address"`
 	// etcd endpoints
 	Endpoints []string `json:"endpoints"`
 	// Runc root path
@@ -26,7 +42,14 @@ type Config struct {
 	EnabledPlugins []string `json:"enabled_plugins"`
 	// Disabled plugins
 	DisabledPlugins []string `json:"disabled_plugins"`
-	// HTTP User
+	// Output of CLI responses
+	Output Output `json:"output"`
+	// Labels are arbitrary key/value
+	// pairs registered with this host.
+	Labels map[string]string `json:"labels"`
+	// User credentials formatted as
+	// ID:TOKEN. If set all RPC and HTTP
+	// requests must be authenticated.
 	User string `json:"user"`
 }
 
@@ -45,8 +68,14 @@ loop:
 }
 
 func (c Config) DailOpts() ([]grpc.DialOption, error) {
-	// TODO
 	opts := []grpc.DialOption{grpc.WithInsecure()}
+	if c.User != "" {
+		u, err := user.FromString(c.User)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.WithPerRPCCredentials(u))
+	}
 	opts = append(opts,
 		grpc.WithDialer(func(addr string, d time.Duration) (net.Conn, error) {
 			u, err := url.Parse(addr)
@@ -69,6 +98,18 @@ func (c Config) CallOpts() []grpc.CallOption {
 	return []grpc.CallOption{}
 }
 
+// Output controls how the
+// CLI prints responses.
+type Output struct {
+	// Format is table, wide, json,
+	// yaml or template.
+	Format string `json:"format"`
+	// Template is a Go template executed
+	// with the JSON encoding of responses
+	// if the format is template.
+	Template string `json:"template"`
+}
+
 // Init holds OS initialization options
 type Init struct {
 	// Helper is the path to a "helper"
@@ -79,6 +120,89 @@ type Init struct {
 	// tempfs contents are compied and switch
 	// moves the base rootfs to.
 	NewRoot string `json:"new_root"`
+	// Stages are executed in order
+	// before the helper script.
+	Stages []Stage `json:"stages"`
+}
+
+// Stage is a step of OS initialization. Each
+// section of a stage is optional and they are
+// executed in the order they are declared here.
+type Stage struct {
+	Name string `json:"name"`
+	// OnFailure is the action taken if the
+	// stage fails: abort, continue or recovery
+	// which launches a recovery shell.
+	OnFailure string `json:"on_failure"`
+	// Mounts are filesystems to mount.
+	Mounts []Mount `json:"mounts"`
+	// Fstab is the path to an fstab
+	// formatted file of mounts.
+	Fstab string `json:"fstab"`
+	// Cgroups mounts the cgroup hierarchy.
+	Cgroups *Cgroups `json:"cgroups"`
+	// Network brings up loopback and applies
+	// the static configuration of each
+	// interface in the network section.
+	Network bool `json:"network"`
+	// Modules are kernel modules
+	// loaded with modprobe.
+	Modules []string `json:"modules"`
+	// Sysctls are kernel parameters
+	// such as net.ipv4.ip_forward.
+	Sysctls map[string]string `json:"sysctls"`
+	// Hostname sets the system hostname.
+	Hostname string `json:"hostname"`
+	// HostnamePrefix sets the hostname
+	// to the prefix followed by the MAC
+	// address of the first interface.
+	HostnamePrefix string `json:"hostname_prefix"`
+	// Files are written to disk.
+	Files []File `json:"files"`
+	// Commands are executed in order.
+	Commands []Command `json:"commands"`
+}
+
+// Mount is a filesystem mount.
+type Mount struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Type   string `json:"type"`
+	// Options are comma separated like
+	// mount -o, e.g. nosuid,mode=755
+	Options string `json:"options"`
+}
+
+// Cgroups configures the cgroup hierarchy.
+type Cgroups struct {
+	// Version is v1 to mount each controller
+	// separately or v2 to mount the unified
+	// hierarchy.
+	Version string `json:"version"`
+	// Root is where the hierarchy is
+	// mounted, e.g. /sys/fs/cgroup
+	Root string `json:"root"`
+	// Subtree is a cgroup owned by Gaffer
+	// where controllers are delegated to.
+	Subtree string `json:"subtree"`
+}
+
+// File is a file written during init.
+type File struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+	// Mode is an octal string, e.g. 0644
+	Mode string `json:"mode"`
+	// Append to the file
+	// instead of truncating it.
+	Append bool `json:"append"`
+}
+
+// Command is a command executed
+// during init.
+type Command struct {
+	Cmd  string   `json:"cmd"`
+	Args []string `json:"args"`
 }
 
 // Store holds configuration options for managing
@@ -91,10 +215,135 @@ type Store struct {
 	Mount bool `json:"mount"`
 	// Move lower --> rootfs
 	MoveRoot bool `json:"move_root"`
+	// Singletons are services which only
+	// run on the cluster leader.
+	Singletons []string `json:"singletons"`
 	// Environment contains environment variable
 	// overrides for runc apps. This is the primary
 	// way os services are configured at boot.
 	Environment map[string]map[string]string `json:"environment"`
+	// Sockets bind mounts the directory of the RPC
+	// socket into services at the given path so
+	// they can write to the log. The socket should
+	// be in a directory of its own such as
+	// /run/gaffer. GAFFER_ADDRESS is set to the
+	// socket within the container.
+	Sockets map[string]string `json:"sockets"`
+	// DependsOn lists the services each service
+	// depends on. Services are stopped in reverse
+	// dependency order at shutdown.
+	DependsOn map[string][]string `json:"depends_on"`
+	// GracePeriod is the number of seconds a
+	// service is given to exit after SIGTERM
+	// before it is killed.
+	GracePeriod int `json:"grace_period"`
+	// GracePeriods overrides the
+	// grace period of each service.
+	GracePeriods map[string]int `json:"grace_periods"`
+}
+
+// Diagnostics holds options for the bundle
+// collected before a fatal system error.
+type Diagnostics struct {
+	// Dir is a directory bundles are written
+	// to which must be on persistent storage
+	// as the root filesystem is usually a tmpfs.
+	// If empty or not writable the bundle is
+	// written to the log device.
+	Dir string `json:"dir"`
+}
+
+// External is an out of process plugin
+// run by the external plugin. Gaffer either
+// launches the binary at Path or connects
+// to a plugin already serving at Address.
+type External struct {
+	Name string `json:"name"`
+	// Path of the plugin binary
+	Path string   `json:"path"`
+	Args []string `json:"args"`
+	// Address of a running plugin,
+	// e.g. unix:///run/plugin.sock
+	Address string `json:"address"`
+}
+
+// Watchdog holds options for the
+// watchdog plugin.
+type Watchdog struct {
+	// Device is the hardware watchdog
+	// device, it is not used if empty.
+	Device string `json:"device"`
+	// Timeout is the number of seconds without
+	// being pet before the device resets the
+	// system.
+	Timeout int `json:"timeout"`
+	// Threshold is the number of seconds a core
+	// goroutine may stall before Action is taken.
+	Threshold int `json:"threshold"`
+	// Action is one of fatal, reboot or log.
+	Action string `json:"action"`
+}
+
+// Network holds network interface
+// and DNS configuration.
+type Network struct {
+	Interfaces []Interface `json:"interfaces"`
+	// Nameservers are written to
+	// ResolvConf, if empty any DNS
+	// servers leased by DHCP are used.
+	Nameservers []string `json:"nameservers"`
+	// Search domains
+	Search []string `json:"search"`
+	// ResolvConf is the path of the
+	// resolver configuration file.
+	ResolvConf string `json:"resolv_conf"`
+}
+
+// Interface configures a named
+// network interface.
+type Interface struct {
+	Name string `json:"name"`
+	// Addresses in CIDR notation
+	Addresses []string `json:"addresses"`
+	Routes    []Route  `json:"routes"`
+	// DHCP leases an IPv4 address
+	// with the built-in client.
+	DHCP bool `json:"dhcp"`
+}
+
+// Route is a static route through
+// an interface.
+type Route struct {
+	// Destination in CIDR notation,
+	// the default route if empty.
+	Destination string `json:"destination"`
+	Gateway     string `json:"gateway"`
+}
+
+// Remote holds options for selecting the
+// registered hosts RPC calls are made against.
+type Remote struct {
+	// Name is a regular expression
+	// matched against host names.
+	Name string `json:"name"`
+	// IP matches a host IP address.
+	IP string `json:"ip"`
+	// MAC matches a host MAC address.
+	MAC string `json:"mac"`
+	// Labels matches hosts having
+	// all of these labels.
+	Labels map[string]string `json:"labels"`
+	// All matches every registered host.
+	All bool `json:"all"`
+	// Port is the RPC port dialed on
+	// hosts which did not register one.
+	Port int `json:"port"`
+}
+
+// Selected checks if any host
+// selectors are configured.
+func (r Remote) Selected() bool {
+	return r.All || r.Name != "" || r.IP != "" || r.MAC != "" || len(r.Labels) > 0
 }
 
 // Logger holds logger specific options.
@@ -127,12 +376,29 @@ type Logger struct {
 	// rotated log files should be
 	// compressed
 	Compress bool `json:"compress"`
+	// Sinks ship log entries including
+	// captured service output to
+	// remote destinations.
+	Sinks []Sink `json:"sinks"`
 }
 
-func Load(path string, cfg *Config) error {
-	raw, err := ioutil.ReadFile(path)
-	if err != nil {
-		return err
-	}
-	return json.Unmarshal(raw, cfg)
+// Sink is a remote log destination.
+type Sink struct {
+	// Format is syslog for RFC 5424 messages
+	// or json for newline delimited entries.
+	Format string `json:"format"`
+	// Address of the destination such as
+	// udp://host:514, tcp://host:601 or
+	// tls://host:6514. Only syslog may
+	// be sent over UDP.
+	Address string `json:"address"`
+	// CA is the path of a PEM encoded CA
+	// certificate used to verify TLS
+	// destinations instead of the system
+	// roots.
+	CA string `json:"ca"`
+	// Buffer is the number of entries held
+	// while the destination is unreachable.
+	// Further entries are dropped.
+	Buffer int `json:"buffer"`
 }
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, raw string) string {
+	dir, err := ioutil.TempDir("", "gaffer-config")
+	assert.NoError(t, err)
+	path := filepath.Join(dir, "gaffer.json")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(raw), 0644))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	path := writeConfig(t, `{
+	"logger": {"max_size": 5},
+	"endpoints": ["http://10.0.0.1:2379"],
+	"labels": {"role": "edge"}
+}`)
+	defer os.RemoveAll(filepath.Dir(path))
+	cfg := New()
+	sources := Sources{}
+	assert.NoError(t, Load(path, cfg, sources))
+	assert.Equal(t, 5, cfg.Logger.MaxSize)
+	// Values missing from the file keep their defaults
+	assert.Equal(t, Default.Logger.MaxBackups, cfg.Logger.MaxBackups)
+	assert.Equal(t, Default.Logger.Device, cfg.Logger.Device)
+	assert.Len(t, cfg.Init.Stages, len(Default.Init.Stages))
+	assert.Equal(t, []string{"http://10.0.0.1:2379"}, cfg.Endpoints)
+	assert.Equal(t, SourceFile, sources.Of("logger.max_size"))
+	assert.Equal(t, SourceFile, sources.Of("labels"))
+	assert.Equal(t, SourceDefault, sources.Of("logger.device"))
+	// The defaults are not modified
+	assert.Equal(t, 1, Default.Logger.MaxSize)
+	// Unknown fields are only rejected by Decode
+	stale := writeConfig(t, `{"logger": {"max_sise": 5}}`)
+	defer os.RemoveAll(filepath.Dir(stale))
+	assert.NoError(t, Load(stale, New(), nil))
+	raw, err := ioutil.ReadFile(stale)
+	assert.NoError(t, err)
+	assert.Error(t, Decode(raw, New()))
+	bad := writeConfig(t, `{"logger": {"max_size": "5"}}`)
+	defer os.RemoveAll(filepath.Dir(bad))
+	err = Load(bad, New(), nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "logger.max_size must be a JSON number")
+}
+
+func TestValidate(t *testing.T) {
+	plugins := []string{"supervisor", "register", "logger", "metrics", "system", "watchdog"}
+	assert.Empty(t, Validate(*Default, plugins))
+	// Empty policies are left to their defaults
+	cfg := New()
+	cfg.FailurePolicy = ""
+	cfg.Init.Stages = append(cfg.Init.Stages, Stage{Name: "extra"})
+	assert.Empty(t, Validate(*cfg, plugins))
+	cfg = New()
+	cfg.EnabledPlugins = append(cfg.EnabledPlugins, "bogus")
+	cfg.FailurePolicies = map[string]string{"logger": "explode"}
+	cfg.Address = "localhost:10000"
+	cfg.Store.Mount = true
+	cfg.Store.MoveRoot = true
+	cfg.Store.DependsOn = map[string][]string{"a": {"b"}, "b": {"a"}}
+	cfg.Logger.Sinks = []Sink{{Format: "json", Address: "udp://10.0.0.1:514"}}
+	cfg.Network.Interfaces = []Interface{{Name: "eth0", Addresses: []string{"10.0.0.1"}}}
+	cfg.External = []External{{Name: "x", Path: "/bin/x", Address: "unix:///x.sock"}}
+	errs := []string{}
+	for _, err := range Validate(*cfg, plugins) {
+		errs = append(errs, err.Error())
+	}
+	joined := strings.Join(errs, "\n")
+	for _, path := range []string{
+		"enabled_plugins[5]",
+		"failure_policies.logger",
+		"address",
+		"store: mount and move_root",
+		"store.depends_on: dependency cycle [a b a]",
+		"logger.sinks[0].address: only syslog",
+		"network.interfaces[0].addresses[0]",
+		"external[0]: path and address",
+	} {
+		assert.Contains(t, joined, path)
+	}
+	assert.Len(t, errs, 8)
+	cfg = New()
+	cfg.Store.Sockets = map[string]string{"web": "/run/gaffer"}
+	assert.Len(t, Validate(*cfg, plugins), 1)
+	cfg.Address = "unix:///run/gaffer/gaffer.sock"
+	assert.Empty(t, Validate(*cfg, plugins))
+}
+
+func TestSchema(t *testing.T) {
+	schema := Schema()
+	assert.Equal(t, SchemaURI, schema["$schema"])
+	properties := schema["properties"].(map[string]interface{})
+	logger := properties["logger"].(map[string]interface{})
+	assert.Equal(t, false, logger["additionalProperties"])
+	maxSize := logger["properties"].(map[string]interface{})["max_size"]
+	assert.Equal(t, map[string]interface{}{"type": "integer"}, maxSize)
+	labels := properties["labels"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"type": "string"}, labels["additionalProperties"])
+	stages := properties["init"].(map[string]interface{})["properties"].(map[string]interface{})["stages"].(map[string]interface{})
+	assert.Equal(t, "array", stages["type"])
+}
@@ -4,12 +4,38 @@ var Default = &Config{
 	Init: Init{
 		Helper:  "/bin/gaffer-helper",
 		NewRoot: "/mnt",
+		Stages: []Stage{
+			{
+				Name:      "filesystems",
+				OnFailure: "abort",
+				Mounts: []Mount{
+					{
+						Source:  "proc",
+						Target:  "/proc",
+						Type:    "proc",
+						Options: "nodev,nosuid,noexec,relatime",
+					},
+					{
+						Source:  "dev",
+						Target:  "/dev",
+						Type:    "devtmpfs",
+						Options: "nosuid,noexec,relatime,size=10m,nr_inodes=248418,mode=755",
+					},
+				},
+			},
+			{
+				Name:      "network",
+				OnFailure: "continue",
+				Network:   true,
+			},
+		},
 	},
 	Store: Store{
-		MoveRoot:   false,
-		Mount:      false,
-		BasePath:   "/containers",
-		ConfigPath: "/var/mesanine",
+		MoveRoot:    false,
+		Mount:       false,
+		BasePath:    "/containers",
+		ConfigPath:  "/var/mesanine",
+		GracePeriod: 10,
 	},
 	Logger: Logger{
 		JSON:       false,
@@ -20,9 +46,28 @@ var Default = &Config{
 		MaxBackups: 2,
 		Compress:   true,
 	},
+	FailurePolicy: "restart",
+	Diagnostics: Diagnostics{
+		Dir: "",
+	},
+	Watchdog: Watchdog{
+		Device:    "/dev/watchdog",
+		Timeout:   60,
+		Threshold: 30,
+		Action:    "fatal",
+	},
+	Network: Network{
+		ResolvConf: "/etc/resolv.conf",
+	},
+	Remote: Remote{
+		Port: 10000,
+	},
+	Output: Output{
+		Format: "table",
+	},
 	RuncRoot:        "/run/runc",
 	Endpoints:       []string{"http://127.0.0.1:2379"},
-	EnabledPlugins:  []string{"supervisor", "register", "logger", "metrics"},
+	EnabledPlugins:  []string{"supervisor", "register", "logger", "metrics", "system"},
 	DisabledPlugins: []string{},
 	Address:         "unix:///var/run/gaffer.sock",
 }
@@ -0,0 +1,178 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"strings"
+)
+
+// Sources of configuration values from
+// lowest to highest precedence.
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+)
+
+// Sources maps the path of configuration values such
+// as logger.max_size to the source they were set from.
+// Values which are not present are defaults.
+type Sources map[string]string
+
+// Of returns the source of the value at path.
+func (s Sources) Of(path string) string {
+	if source, ok := s[path]; ok {
+		return source
+	}
+	return SourceDefault
+}
+
+// New returns a copy of the default
+// configuration which may be modified.
+func New() *Config {
+	cfg := &Config{}
+	raw, _ := json.Marshal(Default)
+	json.Unmarshal(raw, cfg)
+	return cfg
+}
+
+// Load merges the configuration file at path over cfg
+// recording the path of each value set by the file in
+// sources if it is not nil. Objects are merged key by
+// key while arrays and other values replace those of
+// cfg entirely. Unknown fields are ignored so a stale
+// file does not prevent booting, Decode rejects them.
+func Load(path string, cfg *Config, sources Sources) error {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := decode(raw, &Config{}, false); err != nil {
+		return fmt.Errorf("%s: %s", path, err)
+	}
+	var file map[string]interface{}
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return err
+	}
+	current, err := json.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	var merged map[string]interface{}
+	if err := json.Unmarshal(current, &merged); err != nil {
+		return err
+	}
+	merge(merged, file)
+	raw, err = json.Marshal(merged)
+	if err != nil {
+		return err
+	}
+	loaded := &Config{}
+	if err := json.Unmarshal(raw, loaded); err != nil {
+		return err
+	}
+	*cfg = *loaded
+	if sources != nil {
+		Walk(file, func(path string, value interface{}) {
+			sources[path] = SourceFile
+		})
+	}
+	return nil
+}
+
+// Decode decodes a configuration file
+// rejecting any unknown fields.
+func Decode(raw []byte, cfg *Config) error {
+	return decode(raw, cfg, true)
+}
+
+func decode(raw []byte, cfg *Config, strict bool) error {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	if strict {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(cfg); err != nil {
+		if e, ok := err.(*json.UnmarshalTypeError); ok && e.Field != "" {
+			return fmt.Errorf("%s must be a JSON %s not %s", e.Field, kind(e.Type), e.Value)
+		}
+		return err
+	}
+	return nil
+}
+
+// kind describes a Go type as JSON.
+func kind(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map:
+		return "object"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Ptr:
+		return kind(t.Elem())
+	}
+	return "number"
+}
+
+// merge merges src into dst recursively
+// replacing any values other than objects.
+func merge(dst, src map[string]interface{}) {
+	for key, value := range src {
+		if s, ok := value.(map[string]interface{}); ok {
+			if d, ok := dst[key].(map[string]interface{}); ok {
+				merge(d, s)
+				continue
+			}
+		}
+		dst[key] = value
+	}
+}
+
+// Walk calls fn with the path and value of each
+// configuration value in the decoded JSON object v.
+// Values of maps and arrays such as labels are
+// not walked individually.
+func Walk(v map[string]interface{}, fn func(path string, value interface{})) {
+	walk(reflect.TypeOf(Config{}), v, "", fn)
+}
+
+func walk(t reflect.Type, v map[string]interface{}, prefix string, fn func(string, interface{})) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := jsonName(field)
+		value, ok := v[name]
+		if name == "" || !ok {
+			continue
+		}
+		path := prefix + name
+		ft := field.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if nested, ok := value.(map[string]interface{}); ok && ft.Kind() == reflect.Struct {
+			walk(ft, nested, path+".", fn)
+			continue
+		}
+		fn(path, value)
+	}
+}
+
+// jsonName returns the JSON key of
+// a field or "" if it is not encoded.
+func jsonName(field reflect.StructField) string {
+	tag := strings.Split(field.Tag.Get("json"), ",")[0]
+	if tag == "-" {
+		return ""
+	}
+	if tag == "" {
+		return field.Name
+	}
+	return tag
+}
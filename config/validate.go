@@ -0,0 +1,315 @@
+package config
+
+import (
+	"fmt"
+	"github.com/mesanine/gaffer/user"
+	"net"
+	"net/url"
+	"path"
+	"sort"
+)
+
+// Values accepted by options which are defined
+// by the packages consuming them. They are
+// repeated here as those packages import config.
+var (
+	failurePolicies = []string{"restart", "ignore", "fatal"}
+	stagePolicies   = []string{"abort", "continue", "recovery"}
+	watchdogActions = []string{"fatal", "reboot", "log"}
+	cgroupVersions  = []string{"v1", "v2"}
+	sinkFormats     = []string{"syslog", "json"}
+	outputFormats   = []string{"table", "wide", "json", "yaml", "template"}
+)
+
+// sharedDirs may not hold the RPC socket if
+// its directory is mounted into services.
+var sharedDirs = map[string]bool{"/": true, "/run": true, "/var/run": true, "/tmp": true}
+
+// Validate checks the semantics of cfg returning an
+// error for each problem found. Plugins are the names
+// of every plugin which may be enabled.
+func Validate(cfg Config, plugins []string) []error {
+	v := &validator{}
+	for i, name := range cfg.EnabledPlugins {
+		v.oneOf(fmt.Sprintf("enabled_plugins[%d]", i), name, plugins)
+	}
+	for i, name := range cfg.DisabledPlugins {
+		v.oneOf(fmt.Sprintf("disabled_plugins[%d]", i), name, plugins)
+	}
+	v.optional("failure_policy", cfg.FailurePolicy, failurePolicies)
+	for name, policy := range cfg.FailurePolicies {
+		path := fmt.Sprintf("failure_policies.%s", name)
+		if !contains(plugins, name) {
+			v.errorf(path, "unknown plugin %s", name)
+		}
+		v.optional(path, policy, failurePolicies)
+	}
+	v.address("address", cfg.Address, "unix", "tcp")
+	v.address("http_address", cfg.HTTPAddress, "unix", "tcp")
+	for i, endpoint := range cfg.Endpoints {
+		v.address(fmt.Sprintf("endpoints[%d]", i), endpoint, "http", "https")
+	}
+	if cfg.User != "" {
+		if _, err := user.FromString(cfg.User); err != nil {
+			v.errorf("user", "must be formatted as ID:TOKEN")
+		}
+	}
+	if cfg.Output.Format != "" {
+		v.oneOf("output.format", cfg.Output.Format, outputFormats)
+	}
+	if cfg.Output.Format == "template" && cfg.Output.Template == "" {
+		v.errorf("output.template", "is required by the template output format")
+	}
+	if cfg.Remote.Port < 0 || cfg.Remote.Port > 65535 {
+		v.errorf("remote.port", "%d is not a valid port", cfg.Remote.Port)
+	}
+	v.init(cfg.Init)
+	v.store(cfg.Store, cfg.Address)
+	v.logger(cfg.Logger)
+	v.network(cfg.Network)
+	if contains(cfg.Plugins(), "watchdog") {
+		v.oneOf("watchdog.action", cfg.Watchdog.Action, watchdogActions)
+		if cfg.Watchdog.Threshold <= 0 {
+			v.errorf("watchdog.threshold", "must be greater than zero")
+		}
+		if cfg.Watchdog.Device != "" && cfg.Watchdog.Timeout <= cfg.Watchdog.Threshold {
+			v.errorf("watchdog.timeout", "must be greater than the threshold")
+		}
+	}
+	names := map[string]bool{}
+	for i, ext := range cfg.External {
+		path := fmt.Sprintf("external[%d]", i)
+		if ext.Name == "" {
+			v.errorf(path+".name", "is required")
+		} else if names[ext.Name] {
+			v.errorf(path+".name", "%s is declared more than once", ext.Name)
+		}
+		names[ext.Name] = true
+		switch {
+		case ext.Path == "" && ext.Address == "":
+			v.errorf(path, "requires a path or an address")
+		case ext.Path != "" && ext.Address != "":
+			v.errorf(path, "path and address are mutually exclusive")
+		case ext.Address != "":
+			v.address(path+".address", ext.Address, "unix", "tcp")
+		}
+	}
+	return v.errs
+}
+
+type validator struct {
+	errs []error
+}
+
+func (v *validator) errorf(path, format string, args ...interface{}) {
+	v.errs = append(v.errs, fmt.Errorf("%s: %s", path, fmt.Sprintf(format, args...)))
+}
+
+func (v *validator) oneOf(path, value string, values []string) {
+	if !contains(values, value) {
+		v.errorf(path, "unknown value %q, must be one of %v", value, values)
+	}
+}
+
+// optional checks a value which
+// is left to its consumer's default
+// if it is empty.
+func (v *validator) optional(path, value string, values []string) {
+	if value != "" {
+		v.oneOf(path, value, values)
+	}
+}
+
+// address checks an optional URL
+// has one of the given schemes.
+func (v *validator) address(path, addr string, schemes ...string) {
+	if addr == "" {
+		return
+	}
+	u, err := url.Parse(addr)
+	if err != nil {
+		v.errorf(path, "%s", err.Error())
+		return
+	}
+	if !contains(schemes, u.Scheme) {
+		v.errorf(path, "bad address %s, the scheme must be one of %v", addr, schemes)
+		return
+	}
+	if u.Scheme == "unix" && u.Path == "" || u.Scheme != "unix" && u.Host == "" {
+		v.errorf(path, "bad address %s", addr)
+	}
+}
+
+func (v *validator) cidr(path, value string) {
+	if _, _, err := net.ParseCIDR(value); err != nil {
+		v.errorf(path, "%s is not in CIDR notation", value)
+	}
+}
+
+func (v *validator) init(cfg Init) {
+	for i, stage := range cfg.Stages {
+		path := fmt.Sprintf("init.stages[%d]", i)
+		v.optional(path+".on_failure", stage.OnFailure, stagePolicies)
+		if stage.Hostname != "" && stage.HostnamePrefix != "" {
+			v.errorf(path, "hostname and hostname_prefix are mutually exclusive")
+		}
+		if stage.Cgroups != nil {
+			v.optional(path+".cgroups.version", stage.Cgroups.Version, cgroupVersions)
+		}
+		for j, file := range stage.Files {
+			if file.Path == "" {
+				v.errorf(fmt.Sprintf("%s.files[%d].path", path, j), "is required")
+			}
+		}
+	}
+}
+
+func (v *validator) store(cfg Store, address string) {
+	if cfg.Mount && cfg.MoveRoot {
+		v.errorf("store", "mount and move_root are mutually exclusive")
+	}
+	seen := map[string]bool{}
+	for i, name := range cfg.Singletons {
+		if seen[name] {
+			v.errorf(fmt.Sprintf("store.singletons[%d]", i), "%s is declared more than once", name)
+		}
+		seen[name] = true
+	}
+	if len(cfg.Sockets) > 0 {
+		if u, err := url.Parse(address); err != nil || u.Scheme != "unix" {
+			v.errorf("store.sockets", "requires a unix RPC address")
+		} else if sharedDirs[path.Dir(u.Path)] {
+			v.errorf("store.sockets", "the RPC socket %s must be in a directory of its own", u.Path)
+		}
+	}
+	if cfg.GracePeriod < 0 {
+		v.errorf("store.grace_period", "must not be negative")
+	}
+	for name, period := range cfg.GracePeriods {
+		if period < 0 {
+			v.errorf("store.grace_periods."+name, "must not be negative")
+		}
+	}
+	if cycle := findCycle(cfg.DependsOn); cycle != nil {
+		v.errorf("store.depends_on", "dependency cycle %v", cycle)
+	}
+}
+
+func (v *validator) logger(cfg Logger) {
+	if cfg.MaxSize < 0 {
+		v.errorf("logger.max_size", "must not be negative")
+	}
+	if cfg.MaxBackups < 0 {
+		v.errorf("logger.max_backups", "must not be negative")
+	}
+	for i, sink := range cfg.Sinks {
+		path := fmt.Sprintf("logger.sinks[%d]", i)
+		v.oneOf(path+".format", sink.Format, sinkFormats)
+		if sink.Address == "" {
+			v.errorf(path+".address", "is required")
+			continue
+		}
+		v.address(path+".address", sink.Address, "udp", "tcp", "tls")
+		u, err := url.Parse(sink.Address)
+		if err != nil {
+			continue
+		}
+		if u.Scheme == "udp" && sink.Format != "syslog" {
+			v.errorf(path+".address", "only syslog may be sent over udp")
+		}
+		if sink.CA != "" && u.Scheme != "tls" {
+			v.errorf(path+".ca", "is only used by tls addresses")
+		}
+	}
+}
+
+func (v *validator) network(cfg Network) {
+	names := map[string]bool{}
+	for i, iface := range cfg.Interfaces {
+		path := fmt.Sprintf("network.interfaces[%d]", i)
+		if iface.Name == "" {
+			v.errorf(path+".name", "is required")
+		} else if names[iface.Name] {
+			v.errorf(path+".name", "%s is declared more than once", iface.Name)
+		}
+		names[iface.Name] = true
+		for j, addr := range iface.Addresses {
+			v.cidr(fmt.Sprintf("%s.addresses[%d]", path, j), addr)
+		}
+		for j, route := range iface.Routes {
+			if route.Destination != "" {
+				v.cidr(fmt.Sprintf("%s.routes[%d].destination", path, j), route.Destination)
+			}
+			if route.Gateway != "" && net.ParseIP(route.Gateway) == nil {
+				v.errorf(fmt.Sprintf("%s.routes[%d].gateway", path, j), "%q is not an IP address", route.Gateway)
+			}
+		}
+	}
+	for i, ns := range cfg.Nameservers {
+		if net.ParseIP(ns) == nil {
+			v.errorf(fmt.Sprintf("network.nameservers[%d]", i), "%q is not an IP address", ns)
+		}
+	}
+}
+
+// findCycle returns the services forming a
+// cycle in deps or nil if there are none.
+func findCycle(deps map[string][]string) []string {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := map[string]int{}
+	var (
+		stack []string
+		cycle []string
+		visit func(string) bool
+	)
+	visit = func(name string) bool {
+		switch state[name] {
+		case visiting:
+			for i, other := range stack {
+				if other == name {
+					cycle = append(append([]string{}, stack[i:]...), name)
+				}
+			}
+			return true
+		case visited:
+			return false
+		}
+		state[name] = visiting
+		stack = append(stack, name)
+		for _, dep := range deps[name] {
+			if visit(dep) {
+				return true
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[name] = visited
+		return false
+	}
+	for _, name := range sortedKeys(deps) {
+		if visit(name) {
+			return cycle
+		}
+	}
+	return nil
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := []string{}
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func contains(values []string, value string) bool {
+	for _, other := range values {
+		if other == value {
+			return true
+		}
+	}
+	return false
+}
@@ -1,12 +1,21 @@
 package event
 
 import (
+	"encoding/json"
+	"github.com/mesanine/gaffer/fatal"
+	"github.com/mesanine/gaffer/liveness"
 	"github.com/mesanine/gaffer/log"
 	"go.uber.org/zap"
+	"sync"
+	"time"
 )
 
 const BufferSize = 128
 
+// RecentEvents is the number of broadcasted
+// events kept for crash diagnostics.
+const RecentEvents = 100
+
 type Subscriber struct {
 	e chan Event
 }
@@ -37,18 +46,26 @@ func (s Subscriber) Chan() <-chan Event { return s.e }
 // The bus is only as fast as the slowest
 // Subscriber.
 type EventBus struct {
-	running     bool
-	shutdown    chan bool
+	running  bool
+	shutdown chan bool
+	// done is closed once the
+	// EventBus has stopped.
+	done        chan struct{}
 	events      chan Event
 	subscribe   chan Subscriber
 	unsubscribe chan Subscriber
 	subscribers map[Subscriber]bool
+	mu          sync.Mutex
+	// recent holds the most recently
+	// broadcasted events.
+	recent []Event
 }
 
 func NewEventBus() *EventBus {
 	return &EventBus{
 		events:      make(chan Event, BufferSize),
 		shutdown:    make(chan bool),
+		done:        make(chan struct{}),
 		subscribe:   make(chan Subscriber),
 		unsubscribe: make(chan Subscriber),
 		subscribers: map[Subscriber]bool{},
@@ -56,6 +73,12 @@ func NewEventBus() *EventBus {
 }
 
 func (b *EventBus) broadcast(e Event) {
+	b.mu.Lock()
+	b.recent = append(b.recent, e)
+	if len(b.recent) > RecentEvents {
+		b.recent = b.recent[1:]
+	}
+	b.mu.Unlock()
 	// Range each subscriber and attempt
 	// to publish the event to it.
 	log.Log.Debug(
@@ -75,9 +98,14 @@ func (b *EventBus) broadcast(e Event) {
 }
 
 func (b *EventBus) run() {
+	ticker := time.NewTicker(liveness.Interval)
+	defer ticker.Stop()
+	defer liveness.Done("eventbus")
 loop:
 	for {
+		liveness.Beat("eventbus")
 		select {
+		case <-ticker.C:
 		case <-b.shutdown:
 			break loop
 		case event := <-b.events:
@@ -85,19 +113,48 @@ loop:
 		case sub := <-b.subscribe:
 			b.subscribers[sub] = true
 		case sub := <-b.unsubscribe:
-			delete(b.subscribers, sub)
+			if b.subscribers[sub] {
+				delete(b.subscribers, sub)
+				close(sub.e)
+			}
 		}
 	}
 }
 
 // Subscribe adds a new subscriber to the EventBus
 func (b *EventBus) Subscribe(sub Subscriber) {
-	b.subscribe <- sub
+	select {
+	case b.subscribe <- sub:
+	case <-b.done:
+	}
 }
 
 // Unsubscribe removes a subscriber from the EventBus
+// and closes its channel. Subscribers must unsubscribe
+// once they stop reading events or the EventBus blocks
+// when their buffer is full. Events sent while it is
+// removed are discarded.
 func (b *EventBus) Unsubscribe(sub Subscriber) {
-	b.unsubscribe <- sub
+	// Drain the subscriber in case the EventBus
+	// is blocked broadcasting to it.
+	stop := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case _, ok := <-sub.e:
+				if !ok {
+					return
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+	select {
+	case b.unsubscribe <- sub:
+	case <-b.done:
+	}
+	close(stop)
 }
 
 // Push a new event into the EventBus, it will be
@@ -111,9 +168,23 @@ func (b *EventBus) Start() {
 		return
 	}
 	b.running = true
+	select {
+	case <-b.done:
+		b.done = make(chan struct{})
+	default:
+	}
+	fatal.Register("events.json", b.diagnostics)
 	go b.run()
 }
 
+// diagnostics encodes the
+// recent events as JSON.
+func (b *EventBus) diagnostics() ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return json.MarshalIndent(b.recent, "", "  ")
+}
+
 func (b *EventBus) Stop() {
 	if !b.running {
 		return
@@ -121,6 +192,8 @@ func (b *EventBus) Stop() {
 	b.shutdown <- true
 	for sub, _ := range b.subscribers {
 		close(sub.e)
+		delete(b.subscribers, sub)
 	}
+	close(b.done)
 	b.running = false
 }
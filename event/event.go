@@ -1,6 +1,9 @@
 package event
 
 import (
+	"encoding/json"
+	"github.com/mesanine/gaffer/host"
+	"github.com/mesanine/gaffer/service"
 	"time"
 )
 
@@ -15,10 +18,30 @@ const (
 	SERVICE_STARTED = EventType("SERVICE_STARTED")
 	// Service has exited
 	SERVICE_EXITED = EventType("SERVICE_EXITED")
+	// Service was stopped deliberately
+	SERVICE_STOPPED = EventType("SERVICE_STOPPED")
 	// Request service metrics
 	REQUEST_METRICS = EventType("REQUEST_METRICS")
 	// Broadcasted runtime metrics
 	SERVICE_METRICS = EventType("SERVICE_METRICS")
+	// Host registered with the cluster
+	HOST_JOINED = EventType("HOST_JOINED")
+	// Host registration changed
+	HOST_UPDATED = EventType("HOST_UPDATED")
+	// Host registration was removed or expired
+	HOST_LEFT = EventType("HOST_LEFT")
+	// This host became the cluster leader
+	LEADER_ELECTED = EventType("LEADER_ELECTED")
+	// This host is no longer the cluster leader
+	LEADER_LOST = EventType("LEADER_LOST")
+	// Request the network state
+	REQUEST_NETWORK = EventType("REQUEST_NETWORK")
+	// This host has a usable address
+	NETWORK_UP = EventType("NETWORK_UP")
+	// The kernel OOM killer killed a process
+	KERNEL_OOM = EventType("KERNEL_OOM")
+	// A process was killed by a segfault
+	KERNEL_SEGFAULT = EventType("KERNEL_SEGFAULT")
 )
 
 func New(et EventType, opts ...Option) Event {
@@ -31,3 +54,26 @@ func New(et EventType, opts ...Option) Event {
 	}
 	return evt
 }
+
+// MarshalJSON encodes the stats and spec
+// as nested objects rather than the base64
+// encoding of their bytes.
+func (e Event) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Id      string          `json:"id,omitempty"`
+		Type    string          `json:"type,omitempty"`
+		Time    int64           `json:"time,omitempty"`
+		Stats   json.RawMessage `json:"stats,omitempty"`
+		Spec    json.RawMessage `json:"spec,omitempty"`
+		Host    *host.Host      `json:"host,omitempty"`
+		Message string          `json:"message,omitempty"`
+	}{
+		Id:      e.Id,
+		Type:    e.Type,
+		Time:    e.Time,
+		Stats:   service.RawJSON(e.Stats),
+		Spec:    service.RawJSON(e.Spec),
+		Host:    e.Host,
+		Message: e.Message,
+	})
+}
@@ -15,6 +15,7 @@ package event
 import proto "github.com/golang/protobuf/proto"
 import fmt "fmt"
 import math "math"
+import host "github.com/mesanine/gaffer/host"
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ = proto.Marshal
@@ -38,6 +39,11 @@ type Event struct {
 	Stats []byte `protobuf:"bytes,4,opt,name=stats,proto3" json:"stats,omitempty"`
 	// JSON encoded service spec
 	Spec []byte `protobuf:"bytes,5,opt,name=spec,proto3" json:"spec,omitempty"`
+	// Optional host the event relates to
+	Host *host.Host `protobuf:"bytes,6,opt,name=host" json:"host,omitempty"`
+	// Optional description such as
+	// the message which caused it
+	Message string `protobuf:"bytes,7,opt,name=message" json:"message,omitempty"`
 }
 
 func (m *Event) Reset()                    { *m = Event{} }
@@ -80,6 +86,20 @@ func (m *Event) GetSpec() []byte {
 	return nil
 }
 
+func (m *Event) GetHost() *host.Host {
+	if m != nil {
+		return m.Host
+	}
+	return nil
+}
+
+func (m *Event) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Event)(nil), "event.Event")
 }
@@ -87,15 +107,18 @@ func init() {
 func init() { proto.RegisterFile("github.com/mesanine/gaffer/event/event.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 153 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x24, 0x8d, 0x4d, 0x0a, 0xc2, 0x30,
-	0x10, 0x46, 0x49, 0xdb, 0x08, 0x06, 0x71, 0x11, 0x5c, 0x64, 0x59, 0x5c, 0x65, 0x21, 0xcd, 0xc2,
-	0x33, 0x78, 0x81, 0xdc, 0x20, 0x6d, 0xa7, 0x75, 0x16, 0xf9, 0xa1, 0x19, 0x05, 0x6f, 0x2f, 0x9d,
-	0x6e, 0x86, 0xf7, 0x1e, 0x03, 0x9f, 0x7a, 0xac, 0x48, 0xef, 0xcf, 0x38, 0x4c, 0x39, 0xba, 0x08,
-	0x35, 0x24, 0x4c, 0xe0, 0xd6, 0xb0, 0x2c, 0xb0, 0x39, 0xf8, 0x42, 0xa2, 0xe3, 0x0e, 0x65, 0xcb,
-	0x94, 0xb5, 0x64, 0xb9, 0xa3, 0x92, 0xaf, 0x1d, 0xf4, 0x55, 0x35, 0x38, 0x1b, 0xd1, 0x0b, 0x7b,
-	0xf6, 0x0d, 0xce, 0x5a, 0xab, 0x8e, 0x7e, 0x05, 0x4c, 0xc3, 0x85, 0x99, 0x1b, 0x46, 0x30, 0x6d,
-	0x2f, 0x6c, 0xeb, 0x99, 0xf5, 0x4d, 0xc9, 0x4a, 0x81, 0xaa, 0xe9, 0x7a, 0x61, 0x2f, 0xfe, 0x90,
-	0xfd, 0xb3, 0x16, 0x98, 0x8c, 0xe4, 0xc8, 0x3c, 0x9e, 0x78, 0xf8, 0xf9, 0x0f, 0x00, 0x00, 0xff,
-	0xff, 0x65, 0x02, 0xad, 0x66, 0xa8, 0x00, 0x00, 0x00,
+	// 200 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x8e, 0x41, 0x4b, 0xc4, 0x30,
+	0x10, 0x85, 0x49, 0xb7, 0xd9, 0xc5, 0x51, 0x3c, 0x04, 0x0f, 0x83, 0x07, 0x09, 0x9e, 0x82, 0x48,
+	0x0b, 0xfa, 0x1b, 0x04, 0xcf, 0xf9, 0x07, 0xd9, 0xdd, 0xd9, 0x36, 0x87, 0x34, 0xa5, 0x33, 0x0a,
+	0xfe, 0x1e, 0xff, 0xa8, 0x74, 0x8a, 0xd7, 0xbd, 0x0c, 0xef, 0x7d, 0xcc, 0xe3, 0x3d, 0x78, 0x1d,
+	0xb2, 0x8c, 0x5f, 0xc7, 0xee, 0x54, 0x4b, 0x5f, 0x88, 0xd3, 0x94, 0x27, 0xea, 0x87, 0x74, 0xb9,
+	0xd0, 0xd2, 0xd3, 0x37, 0x4d, 0xb2, 0xdd, 0x6e, 0x5e, 0xaa, 0x54, 0x67, 0xd5, 0x3c, 0xbe, 0x5c,
+	0x09, 0x8d, 0x95, 0x45, 0xcf, 0x16, 0x79, 0xfe, 0x35, 0x60, 0x3f, 0xd6, 0x94, 0xbb, 0x87, 0x26,
+	0x9f, 0xd1, 0x78, 0x13, 0x6e, 0x62, 0x93, 0xcf, 0xce, 0x41, 0x2b, 0x3f, 0x33, 0x61, 0xa3, 0x44,
+	0xb5, 0xb2, 0x5c, 0x08, 0x77, 0xde, 0x84, 0x5d, 0x54, 0xed, 0x1e, 0xc0, 0xb2, 0x24, 0x61, 0x6c,
+	0xbd, 0x09, 0x77, 0x71, 0x33, 0xeb, 0x27, 0xcf, 0x74, 0x42, 0xab, 0x50, 0xb5, 0x7b, 0x82, 0x76,
+	0x6d, 0xc6, 0xbd, 0x37, 0xe1, 0xf6, 0x0d, 0x3a, 0x9d, 0xf1, 0x59, 0x59, 0xa2, 0x72, 0x87, 0x70,
+	0x28, 0xc4, 0x9c, 0x06, 0xc2, 0x83, 0x96, 0xfe, 0xdb, 0xe3, 0x5e, 0xc7, 0xbe, 0xff, 0x0d, 0x00,
+	0xac, 0xea, 0x5f, 0x77, 0x0f, 0x01, 0x00, 0x00,
 }
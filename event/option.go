@@ -3,6 +3,7 @@ package event
 import (
 	"encoding/json"
 	"github.com/containerd/go-runc"
+	"github.com/mesanine/gaffer/host"
 )
 
 // Option modifies an event
@@ -12,11 +13,13 @@ type Option func(Event) Event
 func WithID(id string) Option {
 	return func(e Event) Event {
 		return Event{
-			Id:    id,
-			Type:  e.Type,
-			Time:  e.Time,
-			Stats: e.Stats,
-			Spec:  e.Spec,
+			Id:      id,
+			Type:    e.Type,
+			Time:    e.Time,
+			Stats:   e.Stats,
+			Spec:    e.Spec,
+			Host:    e.Host,
+			Message: e.Message,
 		}
 	}
 }
@@ -25,11 +28,41 @@ func WithStats(stats runc.Stats) Option {
 	return func(e Event) Event {
 		raw, _ := json.Marshal(stats)
 		return Event{
-			Stats: raw,
-			Id:    e.Id,
-			Type:  e.Type,
-			Time:  e.Time,
-			Spec:  e.Spec,
+			Stats:   raw,
+			Id:      e.Id,
+			Type:    e.Type,
+			Time:    e.Time,
+			Spec:    e.Spec,
+			Host:    e.Host,
+			Message: e.Message,
+		}
+	}
+}
+
+func WithHost(h *host.Host) Option {
+	return func(e Event) Event {
+		return Event{
+			Host:    h,
+			Id:      e.Id,
+			Type:    e.Type,
+			Time:    e.Time,
+			Stats:   e.Stats,
+			Spec:    e.Spec,
+			Message: e.Message,
+		}
+	}
+}
+
+func WithMessage(msg string) Option {
+	return func(e Event) Event {
+		return Event{
+			Message: msg,
+			Id:      e.Id,
+			Type:    e.Type,
+			Time:    e.Time,
+			Stats:   e.Stats,
+			Spec:    e.Spec,
+			Host:    e.Host,
 		}
 	}
 }
@@ -0,0 +1,210 @@
+package fatal
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"github.com/mesanine/gaffer/config"
+	"github.com/mesanine/gaffer/log"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CollectTimeout is how long each collector is
+// given since the system may be wedged.
+const CollectTimeout = 5 * time.Second
+
+// Collector returns diagnostic data
+// to include in the crash bundle.
+type Collector func() ([]byte, error)
+
+var (
+	mu         sync.Mutex
+	collectors = map[string]Collector{}
+	// dir is where bundles are written
+	dir string
+	// device is the log device bundles are
+	// written to if dir is not writable.
+	device string
+)
+
+func init() {
+	Register("goroutines.txt", goroutines)
+	Register("log.json", func() ([]byte, error) {
+		return []byte(strings.Join(log.Recent(), "")), nil
+	})
+	Register("mounts.txt", func() ([]byte, error) {
+		return ioutil.ReadFile("/proc/mounts")
+	})
+	Register("meminfo.txt", func() ([]byte, error) {
+		return ioutil.ReadFile("/proc/meminfo")
+	})
+}
+
+// Setup configures where
+// bundles are written.
+func Setup(cfg config.Config) {
+	mu.Lock()
+	defer mu.Unlock()
+	dir = cfg.Diagnostics.Dir
+	device = cfg.Logger.Device
+}
+
+// Register adds a collector whose output is written
+// to the named file in the bundle replacing any
+// collector already registered with the name.
+func Register(name string, collector Collector) {
+	mu.Lock()
+	defer mu.Unlock()
+	collectors[name] = collector
+}
+
+// Bundle runs every collector and returns a
+// gzipped tar archive of their output.
+func Bundle() ([]byte, error) {
+	mu.Lock()
+	names := []string{}
+	for name := range collectors {
+		names = append(names, name)
+	}
+	copied := map[string]Collector{}
+	for name, collector := range collectors {
+		copied[name] = collector
+	}
+	mu.Unlock()
+	sort.Strings(names)
+	buf := bytes.NewBuffer(nil)
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+	now := time.Now()
+	for _, name := range names {
+		raw := collect(copied[name])
+		err := tw.WriteHeader(&tar.Header{
+			Name:    name,
+			Mode:    0644,
+			Size:    int64(len(raw)),
+			ModTime: now,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write(raw); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteBundle writes a bundle to the configured
+// directory and returns its path. If that fails
+// the bundle is written to the log device
+// as a framed base64 blob.
+func WriteBundle() (string, error) {
+	raw, err := Bundle()
+	if err != nil {
+		return "", err
+	}
+	mu.Lock()
+	dir, device := dir, device
+	mu.Unlock()
+	if dir != "" {
+		path := filepath.Join(dir, fmt.Sprintf("gaffer-crash-%d.tar.gz", time.Now().Unix()))
+		err = os.MkdirAll(dir, 0755)
+		if err == nil {
+			err = ioutil.WriteFile(path, raw, 0644)
+		}
+		if err == nil {
+			syncFile(path)
+			return path, nil
+		}
+		log.Log.Error(fmt.Sprintf("could not write diagnostics bundle to %s: %s", path, err.Error()))
+	}
+	if device == "" {
+		return "", fmt.Errorf("no diagnostics directory or log device is configured")
+	}
+	fd, err := os.OpenFile(device, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return "", err
+	}
+	defer fd.Close()
+	if _, err := fd.Write(Frame(raw)); err != nil {
+		return "", err
+	}
+	return device, nil
+}
+
+// Frame encodes a bundle as base64 between
+// BEGIN and END lines so it can be extracted
+// from console output.
+func Frame(raw []byte) []byte {
+	buf := bytes.NewBufferString("\n-----BEGIN GAFFER DIAGNOSTICS-----\n")
+	encoded := base64.StdEncoding.EncodeToString(raw)
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76] + "\n")
+		encoded = encoded[76:]
+	}
+	if encoded != "" {
+		buf.WriteString(encoded + "\n")
+	}
+	buf.WriteString("-----END GAFFER DIAGNOSTICS-----\n")
+	return buf.Bytes()
+}
+
+// collect runs the collector returning any
+// error or timeout as the file content.
+func collect(collector Collector) []byte {
+	type result struct {
+		raw []byte
+		err error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		raw, err := collector()
+		ch <- result{raw, err}
+	}()
+	select {
+	case r := <-ch:
+		if r.err != nil {
+			return []byte(fmt.Sprintf("error: %s\n", r.err.Error()))
+		}
+		return r.raw
+	case <-time.After(CollectTimeout):
+		return []byte(fmt.Sprintf("error: timed out after %s\n", CollectTimeout))
+	}
+}
+
+// goroutines dumps the stack
+// of every goroutine.
+func goroutines() ([]byte, error) {
+	buf := make([]byte, 1<<20)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n], nil
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+func syncFile(path string) {
+	fd, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	fd.Sync()
+	fd.Close()
+}
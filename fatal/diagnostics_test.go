@@ -0,0 +1,34 @@
+package fatal
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"testing"
+)
+
+func TestBundle(t *testing.T) {
+	Register("ok.txt", func() ([]byte, error) { return []byte("ok"), nil })
+	Register("bad.txt", func() ([]byte, error) { return nil, fmt.Errorf("bad") })
+	raw, err := Bundle()
+	assert.NoError(t, err)
+	gz, err := gzip.NewReader(bytes.NewReader(raw))
+	assert.NoError(t, err)
+	files := map[string]string{}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			break
+		}
+		content, _ := ioutil.ReadAll(tr)
+		files[hdr.Name] = string(content)
+	}
+	assert.Equal(t, "ok", files["ok.txt"])
+	assert.Equal(t, "error: bad\n", files["bad.txt"])
+	assert.Contains(t, files["goroutines.txt"], "TestBundle")
+	assert.Contains(t, string(Frame(raw)), "-----BEGIN GAFFER DIAGNOSTICS-----")
+}
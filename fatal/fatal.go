@@ -1,6 +1,7 @@
 package fatal
 
 import (
+	"fmt"
 	"github.com/mesanine/gaffer/log"
 	"os"
 	"time"
@@ -22,11 +23,21 @@ https://blog.acolyer.org/2017/06/15/gray-failure-the-achilles-heel-of-cloud-scal
 // at once.
 var FailHard bool
 
-// Fatal will cause a kernel panic
-// if FailHard is true.
+// Fatal will write a diagnostics bundle
+// and cause a kernel panic if FailHard
+// is true.
 func Fatal() {
 	if FailHard {
 		log.Log.Error(msg)
+		// Collect diagnostics while the
+		// system is still running.
+		path, err := WriteBundle()
+		if err != nil {
+			log.Log.Error(fmt.Sprintf("failed to write diagnostics bundle: %s", err.Error()))
+		} else {
+			log.Log.Error(fmt.Sprintf("wrote diagnostics bundle to %s", path))
+		}
+		log.Log.Sync()
 		time.Sleep(10 * time.Second)
 		if os.Getuid() == 0 {
 			fd, _ := os.OpenFile("/proc/sysrq-trigger", os.O_WRONLY, 0)
@@ -1,31 +1,73 @@
 package host
 
 import (
+	"bufio"
 	"fmt"
 	"net"
 	"os"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type Filter func(*Host) bool
 
 type Hosts []*Host
 
+// Filter returns the hosts matching every filter,
+// all hosts match if no filters are given.
 func (hosts Hosts) Filter(filters ...Filter) Hosts {
 	matched := Hosts{}
 loop:
 	for _, host := range hosts {
 		for _, filter := range filters {
-			if filter(host) {
-				matched = append(matched, host)
+			if !filter(host) {
 				continue loop
 			}
 		}
+		matched = append(matched, host)
 	}
 	return matched
 }
 
+// Table returns the hosts as rows of a table.
+func (hosts Hosts) Table(wide bool) ([]string, [][]string) {
+	headers := []string{"NAME", "ADDRESS", "LEADER", "SERVICES", "LABELS"}
+	if wide {
+		headers = append(headers, "MAC", "PORT", "VERSION", "BOOTED")
+	}
+	rows := [][]string{}
+	for _, h := range hosts {
+		services := "-"
+		if h.Services != nil {
+			services = fmt.Sprintf("%d/%d", h.Services.Running, h.Services.Total)
+		}
+		row := []string{h.Name, h.Address, strconv.FormatBool(h.Leader), services, FormatLabels(h.Labels)}
+		if wide {
+			booted := "-"
+			if h.BootTime > 0 {
+				booted = time.Unix(h.BootTime, 0).UTC().Format(time.RFC3339)
+			}
+			row = append(row, h.Mac, strconv.Itoa(int(h.Port)), h.Version, booted)
+		}
+		rows = append(rows, row)
+	}
+	return headers, rows
+}
+
+// FormatLabels formats labels as sorted
+// KEY=VALUE pairs separated by commas.
+func FormatLabels(labels map[string]string) string {
+	pairs := []string{}
+	for key, value := range labels {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", key, value))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
 func Any() Filter {
 	return func(*Host) bool { return true }
 }
@@ -48,6 +90,54 @@ func ByMAC(mac string) Filter {
 	}
 }
 
+// ByLabels matches hosts having
+// all of the given labels.
+func ByLabels(labels map[string]string) Filter {
+	return func(h *Host) bool {
+		for key, value := range labels {
+			if v, ok := h.Labels[key]; !ok || v != value {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// ParseLabels parses labels
+// formatted as KEY=VALUE.
+func ParseLabels(pairs []string) (map[string]string, error) {
+	labels := map[string]string{}
+	for _, pair := range pairs {
+		split := strings.SplitN(pair, "=", 2)
+		if len(split) != 2 || split[0] == "" {
+			return nil, fmt.Errorf("bad label %s", pair)
+		}
+		labels[split[0]] = split[1]
+	}
+	return labels, nil
+}
+
+// BootTime returns the epoch time the
+// system booted at from /proc/stat.
+func BootTime() (int64, error) {
+	fd, err := os.Open("/proc/stat")
+	if err != nil {
+		return 0, err
+	}
+	defer fd.Close()
+	scanner := bufio.NewScanner(fd)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "btime" {
+			return strconv.ParseInt(fields[1], 10, 64)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("cannot detect boot time")
+}
+
 func Self() (*Host, error) {
 	host := &Host{}
 	name, err := os.Hostname()
@@ -92,6 +182,23 @@ func Self() (*Host, error) {
 	return nil, fmt.Errorf("cannot detect ip address")
 }
 
+// Check returns an error if the host an
+// RPC request was addressed to is not
+// this host. Nil hosts always pass.
+func Check(h *Host) error {
+	if h == nil || h.Mac == "" {
+		return nil
+	}
+	self, err := Self()
+	if err != nil {
+		return err
+	}
+	if self.Mac != h.Mac {
+		return fmt.Errorf("request for host %s (%s) reached %s (%s)", h.Name, h.Mac, self.Name, self.Mac)
+	}
+	return nil
+}
+
 func SelfMust() *Host {
 	host, err := Self()
 	if err != nil {
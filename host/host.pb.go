@@ -9,6 +9,7 @@ It is generated from these files:
 
 It has these top-level messages:
 	Host
+	Services
 */
 package host
 
@@ -37,6 +38,18 @@ type Host struct {
 	Mac string `protobuf:"bytes,3,opt,name=mac" json:"mac,omitempty"`
 	// RPC port
 	Port int32 `protobuf:"varint,4,opt,name=port" json:"port,omitempty"`
+	// Gaffer version
+	Version string `protobuf:"bytes,5,opt,name=version" json:"version,omitempty"`
+	// Gaffer git SHA
+	GitSha string `protobuf:"bytes,6,opt,name=git_sha,json=gitSha" json:"git_sha,omitempty"`
+	// Epoch time the host booted
+	BootTime int64 `protobuf:"varint,7,opt,name=boot_time,json=bootTime" json:"boot_time,omitempty"`
+	// Arbitrary key/value labels
+	Labels map[string]string `protobuf:"bytes,8,rep,name=labels" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Summary of service states
+	Services *Services `protobuf:"bytes,9,opt,name=services" json:"services,omitempty"`
+	// Host is the cluster leader
+	Leader bool `protobuf:"varint,10,opt,name=leader" json:"leader,omitempty"`
 }
 
 func (m *Host) Reset()                    { *m = Host{} }
@@ -72,21 +85,110 @@ func (m *Host) GetPort() int32 {
 	return 0
 }
 
+func (m *Host) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *Host) GetGitSha() string {
+	if m != nil {
+		return m.GitSha
+	}
+	return ""
+}
+
+func (m *Host) GetBootTime() int64 {
+	if m != nil {
+		return m.BootTime
+	}
+	return 0
+}
+
+func (m *Host) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+func (m *Host) GetServices() *Services {
+	if m != nil {
+		return m.Services
+	}
+	return nil
+}
+
+func (m *Host) GetLeader() bool {
+	if m != nil {
+		return m.Leader
+	}
+	return false
+}
+
+// Services counts the states of
+// services running on a host.
+type Services struct {
+	Total   int32 `protobuf:"varint,1,opt,name=total" json:"total,omitempty"`
+	Running int32 `protobuf:"varint,2,opt,name=running" json:"running,omitempty"`
+	Failed  int32 `protobuf:"varint,3,opt,name=failed" json:"failed,omitempty"`
+}
+
+func (m *Services) Reset()                    { *m = Services{} }
+func (m *Services) String() string            { return proto.CompactTextString(m) }
+func (*Services) ProtoMessage()               {}
+func (*Services) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *Services) GetTotal() int32 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+func (m *Services) GetRunning() int32 {
+	if m != nil {
+		return m.Running
+	}
+	return 0
+}
+
+func (m *Services) GetFailed() int32 {
+	if m != nil {
+		return m.Failed
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Host)(nil), "host.Host")
+	proto.RegisterType((*Services)(nil), "host.Services")
 }
 
 func init() { proto.RegisterFile("github.com/mesanine/gaffer/host/host.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 142 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xd2, 0x4a, 0xcf, 0x2c, 0xc9,
-	0x28, 0x4d, 0xd2, 0x4b, 0xce, 0xcf, 0xd5, 0xcf, 0x4d, 0x2d, 0x4e, 0xcc, 0xcb, 0xcc, 0x4b, 0xd5,
-	0x4f, 0x4f, 0x4c, 0x4b, 0x4b, 0x2d, 0xd2, 0xcf, 0xc8, 0x2f, 0x2e, 0x01, 0x13, 0x7a, 0x05, 0x45,
-	0xf9, 0x25, 0xf9, 0x42, 0x2c, 0x20, 0xb6, 0x52, 0x14, 0x17, 0x8b, 0x47, 0x7e, 0x71, 0x89, 0x90,
-	0x10, 0x17, 0x4b, 0x5e, 0x62, 0x6e, 0xaa, 0x04, 0xa3, 0x02, 0xa3, 0x06, 0x67, 0x10, 0x98, 0x2d,
-	0x24, 0xc1, 0xc5, 0x9e, 0x98, 0x92, 0x52, 0x94, 0x5a, 0x5c, 0x2c, 0xc1, 0x04, 0x16, 0x86, 0x71,
-	0x85, 0x04, 0xb8, 0x98, 0x73, 0x13, 0x93, 0x25, 0x98, 0xc1, 0xa2, 0x20, 0x26, 0x48, 0x7f, 0x41,
-	0x7e, 0x51, 0x89, 0x04, 0x8b, 0x02, 0xa3, 0x06, 0x6b, 0x10, 0x98, 0x9d, 0xc4, 0x06, 0xb6, 0xc8,
-	0x18, 0x10, 0x00, 0x00, 0xff, 0xff, 0xbe, 0xec, 0x75, 0xaf, 0x96, 0x00, 0x00, 0x00,
+	// 330 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0x51, 0x4d, 0x6b, 0xe3, 0x30,
+	0x10, 0x45, 0xf1, 0x47, 0x9c, 0x09, 0x2c, 0x8b, 0x58, 0xb2, 0x62, 0xf7, 0x62, 0x72, 0x32, 0x39,
+	0x38, 0x90, 0x5e, 0xda, 0xde, 0x0b, 0x3d, 0xf4, 0xa4, 0xf4, 0x1e, 0xe4, 0x78, 0x62, 0x8b, 0xda,
+	0x52, 0x90, 0x94, 0x40, 0x7e, 0x6a, 0xff, 0x4d, 0x91, 0xac, 0x94, 0x5e, 0xc4, 0x7b, 0x6f, 0xe6,
+	0x49, 0x33, 0x4f, 0xb0, 0xe9, 0xa4, 0xeb, 0x2f, 0x4d, 0x7d, 0xd4, 0xe3, 0x76, 0x44, 0x2b, 0x94,
+	0x54, 0xb8, 0xed, 0xc4, 0xe9, 0x84, 0x66, 0xdb, 0x6b, 0xeb, 0xc2, 0x51, 0x9f, 0x8d, 0x76, 0x9a,
+	0xa6, 0x1e, 0xaf, 0x3f, 0x67, 0x90, 0xbe, 0x6a, 0xeb, 0x28, 0x85, 0x54, 0x89, 0x11, 0x19, 0x29,
+	0x49, 0xb5, 0xe0, 0x01, 0x53, 0x06, 0x73, 0xd1, 0xb6, 0x06, 0xad, 0x65, 0xb3, 0x20, 0xdf, 0x29,
+	0xfd, 0x0d, 0xc9, 0x28, 0x8e, 0x2c, 0x09, 0xaa, 0x87, 0xde, 0x7f, 0xd6, 0xc6, 0xb1, 0xb4, 0x24,
+	0x55, 0xc6, 0x03, 0xf6, 0xfe, 0x2b, 0x1a, 0x2b, 0xb5, 0x62, 0xd9, 0xe4, 0x8f, 0x94, 0xfe, 0x85,
+	0x79, 0x27, 0xdd, 0xc1, 0xf6, 0x82, 0xe5, 0xa1, 0x92, 0x77, 0xd2, 0xed, 0x7b, 0x41, 0xff, 0xc3,
+	0xa2, 0xd1, 0xda, 0x1d, 0x9c, 0x1c, 0x91, 0xcd, 0x4b, 0x52, 0x25, 0xbc, 0xf0, 0xc2, 0xbb, 0x1c,
+	0x91, 0xd6, 0x90, 0x0f, 0xa2, 0xc1, 0xc1, 0xb2, 0xa2, 0x4c, 0xaa, 0xe5, 0x6e, 0x55, 0x87, 0x7d,
+	0xfc, 0xfc, 0xf5, 0x5b, 0x28, 0xbc, 0x28, 0x67, 0x6e, 0x3c, 0x76, 0xd1, 0x0d, 0x14, 0x16, 0xcd,
+	0x55, 0x1e, 0xd1, 0xb2, 0x45, 0x49, 0xaa, 0xe5, 0xee, 0xd7, 0xe4, 0xd8, 0x47, 0x95, 0x7f, 0xd7,
+	0xe9, 0x0a, 0xf2, 0x01, 0x45, 0x8b, 0x86, 0x41, 0x49, 0xaa, 0x82, 0x47, 0xf6, 0xef, 0x09, 0x96,
+	0x3f, 0xae, 0xf6, 0x8b, 0x7f, 0xe0, 0x2d, 0xa6, 0xe4, 0x21, 0xfd, 0x03, 0xd9, 0x55, 0x0c, 0x17,
+	0x8c, 0x11, 0x4d, 0xe4, 0x79, 0xf6, 0x48, 0xd6, 0x1c, 0x8a, 0xfb, 0x43, 0xbe, 0xcb, 0x69, 0x27,
+	0x86, 0xe0, 0xcc, 0xf8, 0x44, 0x7c, 0x40, 0xe6, 0xa2, 0x94, 0x54, 0x5d, 0x70, 0x67, 0xfc, 0x4e,
+	0xfd, 0x38, 0x27, 0x21, 0x07, 0x6c, 0x43, 0xc6, 0x19, 0x8f, 0xac, 0xc9, 0xc3, 0xe7, 0x3d, 0x7c,
+	0x0d, 0x00, 0x03, 0xd5, 0xfd, 0xd3, 0xea, 0x01, 0x00, 0x00,
 }
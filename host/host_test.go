@@ -0,0 +1,61 @@
+package host
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func names(hosts Hosts) []string {
+	n := []string{}
+	for _, h := range hosts {
+		n = append(n, h.Name)
+	}
+	return n
+}
+
+func TestFilter(t *testing.T) {
+	hosts := Hosts{
+		{Name: "web-1", Address: "10.0.0.1", Labels: map[string]string{"zone": "a", "role": "web"}},
+		{Name: "web-2", Address: "10.0.0.2", Labels: map[string]string{"zone": "b", "role": "web"}},
+		{Name: "db-1", Address: "10.0.0.3", Labels: map[string]string{"zone": "a"}},
+	}
+	for _, test := range []struct {
+		filters []Filter
+		matched []string
+	}{
+		{nil, []string{"web-1", "web-2", "db-1"}},
+		{[]Filter{Any()}, []string{"web-1", "web-2", "db-1"}},
+		{[]Filter{ByName("^web")}, []string{"web-1", "web-2"}},
+		{[]Filter{ByLabels(map[string]string{"zone": "a"})}, []string{"web-1", "db-1"}},
+		{[]Filter{ByName("^web"), ByLabels(map[string]string{"zone": "a"})}, []string{"web-1"}},
+		{[]Filter{ByIP("10.0.0.3"), ByName("^web")}, []string{}},
+		{[]Filter{ByLabels(map[string]string{"zone": "a", "role": "web"})}, []string{"web-1"}},
+		{[]Filter{ByLabels(map[string]string{"zone": ""})}, []string{}},
+		{[]Filter{ByLabels(map[string]string{})}, []string{"web-1", "web-2", "db-1"}},
+	} {
+		assert.Equal(t, test.matched, names(hosts.Filter(test.filters...)))
+	}
+}
+
+func TestParseLabels(t *testing.T) {
+	for _, test := range []struct {
+		pairs  []string
+		labels map[string]string
+		err    bool
+	}{
+		{[]string{}, map[string]string{}, false},
+		{[]string{"zone=a", "role=web"}, map[string]string{"zone": "a", "role": "web"}, false},
+		{[]string{"empty="}, map[string]string{"empty": ""}, false},
+		{[]string{"expr=a=b"}, map[string]string{"expr": "a=b"}, false},
+		{[]string{"zone"}, nil, true},
+		{[]string{"=a"}, nil, true},
+	} {
+		labels, err := ParseLabels(test.pairs)
+		if test.err {
+			assert.Error(t, err, "%v", test.pairs)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, test.labels, labels)
+	}
+}
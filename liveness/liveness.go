@@ -0,0 +1,53 @@
+/*
+package liveness tracks the progress of the core
+Gaffer goroutines. Each loop calls Beat at least
+once every Interval while it is running and Done
+when it returns so the watchdog can detect a
+goroutine which has stalled.
+*/
+package liveness
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Interval is how often a running
+// loop should call Beat.
+const Interval = 1 * time.Second
+
+var (
+	mu    sync.Mutex
+	beats = map[string]time.Time{}
+)
+
+// Beat records that the named
+// goroutine is making progress.
+func Beat(name string) {
+	mu.Lock()
+	defer mu.Unlock()
+	beats[name] = time.Now()
+}
+
+// Done stops tracking the named goroutine.
+func Done(name string) {
+	mu.Lock()
+	defer mu.Unlock()
+	delete(beats, name)
+}
+
+// Stalled returns the name of each goroutine
+// which has not called Beat within threshold.
+func Stalled(threshold time.Duration) []string {
+	mu.Lock()
+	defer mu.Unlock()
+	stalled := []string{}
+	for name, last := range beats {
+		if time.Since(last) > threshold {
+			stalled = append(stalled, name)
+		}
+	}
+	sort.Strings(stalled)
+	return stalled
+}
@@ -0,0 +1,18 @@
+package liveness
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestStalled(t *testing.T) {
+	Beat("fresh")
+	mu.Lock()
+	beats["stale"] = time.Now().Add(-1 * time.Minute)
+	mu.Unlock()
+	assert.Equal(t, []string{"stale"}, Stalled(30*time.Second))
+	Done("stale")
+	assert.Empty(t, Stalled(30*time.Second))
+	Done("fresh")
+}
@@ -0,0 +1,211 @@
+package log
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// levels holds the global level and the
+// levels of named loggers which override it.
+var levels = newLevelSet()
+
+// Level is the level of a
+// logger, the global level
+// if the name is empty.
+type Level struct {
+	Name  string
+	Level zapcore.Level
+	// Revert is when a temporary
+	// level is reverted, if set.
+	Revert time.Time
+}
+
+// revert restores a level
+// set temporarily.
+type revert struct {
+	timer    *time.Timer
+	at       time.Time
+	previous zapcore.Level
+	// overridden is false if the named
+	// logger previously had no level.
+	overridden bool
+}
+
+type levelSet struct {
+	mu      sync.RWMutex
+	global  zapcore.Level
+	named   map[string]zapcore.Level
+	reverts map[string]*revert
+	// current is a copy of the levels which
+	// is read by every entry without locking.
+	current atomic.Value
+}
+
+func newLevelSet() *levelSet {
+	s := &levelSet{
+		global:  zapcore.InfoLevel,
+		named:   map[string]zapcore.Level{},
+		reverts: map[string]*revert{},
+	}
+	s.update()
+	return s
+}
+
+// snapshot is an immutable copy of
+// the levels with their minimum.
+type snapshot struct {
+	global  zapcore.Level
+	minimum zapcore.Level
+	named   map[string]zapcore.Level
+}
+
+// of returns the level of the logger with
+// the longest matching name. Named loggers
+// include the names of their parents, e.g.
+// the level of foo applies to foo.bar.
+func (s *snapshot) of(name string) zapcore.Level {
+	level, match := s.global, -1
+	for other, l := range s.named {
+		if len(other) > match && (name == other || strings.HasPrefix(name, other+".")) {
+			level, match = l, len(other)
+		}
+	}
+	return level
+}
+
+func (s *levelSet) load() *snapshot {
+	return s.current.Load().(*snapshot)
+}
+
+// update copies the levels to current
+// and must be called with mu held.
+func (s *levelSet) update() {
+	snap := &snapshot{
+		global:  s.global,
+		minimum: s.global,
+		named:   map[string]zapcore.Level{},
+	}
+	for name, l := range s.named {
+		snap.named[name] = l
+		if l < snap.minimum {
+			snap.minimum = l
+		}
+	}
+	s.current.Store(snap)
+}
+
+// Levels returns the global level
+// followed by each named level.
+func Levels() []Level {
+	levels.mu.RLock()
+	defer levels.mu.RUnlock()
+	named := []Level{}
+	for name, level := range levels.named {
+		named = append(named, Level{Name: name, Level: level})
+	}
+	sort.Slice(named, func(i, j int) bool { return named[i].Name < named[j].Name })
+	all := append([]Level{{Level: levels.global}}, named...)
+	for i := range all {
+		if r, ok := levels.reverts[all[i].Name]; ok {
+			all[i].Revert = r.at
+		}
+	}
+	return all
+}
+
+// SetLevel sets the level of the named logger or the
+// global level if name is empty. If d is positive the
+// previous level is restored after it.
+func SetLevel(name string, level zapcore.Level, d time.Duration) {
+	levels.mu.Lock()
+	defer levels.mu.Unlock()
+	r, pending := levels.reverts[name]
+	if pending {
+		// The level from before the
+		// first change is restored.
+		r.timer.Stop()
+		delete(levels.reverts, name)
+	} else {
+		r = &revert{}
+		if name == "" {
+			r.previous, r.overridden = levels.global, true
+		} else {
+			r.previous, r.overridden = levels.named[name]
+		}
+	}
+	levels.set(name, level, true)
+	if d <= 0 {
+		return
+	}
+	r.at = time.Now().Add(d)
+	r.timer = time.AfterFunc(d, func() {
+		levels.mu.Lock()
+		if levels.reverts[name] != r {
+			levels.mu.Unlock()
+			return
+		}
+		delete(levels.reverts, name)
+		levels.set(name, r.previous, r.overridden)
+		levels.mu.Unlock()
+		Log.Info("log level reverted", zap.String("logger", name), zap.String("level", r.previous.String()))
+	})
+	levels.reverts[name] = r
+}
+
+// ResetLevel removes the level of a named logger
+// so it logs at the global level again. The global
+// level is restored if it was set temporarily.
+func ResetLevel(name string) {
+	levels.mu.Lock()
+	defer levels.mu.Unlock()
+	r, pending := levels.reverts[name]
+	if pending {
+		r.timer.Stop()
+		delete(levels.reverts, name)
+	}
+	switch {
+	case name != "":
+		levels.set(name, 0, false)
+	case pending:
+		levels.set(name, r.previous, true)
+	}
+}
+
+func (s *levelSet) set(name string, level zapcore.Level, overridden bool) {
+	switch {
+	case name == "":
+		s.global = level
+	case overridden:
+		s.named[name] = level
+	default:
+		delete(s.named, name)
+	}
+	s.update()
+}
+
+// levelCore filters entries by the
+// level of the logger they are from.
+type levelCore struct {
+	zapcore.Core
+}
+
+func (c levelCore) Enabled(level zapcore.Level) bool {
+	return level >= levels.load().minimum
+}
+
+func (c levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return levelCore{c.Core.With(fields)}
+}
+
+func (c levelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	snap := levels.load()
+	if ent.Level < snap.minimum || ent.Level < snap.of(ent.LoggerName) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
@@ -0,0 +1,52 @@
+package log
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"testing"
+	"time"
+)
+
+func TestLevels(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(levelCore{core})
+	SetLevel("", zapcore.InfoLevel, 0)
+	logger.Debug("dropped")
+	logger.Named("foo").Debug("dropped")
+	SetLevel("foo", zapcore.DebugLevel, 0)
+	logger.Named("foo").Debug("foo")
+	logger.Named("foo").Named("bar").Debug("foo.bar")
+	logger.Named("foobar").Debug("dropped")
+	logger.Debug("dropped")
+	SetLevel("foo.bar", zapcore.ErrorLevel, 0)
+	logger.Named("foo").Named("bar").Warn("dropped")
+	ResetLevel("foo")
+	ResetLevel("foo.bar")
+	logger.Named("foo").Debug("dropped")
+	messages := []string{}
+	for _, entry := range logs.AllUntimed() {
+		messages = append(messages, entry.Message)
+	}
+	assert.Equal(t, []string{"foo", "foo.bar"}, messages)
+	// Temporary levels are reverted
+	// to the level before the first
+	SetLevel("", zapcore.DebugLevel, 50*time.Millisecond)
+	SetLevel("", zapcore.WarnLevel, 50*time.Millisecond)
+	assert.Equal(t, zapcore.WarnLevel, Levels()[0].Level)
+	assert.False(t, Levels()[0].Revert.IsZero())
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, zapcore.InfoLevel, Levels()[0].Level)
+	assert.True(t, Levels()[0].Revert.IsZero())
+	SetLevel("foo", zapcore.DebugLevel, 50*time.Millisecond)
+	time.Sleep(200 * time.Millisecond)
+	assert.Len(t, Levels(), 1)
+	// Resetting the global level
+	// restores it immediately
+	SetLevel("", zapcore.DebugLevel, time.Minute)
+	ResetLevel("")
+	assert.Equal(t, zapcore.InfoLevel, Levels()[0].Level)
+	assert.True(t, Levels()[0].Revert.IsZero())
+	assert.False(t, logger.Core().Enabled(zapcore.DebugLevel))
+}
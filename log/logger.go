@@ -56,7 +56,7 @@ func Setup(config config.Config) error {
 		if err != nil {
 			return err
 		}
-		cores = append(cores, zapcore.NewCore(encoder, zapcore.AddSync(fp), zap.NewAtomicLevelAt(level)))
+		cores = append(cores, zapcore.NewCore(encoder, zapcore.AddSync(fp), zapcore.DebugLevel))
 	}
 	// Create a directory at path
 	// if it is missing. Log files
@@ -73,20 +73,36 @@ func Setup(config config.Config) error {
 			Filename:   filepath.Join(config.Logger.LogDir, LogFileName),
 			Compress:   config.Logger.Compress,
 		})
-		cores = append(cores, zapcore.NewCore(encoder, sync, zap.NewAtomicLevelAt(level)))
+		cores = append(cores, zapcore.NewCore(encoder, sync, zapcore.DebugLevel))
 	}
-	switch len(cores) {
-	case 1:
-		Log = zap.New(cores[0])
-	case 2:
-		Log = zap.New(zapcore.NewTee(cores...))
-	default:
+	remote, err := sinkCores(config.Logger.Sinks)
+	if err != nil {
+		return err
+	}
+	cores = append(cores, remote...)
+	if len(cores) == 0 {
 		// Logging is completely disabled
 		Log = zap.NewNop()
+		return nil
 	}
+	// Keep recent entries in memory
+	// for crash diagnostics.
+	recentConfig := zap.NewProductionEncoderConfig()
+	recentConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	cores = append(cores, zapcore.NewCore(zapcore.NewJSONEncoder(recentConfig), recent, zapcore.DebugLevel))
+	// Cores write every entry enabled
+	// by the level of its logger which
+	// may be changed at runtime.
+	SetLevel("", level, 0)
+	Log = zap.New(levelCore{zapcore.NewTee(cores...)})
 	return nil
 }
 
+// Named returns the global logger with a name
+// such as that of a plugin so its level can be
+// set independently.
+func Named(name string) *zap.Logger { return Log.Named(name) }
+
 func init() {
 	// Noop logging by default (useful is importing as library for testing)
 	Log = zap.NewNop()
@@ -0,0 +1,45 @@
+package log
+
+import (
+	"sync"
+)
+
+// RecentLines is the number of log entries
+// kept in memory for crash diagnostics.
+const RecentLines = 200
+
+// ring is a buffer holding the
+// most recently written log entries.
+type ring struct {
+	mu    sync.Mutex
+	lines []string
+	next  int
+}
+
+var recent = &ring{lines: make([]string, 0, RecentLines)}
+
+// Write implements zapcore.WriteSyncer, each
+// call writes a single encoded entry.
+func (r *ring) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.lines) < cap(r.lines) {
+		r.lines = append(r.lines, string(p))
+	} else {
+		r.lines[r.next] = string(p)
+		r.next = (r.next + 1) % cap(r.lines)
+	}
+	return len(p), nil
+}
+
+func (r *ring) Sync() error { return nil }
+
+// Recent returns the most recent log
+// entries from oldest to newest.
+func Recent() []string {
+	recent.mu.Lock()
+	defer recent.mu.Unlock()
+	lines := make([]string, 0, len(recent.lines))
+	lines = append(lines, recent.lines[recent.next:]...)
+	return append(lines, recent.lines[:recent.next]...)
+}
@@ -0,0 +1,355 @@
+package log
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"github.com/mesanine/gaffer/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// SinkBuffer is the default number of entries
+	// held for an unreachable destination.
+	SinkBuffer = 1000
+	// SinkTimeout is how long connecting to
+	// or writing to a destination may take.
+	SinkTimeout = 10 * time.Second
+	// SinkRetry is the maximum interval between
+	// attempts to connect to a destination.
+	SinkRetry = 30 * time.Second
+	// SinkFlush is how long Sync waits for
+	// buffered entries to be written.
+	SinkFlush = 5 * time.Second
+	// facility of syslog messages, daemon
+	facility = 3
+)
+
+// SinkStats are the counters of a sink.
+type SinkStats struct {
+	Format    string
+	Address   string
+	Sent      uint64
+	Dropped   uint64
+	Connected bool
+}
+
+var (
+	sinksMu sync.Mutex
+	sinks   []*sink
+)
+
+// Sinks returns the counters of
+// each configured sink.
+func Sinks() []SinkStats {
+	sinksMu.Lock()
+	defer sinksMu.Unlock()
+	stats := []SinkStats{}
+	for _, s := range sinks {
+		stats = append(stats, SinkStats{
+			Format:    s.format,
+			Address:   s.address,
+			Sent:      atomic.LoadUint64(&s.sent),
+			Dropped:   atomic.LoadUint64(&s.dropped),
+			Connected: atomic.LoadUint32(&s.connected) == 1,
+		})
+	}
+	return stats
+}
+
+// sink is a zapcore.WriteSyncer which buffers entries
+// and writes them to a remote destination from its
+// own goroutine so logging never blocks on the
+// network. Entries are dropped once the buffer
+// is full.
+type sink struct {
+	format  string
+	address string
+	network string
+	host    string
+	tls     *tls.Config
+	entries chan []byte
+	// pending counts entries which
+	// are buffered or being written.
+	pending   int64
+	sent      uint64
+	dropped   uint64
+	connected uint32
+}
+
+func newSink(cfg config.Sink) (*sink, error) {
+	u, err := url.Parse(cfg.Address)
+	if err != nil {
+		return nil, err
+	}
+	s := &sink{
+		format:  cfg.Format,
+		address: cfg.Address,
+		network: u.Scheme,
+		host:    u.Host,
+	}
+	switch u.Scheme {
+	case "udp":
+		if cfg.Format != "syslog" {
+			return nil, fmt.Errorf("only syslog may be sent over udp: %s", cfg.Address)
+		}
+	case "tcp":
+	case "tls":
+		s.network = "tcp"
+		s.tls = &tls.Config{ServerName: u.Hostname()}
+		if cfg.CA != "" {
+			raw, err := ioutil.ReadFile(cfg.CA)
+			if err != nil {
+				return nil, err
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(raw) {
+				return nil, fmt.Errorf("no certificates in %s", cfg.CA)
+			}
+			s.tls.RootCAs = pool
+		}
+	default:
+		return nil, fmt.Errorf("bad sink address: %s", cfg.Address)
+	}
+	size := cfg.Buffer
+	if size <= 0 {
+		size = SinkBuffer
+	}
+	s.entries = make(chan []byte, size)
+	return s, nil
+}
+
+// Write implements zapcore.WriteSyncer,
+// each call writes a single entry.
+func (s *sink) Write(p []byte) (int, error) {
+	entry := make([]byte, len(p))
+	copy(entry, p)
+	atomic.AddInt64(&s.pending, 1)
+	select {
+	case s.entries <- entry:
+	default:
+		atomic.AddInt64(&s.pending, -1)
+		atomic.AddUint64(&s.dropped, 1)
+	}
+	return len(p), nil
+}
+
+// Sync waits for buffered entries to be written
+// so entries logged before exiting are not lost.
+func (s *sink) Sync() error {
+	deadline := time.Now().Add(SinkFlush)
+	for atomic.LoadInt64(&s.pending) > 0 {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%d entries were not sent to %s", atomic.LoadInt64(&s.pending), s.address)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return nil
+}
+
+// run writes buffered entries reconnecting
+// with a backoff if the destination fails.
+func (s *sink) run() {
+	var (
+		conn  net.Conn
+		retry = time.Second
+	)
+	for entry := range s.entries {
+		for {
+			if conn == nil {
+				c, err := s.dial()
+				if err != nil {
+					time.Sleep(retry)
+					if retry *= 2; retry > SinkRetry {
+						retry = SinkRetry
+					}
+					continue
+				}
+				conn, retry = c, time.Second
+				atomic.StoreUint32(&s.connected, 1)
+			}
+			conn.SetWriteDeadline(time.Now().Add(SinkTimeout))
+			if _, err := conn.Write(s.frame(entry)); err != nil {
+				conn.Close()
+				conn = nil
+				atomic.StoreUint32(&s.connected, 0)
+				continue
+			}
+			atomic.AddUint64(&s.sent, 1)
+			break
+		}
+		atomic.AddInt64(&s.pending, -1)
+	}
+}
+
+func (s *sink) dial() (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: SinkTimeout}
+	if s.tls != nil {
+		return tls.DialWithDialer(dialer, s.network, s.host, s.tls)
+	}
+	return dialer.Dial(s.network, s.host)
+}
+
+// frame frames an entry for the transport. Syslog
+// messages over streams are octet counted as in
+// RFC 6587 while JSON entries end with a newline.
+func (s *sink) frame(entry []byte) []byte {
+	if s.format == "syslog" && s.network != "udp" {
+		return append([]byte(fmt.Sprintf("%d ", len(entry))), entry...)
+	}
+	return entry
+}
+
+// sinkCores returns a core writing to the destination
+// of each sink. Sinks are only started once all of
+// them are configured successfully.
+func sinkCores(cfgs []config.Sink) ([]zapcore.Core, error) {
+	var (
+		cores   []zapcore.Core
+		started []*sink
+	)
+	for _, cfg := range cfgs {
+		core, s, err := sinkCore(cfg)
+		if err != nil {
+			return nil, err
+		}
+		cores = append(cores, core)
+		started = append(started, s)
+	}
+	sinksMu.Lock()
+	sinks = append(sinks, started...)
+	sinksMu.Unlock()
+	for _, s := range started {
+		go s.run()
+	}
+	return cores, nil
+}
+
+// sinkCore returns a core writing
+// to the destination of a sink.
+func sinkCore(cfg config.Sink) (zapcore.Core, *sink, error) {
+	s, err := newSink(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	var core zapcore.Core
+	switch cfg.Format {
+	case "syslog":
+		core = newSyslogCore(s)
+	case "json":
+		encoderConfig := zap.NewProductionEncoderConfig()
+		encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+		core = zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), s, zapcore.DebugLevel)
+	default:
+		return nil, nil, fmt.Errorf("unknown sink format: %s", cfg.Format)
+	}
+	return core, s, nil
+}
+
+// syslogCore encodes entries as RFC 5424 messages
+// with the logger name as the message ID and any
+// fields JSON encoded after the message.
+type syslogCore struct {
+	fields   zapcore.Encoder
+	out      zapcore.WriteSyncer
+	hostname string
+	pid      int
+}
+
+func newSyslogCore(out zapcore.WriteSyncer) *syslogCore {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	// Only fields are encoded
+	// by the JSON encoder.
+	return &syslogCore{
+		fields: zapcore.NewJSONEncoder(zapcore.EncoderConfig{
+			StacktraceKey:  "stacktrace",
+			EncodeDuration: zapcore.StringDurationEncoder,
+			EncodeTime:     zapcore.ISO8601TimeEncoder,
+		}),
+		out:      out,
+		hostname: hostname,
+		pid:      os.Getpid(),
+	}
+}
+
+func (c *syslogCore) Enabled(zapcore.Level) bool { return true }
+
+func (c *syslogCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.fields = c.fields.Clone()
+	for _, field := range fields {
+		field.AddTo(clone.fields)
+	}
+	return &clone
+}
+
+func (c *syslogCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, c)
+}
+
+func (c *syslogCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.fields.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	defer buf.Free()
+	msg := ent.Message
+	if encoded := strings.TrimSpace(buf.String()); encoded != "{}" {
+		msg = fmt.Sprintf("%s %s", msg, encoded)
+	}
+	msgID := ent.LoggerName
+	if msgID == "" {
+		msgID = "-"
+	} else if len(msgID) > 32 {
+		msgID = msgID[:32]
+	}
+	_, err = fmt.Fprintf(
+		c.out, "<%d>1 %s %s gaffer %d %s - %s",
+		facility*8+severity(ent.Level),
+		ent.Time.UTC().Format("2006-01-02T15:04:05.000000Z"),
+		c.hostname, c.pid, msgID, msg,
+	)
+	if err != nil {
+		return err
+	}
+	// Flush entries logged before
+	// exiting as zap cores do.
+	if ent.Level > zapcore.ErrorLevel {
+		return c.Sync()
+	}
+	return nil
+}
+
+func (c *syslogCore) Sync() error { return c.out.Sync() }
+
+// severity returns the syslog
+// severity of a level.
+func severity(level zapcore.Level) int {
+	switch level {
+	case zapcore.DebugLevel:
+		return 7
+	case zapcore.InfoLevel:
+		return 6
+	case zapcore.WarnLevel:
+		return 4
+	case zapcore.ErrorLevel:
+		return 3
+	case zapcore.DPanicLevel:
+		return 2
+	case zapcore.PanicLevel:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,79 @@
+package log
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/mesanine/gaffer/config"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSyslogSink(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+	cores, err := sinkCores([]config.Sink{{Format: "syslog", Address: fmt.Sprintf("tcp://%s", listener.Addr())}})
+	assert.NoError(t, err)
+	logger := zap.New(cores[0]).Named("foo")
+	logger.Warn("hello", zap.Int("code", 1))
+	logger.Info("world")
+	// Entries are written once
+	// connected and synced.
+	assert.NoError(t, cores[0].Sync())
+	conn, err := listener.Accept()
+	assert.NoError(t, err)
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	reader := bufio.NewReader(conn)
+	for _, expected := range []string{"<28>1 ", "<30>1 "} {
+		var length int
+		_, err := fmt.Fscanf(reader, "%d ", &length)
+		assert.NoError(t, err)
+		msg := make([]byte, length)
+		_, err = io.ReadFull(reader, msg)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(msg), expected), string(msg))
+		assert.Contains(t, string(msg), " gaffer ")
+		assert.Contains(t, string(msg), " foo - ")
+	}
+}
+
+func TestSinkDrops(t *testing.T) {
+	_, err := newSink(config.Sink{Format: "json", Address: "udp://127.0.0.1:514"})
+	assert.Error(t, err)
+	// Nothing listens on this address
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	address := listener.Addr().String()
+	listener.Close()
+	// No sink is started if any fails
+	started := len(Sinks())
+	_, err = sinkCores([]config.Sink{
+		{Format: "json", Address: fmt.Sprintf("tcp://%s", address)},
+		{Format: "bogus", Address: fmt.Sprintf("tcp://%s", address)},
+	})
+	assert.Error(t, err)
+	assert.Len(t, Sinks(), started)
+	cores, err := sinkCores([]config.Sink{{Format: "json", Address: fmt.Sprintf("tcp://%s", address), Buffer: 2}})
+	assert.NoError(t, err)
+	logger := zap.New(cores[0])
+	for i := 0; i < 10; i++ {
+		logger.Info("dropped")
+	}
+	var stats SinkStats
+	for _, s := range Sinks() {
+		if strings.HasSuffix(s.Address, address) {
+			stats = s
+		}
+	}
+	assert.False(t, stats.Connected)
+	assert.Equal(t, uint64(0), stats.Sent)
+	// One entry may be held by the
+	// goroutine as well as the buffer.
+	assert.True(t, stats.Dropped >= 7, "dropped %d", stats.Dropped)
+}
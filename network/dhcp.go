@@ -0,0 +1,276 @@
+package network
+
+import (
+	"encoding/binary"
+	"fmt"
+	"golang.org/x/sys/unix"
+	"math/rand"
+	"net"
+	"time"
+)
+
+// DHCP message types
+const (
+	dhcpDiscover = 1
+	dhcpOffer    = 2
+	dhcpRequest  = 3
+	dhcpAck      = 5
+	dhcpNak      = 6
+)
+
+// DHCP options
+const (
+	optSubnetMask  = 1
+	optRouter      = 3
+	optDNS         = 6
+	optRequestedIP = 50
+	optLeaseTime   = 51
+	optMessageType = 53
+	optServerID    = 54
+	optParams      = 55
+	optEnd         = 255
+)
+
+const (
+	dhcpServerPort = 67
+	dhcpClientPort = 68
+	// Size of the fixed BOOTP header
+	// including the magic cookie.
+	dhcpHeaderSize = 240
+)
+
+var magicCookie = []byte{99, 130, 83, 99}
+
+// DefaultLeaseTime is used if the
+// server does not specify one.
+const DefaultLeaseTime = 1 * time.Hour
+
+// Lease is an IPv4 address
+// leased from a DHCP server.
+type Lease struct {
+	Address  *net.IPNet
+	Router   net.IP
+	DNS      []net.IP
+	Server   net.IP
+	Duration time.Duration
+}
+
+// message is a decoded DHCP message.
+type message struct {
+	op      byte
+	xid     uint32
+	yiaddr  net.IP
+	chaddr  net.HardwareAddr
+	options map[byte][]byte
+}
+
+func (m message) msgType() byte {
+	if opt, ok := m.options[optMessageType]; ok && len(opt) == 1 {
+		return opt[0]
+	}
+	return 0
+}
+
+// encode serializes a client request
+// asking servers to broadcast replies.
+func (m message) encode() []byte {
+	buf := make([]byte, dhcpHeaderSize)
+	buf[0] = m.op
+	// htype ethernet
+	buf[1] = 1
+	buf[2] = byte(len(m.chaddr))
+	binary.BigEndian.PutUint32(buf[4:8], m.xid)
+	// broadcast flag
+	binary.BigEndian.PutUint16(buf[10:12], 0x8000)
+	copy(buf[28:44], m.chaddr)
+	copy(buf[236:240], magicCookie)
+	// Write the message type first
+	// as some servers require it.
+	if opt, ok := m.options[optMessageType]; ok {
+		buf = append(buf, optMessageType, byte(len(opt)))
+		buf = append(buf, opt...)
+	}
+	for code, opt := range m.options {
+		if code == optMessageType {
+			continue
+		}
+		buf = append(buf, code, byte(len(opt)))
+		buf = append(buf, opt...)
+	}
+	return append(buf, optEnd)
+}
+
+// decode parses a DHCP message.
+func decode(raw []byte) (*message, error) {
+	if len(raw) < dhcpHeaderSize {
+		return nil, fmt.Errorf("short DHCP message")
+	}
+	for i := range magicCookie {
+		if raw[236+i] != magicCookie[i] {
+			return nil, fmt.Errorf("bad DHCP magic cookie")
+		}
+	}
+	hlen := int(raw[2])
+	if hlen > 16 {
+		return nil, fmt.Errorf("bad DHCP hardware address length")
+	}
+	m := &message{
+		op:      raw[0],
+		xid:     binary.BigEndian.Uint32(raw[4:8]),
+		yiaddr:  net.IP(append([]byte{}, raw[16:20]...)),
+		chaddr:  net.HardwareAddr(append([]byte{}, raw[28:28+hlen]...)),
+		options: map[byte][]byte{},
+	}
+	opts := raw[dhcpHeaderSize:]
+	for len(opts) > 0 {
+		code := opts[0]
+		// pad
+		if code == 0 {
+			opts = opts[1:]
+			continue
+		}
+		if code == optEnd {
+			break
+		}
+		if len(opts) < 2 || len(opts) < 2+int(opts[1]) {
+			return nil, fmt.Errorf("truncated DHCP option %d", code)
+		}
+		m.options[code] = opts[2 : 2+int(opts[1])]
+		opts = opts[2+int(opts[1]):]
+	}
+	return m, nil
+}
+
+// lease builds a Lease from
+// a DHCPACK message.
+func (m message) lease() *Lease {
+	lease := &Lease{
+		Address:  &net.IPNet{IP: m.yiaddr, Mask: net.IPv4Mask(255, 255, 255, 0)},
+		DNS:      []net.IP{},
+		Duration: DefaultLeaseTime,
+	}
+	if opt, ok := m.options[optSubnetMask]; ok && len(opt) == 4 {
+		lease.Address.Mask = net.IPMask(opt)
+	}
+	if opt, ok := m.options[optRouter]; ok && len(opt) >= 4 {
+		lease.Router = net.IP(opt[0:4])
+	}
+	if opt, ok := m.options[optServerID]; ok && len(opt) == 4 {
+		lease.Server = net.IP(opt)
+	}
+	if opt, ok := m.options[optLeaseTime]; ok && len(opt) == 4 {
+		lease.Duration = time.Duration(binary.BigEndian.Uint32(opt)) * time.Second
+	}
+	opt := m.options[optDNS]
+	for i := 0; i+4 <= len(opt); i += 4 {
+		lease.DNS = append(lease.DNS, net.IP(opt[i:i+4]))
+	}
+	return lease
+}
+
+// DHCP leases an IPv4 address for the named
+// interface. The interface must already be up.
+func DHCP(name string, timeout time.Duration) (*Lease, error) {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil, err
+	}
+	fd, err := dhcpSocket(name, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer unix.Close(fd)
+	xid := rand.Uint32()
+	params := []byte{optSubnetMask, optRouter, optDNS, optLeaseTime, optServerID}
+	offer, err := exchange(fd, message{
+		op:     1,
+		xid:    xid,
+		chaddr: iface.HardwareAddr,
+		options: map[byte][]byte{
+			optMessageType: []byte{dhcpDiscover},
+			optParams:      params,
+		},
+	}, dhcpOffer)
+	if err != nil {
+		return nil, err
+	}
+	ack, err := exchange(fd, message{
+		op:     1,
+		xid:    xid,
+		chaddr: iface.HardwareAddr,
+		options: map[byte][]byte{
+			optMessageType: []byte{dhcpRequest},
+			optParams:      params,
+			optRequestedIP: offer.yiaddr.To4(),
+			optServerID:    offer.options[optServerID],
+		},
+	}, dhcpAck)
+	if err != nil {
+		return nil, err
+	}
+	return ack.lease(), nil
+}
+
+// exchange broadcasts a request and waits
+// for a reply of the expected type.
+func exchange(fd int, req message, expect byte) (*message, error) {
+	dst := &unix.SockaddrInet4{Port: dhcpServerPort, Addr: [4]byte{255, 255, 255, 255}}
+	if err := unix.Sendto(fd, req.encode(), 0, dst); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 1500)
+	for {
+		n, _, err := unix.Recvfrom(fd, buf, 0)
+		if err != nil {
+			if err == unix.EAGAIN {
+				return nil, fmt.Errorf("timed out waiting for DHCP reply")
+			}
+			return nil, err
+		}
+		reply, err := decode(buf[:n])
+		if err != nil || reply.op != 2 || reply.xid != req.xid {
+			continue
+		}
+		switch reply.msgType() {
+		case expect:
+			return reply, nil
+		case dhcpNak:
+			return nil, fmt.Errorf("DHCP request was refused")
+		}
+	}
+}
+
+// dhcpSocket opens a UDP socket on the DHCP
+// client port bound to the named interface.
+func dhcpSocket(name string, timeout time.Duration) (int, error) {
+	fd, err := unix.Socket(unix.AF_INET, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, unix.IPPROTO_UDP)
+	if err != nil {
+		return -1, err
+	}
+	tv := unix.NsecToTimeval(timeout.Nanoseconds())
+	for _, fn := range []func() error{
+		func() error { return unix.SetsockoptInt(fd, unix.SOL_SOCKET, unix.SO_REUSEADDR, 1) },
+		func() error { return unix.SetsockoptInt(fd, unix.SOL_SOCKET, unix.SO_BROADCAST, 1) },
+		func() error { return unix.SetsockoptString(fd, unix.SOL_SOCKET, unix.SO_BINDTODEVICE, name) },
+		func() error { return unix.SetsockoptTimeval(fd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &tv) },
+		func() error { return unix.Bind(fd, &unix.SockaddrInet4{Port: dhcpClientPort}) },
+	} {
+		if err := fn(); err != nil {
+			unix.Close(fd)
+			return -1, err
+		}
+	}
+	return fd, nil
+}
+
+// Apply assigns the leased address and
+// default route to the named interface.
+func (l Lease) Apply(name string) error {
+	if err := AddAddress(name, l.Address); err != nil {
+		return err
+	}
+	if l.Router != nil {
+		return AddRoute(name, nil, l.Router)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package network
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestDHCPMessage(t *testing.T) {
+	mac, _ := net.ParseMAC("02:42:ac:11:00:02")
+	req := message{
+		op:     1,
+		xid:    1234,
+		chaddr: mac,
+		options: map[byte][]byte{
+			optMessageType: []byte{dhcpDiscover},
+			optParams:      []byte{optSubnetMask, optRouter},
+		},
+	}
+	raw := req.encode()
+	// The message type is the first option
+	assert.Equal(t, []byte{optMessageType, 1, dhcpDiscover}, raw[dhcpHeaderSize:dhcpHeaderSize+3])
+	m, err := decode(raw)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1234), m.xid)
+	assert.Equal(t, mac, m.chaddr)
+	assert.Equal(t, byte(dhcpDiscover), m.msgType())
+	assert.Equal(t, []byte{optSubnetMask, optRouter}, m.options[optParams])
+	_, err = decode(raw[:100])
+	assert.Error(t, err)
+}
+
+func TestDHCPLease(t *testing.T) {
+	m := message{
+		yiaddr: net.IPv4(10, 0, 0, 5).To4(),
+		options: map[byte][]byte{
+			optSubnetMask: []byte{255, 255, 0, 0},
+			optRouter:     []byte{10, 0, 0, 1},
+			optDNS:        []byte{8, 8, 8, 8, 8, 8, 4, 4},
+			optLeaseTime:  []byte{0, 0, 0x0e, 0x10},
+		},
+	}
+	lease := m.lease()
+	assert.Equal(t, "10.0.0.5/16", lease.Address.String())
+	assert.Equal(t, "10.0.0.1", lease.Router.String())
+	assert.Len(t, lease.DNS, 2)
+	assert.Equal(t, "8.8.4.4", lease.DNS[1].String())
+	assert.Equal(t, time.Hour, lease.Duration)
+}
@@ -0,0 +1,201 @@
+package network
+
+import (
+	"encoding/binary"
+	"fmt"
+	"golang.org/x/sys/unix"
+	"net"
+	"syscall"
+	"unsafe"
+)
+
+// nativeEndian is the byte order
+// of netlink message fields.
+var nativeEndian binary.ByteOrder
+
+func init() {
+	i := uint16(1)
+	if *(*byte)(unsafe.Pointer(&i)) == 1 {
+		nativeEndian = binary.LittleEndian
+	} else {
+		nativeEndian = binary.BigEndian
+	}
+}
+
+// netlink is a minimal rtnetlink client.
+type netlink struct {
+	fd  int
+	seq uint32
+}
+
+func dial() (*netlink, error) {
+	fd, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_RAW|unix.SOCK_CLOEXEC, unix.NETLINK_ROUTE)
+	if err != nil {
+		return nil, err
+	}
+	if err := unix.Bind(fd, &unix.SockaddrNetlink{Family: unix.AF_NETLINK}); err != nil {
+		unix.Close(fd)
+		return nil, err
+	}
+	return &netlink{fd: fd}, nil
+}
+
+func (n *netlink) Close() error { return unix.Close(n.fd) }
+
+// request sends a message to the kernel
+// and waits for it to be acknowledged.
+func (n *netlink) request(typ, flags uint16, data []byte) error {
+	n.seq++
+	msg := make([]byte, unix.SizeofNlMsghdr, unix.SizeofNlMsghdr+len(data))
+	nativeEndian.PutUint32(msg[0:4], uint32(unix.SizeofNlMsghdr+len(data)))
+	nativeEndian.PutUint16(msg[4:6], typ)
+	nativeEndian.PutUint16(msg[6:8], flags|unix.NLM_F_REQUEST|unix.NLM_F_ACK)
+	nativeEndian.PutUint32(msg[8:12], n.seq)
+	msg = append(msg, data...)
+	if err := unix.Sendto(n.fd, msg, 0, &unix.SockaddrNetlink{Family: unix.AF_NETLINK}); err != nil {
+		return err
+	}
+	buf := make([]byte, unix.Getpagesize())
+	for {
+		size, _, err := unix.Recvfrom(n.fd, buf, 0)
+		if err != nil {
+			return err
+		}
+		msgs, err := syscall.ParseNetlinkMessage(buf[:size])
+		if err != nil {
+			return err
+		}
+		for _, m := range msgs {
+			if m.Header.Seq != n.seq || m.Header.Type != unix.NLMSG_ERROR {
+				continue
+			}
+			if len(m.Data) < 4 {
+				return fmt.Errorf("short netlink ack")
+			}
+			errno := int32(nativeEndian.Uint32(m.Data[0:4]))
+			if errno == 0 {
+				return nil
+			}
+			return syscall.Errno(-errno)
+		}
+	}
+}
+
+// attr encodes a route attribute
+// padded to a four byte boundary.
+func attr(typ uint16, data []byte) []byte {
+	length := unix.SizeofRtAttr + len(data)
+	buf := make([]byte, (length+unix.RTA_ALIGNTO-1) & ^(unix.RTA_ALIGNTO-1))
+	nativeEndian.PutUint16(buf[0:2], uint16(length))
+	nativeEndian.PutUint16(buf[2:4], typ)
+	copy(buf[unix.SizeofRtAttr:], data)
+	return buf
+}
+
+func uint32Attr(typ uint16, value uint32) []byte {
+	data := make([]byte, 4)
+	nativeEndian.PutUint32(data, value)
+	return attr(typ, data)
+}
+
+// LinkUp sets the IFF_UP flag
+// on the named interface.
+func LinkUp(name string) error {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return err
+	}
+	nl, err := dial()
+	if err != nil {
+		return err
+	}
+	defer nl.Close()
+	// struct ifinfomsg
+	msg := make([]byte, unix.SizeofIfInfomsg)
+	msg[0] = unix.AF_UNSPEC
+	nativeEndian.PutUint32(msg[4:8], uint32(iface.Index))
+	nativeEndian.PutUint32(msg[8:12], unix.IFF_UP)
+	nativeEndian.PutUint32(msg[12:16], unix.IFF_UP)
+	return nl.request(unix.RTM_NEWLINK, 0, msg)
+}
+
+// AddAddress assigns an IPv4 address
+// to the named interface replacing
+// any matching address.
+func AddAddress(name string, addr *net.IPNet) error {
+	return address(unix.RTM_NEWADDR, unix.NLM_F_CREATE|unix.NLM_F_REPLACE, name, addr)
+}
+
+// DelAddress removes an IPv4 address
+// from the named interface.
+func DelAddress(name string, addr *net.IPNet) error {
+	return address(unix.RTM_DELADDR, 0, name, addr)
+}
+
+func address(typ, flags uint16, name string, addr *net.IPNet) error {
+	ip := addr.IP.To4()
+	if ip == nil {
+		return fmt.Errorf("%s is not an IPv4 address", addr.String())
+	}
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return err
+	}
+	nl, err := dial()
+	if err != nil {
+		return err
+	}
+	defer nl.Close()
+	ones, _ := addr.Mask.Size()
+	// struct ifaddrmsg
+	msg := make([]byte, unix.SizeofIfAddrmsg)
+	msg[0] = unix.AF_INET
+	msg[1] = uint8(ones)
+	nativeEndian.PutUint32(msg[4:8], uint32(iface.Index))
+	broadcast := make(net.IP, net.IPv4len)
+	for i := range ip {
+		broadcast[i] = ip[i] | ^addr.Mask[len(addr.Mask)-net.IPv4len+i]
+	}
+	msg = append(msg, attr(unix.IFA_LOCAL, ip)...)
+	msg = append(msg, attr(unix.IFA_ADDRESS, ip)...)
+	msg = append(msg, attr(unix.IFA_BROADCAST, broadcast)...)
+	return nl.request(typ, flags, msg)
+}
+
+// AddRoute adds an IPv4 route through the named
+// interface replacing any matching route. A nil
+// destination is the default route and a nil
+// gateway is a directly connected route.
+func AddRoute(name string, dst *net.IPNet, gw net.IP) error {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return err
+	}
+	nl, err := dial()
+	if err != nil {
+		return err
+	}
+	defer nl.Close()
+	// struct rtmsg
+	msg := make([]byte, unix.SizeofRtMsg)
+	msg[0] = unix.AF_INET
+	msg[4] = unix.RT_TABLE_MAIN
+	msg[5] = unix.RTPROT_BOOT
+	msg[6] = unix.RT_SCOPE_UNIVERSE
+	msg[7] = unix.RTN_UNICAST
+	if dst != nil {
+		ones, _ := dst.Mask.Size()
+		msg[1] = uint8(ones)
+		msg = append(msg, attr(unix.RTA_DST, dst.IP.To4())...)
+	}
+	if gw != nil {
+		if gw.To4() == nil {
+			return fmt.Errorf("%s is not an IPv4 gateway", gw.String())
+		}
+		msg = append(msg, attr(unix.RTA_GATEWAY, gw.To4())...)
+	} else {
+		msg[6] = unix.RT_SCOPE_LINK
+	}
+	msg = append(msg, uint32Attr(unix.RTA_OIF, uint32(iface.Index))...)
+	return nl.request(unix.RTM_NEWROUTE, unix.NLM_F_CREATE|unix.NLM_F_REPLACE, msg)
+}
@@ -0,0 +1,118 @@
+/*
+package network configures network interfaces
+with rtnetlink and provides a minimal DHCPv4
+client so hosts can be brought online without
+any external tools.
+*/
+package network
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/mesanine/gaffer/config"
+	"github.com/mesanine/gaffer/log"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+)
+
+// Loopback is the name of
+// the loopback interface.
+const Loopback = "lo"
+
+// Configure brings up the loopback interface and
+// applies the static addresses and routes of each
+// configured interface. Interfaces using DHCP are
+// only brought up, leases are acquired later by
+// the network plugin.
+func Configure(cfg config.Network) error {
+	log.Log.Info("bringing up loopback interface")
+	if err := LinkUp(Loopback); err != nil {
+		return err
+	}
+	for _, iface := range cfg.Interfaces {
+		if err := Static(iface); err != nil {
+			return fmt.Errorf("failed to configure interface %s: %s", iface.Name, err.Error())
+		}
+	}
+	if len(cfg.Nameservers) > 0 {
+		return WriteResolvConf(cfg.ResolvConf, ParseIPs(cfg.Nameservers), cfg.Search)
+	}
+	return nil
+}
+
+// Static brings up an interface and applies
+// its static addresses and routes.
+func Static(iface config.Interface) error {
+	log.Log.Info(fmt.Sprintf("bringing up interface %s", iface.Name))
+	if err := LinkUp(iface.Name); err != nil {
+		return err
+	}
+	for _, address := range iface.Addresses {
+		ip, ipNet, err := net.ParseCIDR(address)
+		if err != nil {
+			return err
+		}
+		ipNet.IP = ip
+		log.Log.Info(fmt.Sprintf("adding address %s to %s", ipNet.String(), iface.Name))
+		if err := AddAddress(iface.Name, ipNet); err != nil {
+			return err
+		}
+	}
+	for _, route := range iface.Routes {
+		var dst *net.IPNet
+		if route.Destination != "" {
+			_, ipNet, err := net.ParseCIDR(route.Destination)
+			if err != nil {
+				return err
+			}
+			dst = ipNet
+		}
+		var gw net.IP
+		if route.Gateway != "" {
+			gw = net.ParseIP(route.Gateway)
+			if gw == nil {
+				return fmt.Errorf("bad gateway %s", route.Gateway)
+			}
+		}
+		log.Log.Info(fmt.Sprintf("adding route %s via %s on %s", route.Destination, route.Gateway, iface.Name))
+		if err := AddRoute(iface.Name, dst, gw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteResolvConf writes the nameservers and search
+// domains to a resolv.conf file at path.
+func WriteResolvConf(path string, nameservers []net.IP, search []string) error {
+	buf := bytes.NewBuffer(nil)
+	if len(search) > 0 {
+		fmt.Fprint(buf, "search")
+		for _, domain := range search {
+			fmt.Fprintf(buf, " %s", domain)
+		}
+		fmt.Fprintln(buf)
+	}
+	for _, ns := range nameservers {
+		fmt.Fprintf(buf, "nameserver %s\n", ns.String())
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	log.Log.Info(fmt.Sprintf("writing resolver configuration %s", path))
+	return ioutil.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// ParseIPs parses each IP address
+// ignoring any which are invalid.
+func ParseIPs(addrs []string) []net.IP {
+	ips := []net.IP{}
+	for _, addr := range addrs {
+		if ip := net.ParseIP(addr); ip != nil {
+			ips = append(ips, ip)
+		}
+	}
+	return ips
+}
@@ -0,0 +1,88 @@
+package plugin
+
+import (
+	"github.com/mesanine/gaffer/config"
+	"github.com/mesanine/gaffer/user"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"net/http"
+)
+
+// Auth authenticates calls made against the
+// RPC server and the HTTP gateway. If no user
+// is configured all calls are permitted.
+type Auth struct {
+	user *user.User
+}
+
+func NewAuth(cfg config.Config) (*Auth, error) {
+	auth := &Auth{}
+	if cfg.User != "" {
+		u, err := user.FromString(cfg.User)
+		if err != nil {
+			return nil, err
+		}
+		auth.user = u
+	}
+	return auth, nil
+}
+
+// Check verifies the user credentials
+// contained in the incoming RPC metadata.
+func (a Auth) Check(ctx context.Context) error {
+	if a.user == nil {
+		return nil
+	}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return grpc.Errorf(codes.Unauthenticated, "missing credentials")
+	}
+	var id, token string
+	if v := md[user.MetadataID]; len(v) > 0 {
+		id = v[0]
+	}
+	if v := md[user.MetadataToken]; len(v) > 0 {
+		token = v[0]
+	}
+	if !a.user.Match(id, token) {
+		return grpc.Errorf(codes.Unauthenticated, "bad credentials")
+	}
+	return nil
+}
+
+// CheckRequest verifies the basic auth
+// credentials of an HTTP request.
+func (a Auth) CheckRequest(r *http.Request) error {
+	if a.user == nil {
+		return nil
+	}
+	id, token, _ := r.BasicAuth()
+	if !a.user.Match(id, token) {
+		return grpc.Errorf(codes.Unauthenticated, "bad credentials")
+	}
+	return nil
+}
+
+// Unary returns a grpc.UnaryServerInterceptor
+// that rejects unauthenticated calls.
+func (a Auth) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := a.Check(ctx); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// Stream returns a grpc.StreamServerInterceptor
+// that rejects unauthenticated calls.
+func (a Auth) Stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := a.Check(ss.Context()); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
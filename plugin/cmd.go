@@ -0,0 +1,58 @@
+package plugin
+
+import (
+	"github.com/jawher/mow.cli"
+	"github.com/mesanine/gaffer/config"
+	"github.com/mesanine/gaffer/host"
+	"github.com/mesanine/gaffer/util"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"strconv"
+	"strings"
+)
+
+// CLI returns commands for
+// the registry RPC service.
+func (r *Registry) CLI(cfg *config.Config) cli.CmdInitializer {
+	return func(cmd *cli.Cmd) {
+		cmd.Command("list", "List plugins and their state", func(cmd *cli.Cmd) {
+			cmd.Action = func() {
+				util.Remote(*cfg, func(h *host.Host, conn *grpc.ClientConn) (interface{}, error) {
+					req := &ListRequest{Host: h}
+					return NewRPCClient(conn).List(context.Background(), req, cfg.CallOpts()...)
+				})
+			}
+		})
+		cmd.Command("restart", "Restart a plugin", func(cmd *cli.Cmd) {
+			cmd.Spec = "NAME"
+			name := cmd.String(cli.StringArg{
+				Name:  "NAME",
+				Desc:  "Plugin name to restart",
+				Value: "",
+			})
+			cmd.Action = func() {
+				util.Remote(*cfg, func(h *host.Host, conn *grpc.ClientConn) (interface{}, error) {
+					req := &RestartRequest{Name: *name, Host: h}
+					return NewRPCClient(conn).Restart(context.Background(), req, cfg.CallOpts()...)
+				})
+			}
+		})
+	}
+}
+
+// Table returns the plugins as rows of a table.
+func (r *ListResponse) Table(wide bool) ([]string, [][]string) {
+	headers := []string{"NAME", "STATE", "RESTARTS", "ERROR"}
+	if wide {
+		headers = append(headers, "POLICY", "DEPENDENCIES")
+	}
+	rows := [][]string{}
+	for _, status := range r.Plugins {
+		row := []string{status.Name, status.State, strconv.Itoa(int(status.Restarts)), status.Error}
+		if wide {
+			row = append(row, status.Policy, strings.Join(status.Dependencies, ","))
+		}
+		rows = append(rows, row)
+	}
+	return headers, rows
+}
@@ -0,0 +1,289 @@
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/mesanine/gaffer/config"
+	"github.com/mesanine/gaffer/event"
+	"github.com/mesanine/gaffer/log"
+	"github.com/mesanine/gaffer/reaper"
+	"google.golang.org/grpc"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Plugin states
+const (
+	Running = "running"
+	Failed  = "failed"
+	Stopped = "stopped"
+)
+
+// client runs a single external plugin.
+type client struct {
+	spec config.External
+	mu   sync.Mutex
+	// conn is set while the
+	// plugin is running.
+	conn      *grpc.ClientConn
+	handshake *HandshakeResponse
+	state     string
+	err       error
+	restarts  uint32
+}
+
+func newClient(spec config.External) *client {
+	return &client{spec: spec, state: Stopped}
+}
+
+// address returns the address the plugin serves on.
+func (c *client) address() string {
+	if c.spec.Address != "" {
+		return c.spec.Address
+	}
+	return fmt.Sprintf("unix://%s", filepath.Join(SocketDir, fmt.Sprintf("%s.sock", c.spec.Name)))
+}
+
+// run launches the plugin if it has a path, connects to it
+// and streams events until ctx is canceled or it fails.
+func (c *client) run(ctx context.Context, eb *event.EventBus, cfg config.Config) error {
+	var exited chan int
+	if c.spec.Path != "" {
+		cmd, err := c.launch()
+		if err != nil {
+			return err
+		}
+		// exited receives the exit status
+		// and is closed once it is read.
+		exited = make(chan int, 1)
+		go func() {
+			status, _ := reaper.Default.Wait(cmd)
+			exited <- status
+			close(exited)
+		}()
+		defer terminate(cmd, exited)
+	}
+	conn, handshake, err := c.connect(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	pc := NewPluginClient(conn)
+	// The stream outlives ctx so
+	// the plugin is stopped gracefully.
+	sctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := pc.Run(sctx)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.conn = conn
+	c.handshake = handshake
+	c.state = Running
+	c.err = nil
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		c.conn = nil
+		c.mu.Unlock()
+	}()
+	log.Named("external").Info(fmt.Sprintf("external plugin %s version %s is running", handshake.Name, handshake.PluginVersion))
+	sub := event.NewSubscriber()
+	eb.Subscribe(sub)
+	defer eb.Unsubscribe(sub)
+	done := make(chan error, 1)
+	go func() {
+		for {
+			evt, err := stream.Recv()
+			if err != nil {
+				if err == io.EOF {
+					err = nil
+				}
+				done <- err
+				return
+			}
+			eb.Push(*evt)
+		}
+	}()
+	subscribed := subscriptions(handshake)
+	for {
+		select {
+		case err := <-done:
+			if err == nil && ctx.Err() == nil {
+				return fmt.Errorf("external plugin %s returned", c.spec.Name)
+			}
+			return err
+		case status := <-exited:
+			return fmt.Errorf("external plugin %s exited with status %d", c.spec.Name, status)
+		case evt := <-sub.Chan():
+			if subscribed(evt) {
+				if err := stream.Send(&evt); err != nil {
+					return err
+				}
+			}
+		case <-ctx.Done():
+			stop, cancelStop := context.WithTimeout(context.Background(), StopTimeout)
+			_, err := pc.Stop(stop, &StopRequest{})
+			cancelStop()
+			if err != nil {
+				return err
+			}
+			stream.CloseSend()
+			select {
+			case err := <-done:
+				return err
+			case <-time.After(StopTimeout):
+				return fmt.Errorf("external plugin %s did not stop", c.spec.Name)
+			}
+		}
+	}
+}
+
+// launch starts the plugin binary
+// passing the address to serve on.
+func (c *client) launch() (*exec.Cmd, error) {
+	if err := os.MkdirAll(SocketDir, 0755); err != nil {
+		return nil, err
+	}
+	// Remove any stale socket
+	// from a previous launch.
+	os.Remove(filepath.Join(SocketDir, fmt.Sprintf("%s.sock", c.spec.Name)))
+	cmd := exec.Command(c.spec.Path, c.spec.Args...)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%s", AddressEnv, c.address()))
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := reaper.Default.Start(cmd); err != nil {
+		return nil, err
+	}
+	log.Named("external").Info(fmt.Sprintf("launched external plugin %s with pid %d", c.spec.Name, cmd.Process.Pid))
+	return cmd, nil
+}
+
+// connect dials the plugin, checks the protocol
+// version it speaks and configures it.
+func (c *client) connect(ctx context.Context, cfg config.Config) (*grpc.ClientConn, *HandshakeResponse, error) {
+	// Gaffer credentials are not sent to plugins
+	opts, err := config.Config{}.DailOpts()
+	if err != nil {
+		return nil, nil, err
+	}
+	dctx, cancel := context.WithTimeout(ctx, DialTimeout)
+	defer cancel()
+	conn, err := grpc.DialContext(dctx, c.address(), append(opts, grpc.WithBlock())...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to external plugin %s: %s", c.spec.Name, err.Error())
+	}
+	pc := NewPluginClient(conn)
+	handshake, err := pc.Handshake(dctx, &HandshakeRequest{Version: Version})
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	if handshake.Version != Version {
+		conn.Close()
+		return nil, nil, fmt.Errorf("external plugin %s speaks protocol version %d, not %d", c.spec.Name, handshake.Version, Version)
+	}
+	// Nor are they in its configuration
+	cfg.User = ""
+	raw, err := json.Marshal(cfg)
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	if _, err := pc.Configure(dctx, &ConfigureRequest{Config: raw}); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	return conn, handshake, nil
+}
+
+// failed records the error returned by run.
+func (c *client) failed(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err == nil {
+		c.state = Stopped
+		return
+	}
+	c.state = Failed
+	c.err = err
+	c.restarts++
+}
+
+// proxies reports if the plugin serves
+// the fully qualified service.
+func (c *client) proxies(service string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return false
+	}
+	for _, name := range c.handshake.Services {
+		if name == service {
+			return true
+		}
+	}
+	return false
+}
+
+// describe returns the state of the plugin.
+func (c *client) describe() *Description {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	desc := &Description{
+		Name:      c.spec.Name,
+		State:     c.state,
+		Restarts:  c.restarts,
+		Handshake: c.handshake,
+	}
+	if c.err != nil {
+		desc.Error = c.err.Error()
+	}
+	return desc
+}
+
+// connection returns the connection
+// to the plugin if it is running.
+func (c *client) connection() (*grpc.ClientConn, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return nil, fmt.Errorf("external plugin %s is not running", c.spec.Name)
+	}
+	return c.conn, nil
+}
+
+// subscriptions returns a filter matching the
+// event types the plugin subscribed to.
+func subscriptions(handshake *HandshakeResponse) event.Filter {
+	return func(evt event.Event) bool {
+		if len(handshake.Subscribe) == 0 {
+			return true
+		}
+		for _, name := range handshake.Subscribe {
+			if name == evt.Type {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// terminate sends SIGTERM to a launched plugin
+// and SIGKILL if it does not exit in time.
+func terminate(cmd *exec.Cmd, exited chan int) {
+	cmd.Process.Signal(syscall.SIGTERM)
+	select {
+	case <-exited:
+	case <-time.After(StopTimeout):
+		cmd.Process.Kill()
+		<-exited
+	}
+}
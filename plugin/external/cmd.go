@@ -0,0 +1,84 @@
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/jawher/mow.cli"
+	"github.com/mesanine/gaffer/config"
+	"github.com/mesanine/gaffer/host"
+	"github.com/mesanine/gaffer/util"
+	"google.golang.org/grpc"
+	"strconv"
+	"strings"
+)
+
+func (e *External) CLI(cfg *config.Config) cli.CmdInitializer {
+	return func(cmd *cli.Cmd) {
+		cmd.Command("list", "List external plugins and their commands", func(cmd *cli.Cmd) {
+			cmd.Action = func() {
+				util.Remote(*cfg, func(h *host.Host, conn *grpc.ClientConn) (interface{}, error) {
+					req := &DescribeRequest{Host: h}
+					return NewRPCClient(conn).Describe(context.Background(), req, cfg.CallOpts()...)
+				})
+			}
+		})
+		cmd.Command("call", "Call a command of an external plugin", func(cmd *cli.Cmd) {
+			cmd.Spec = "PLUGIN COMMAND [ARGS...]"
+			var (
+				name = cmd.String(cli.StringArg{
+					Name: "PLUGIN",
+					Desc: "External plugin name",
+				})
+				command = cmd.String(cli.StringArg{
+					Name: "COMMAND",
+					Desc: "Command name",
+				})
+				args = cmd.Strings(cli.StringsArg{
+					Name: "ARGS",
+					Desc: "Command arguments",
+				})
+			)
+			cmd.Action = func() {
+				util.Remote(*cfg, func(h *host.Host, conn *grpc.ClientConn) (interface{}, error) {
+					req := &CallRequest{Host: h, Plugin: *name, Command: *command, Args: *args}
+					resp, err := NewRPCClient(conn).Call(context.Background(), req, cfg.CallOpts()...)
+					if err != nil {
+						return nil, err
+					}
+					if len(resp.Output) == 0 {
+						return nil, nil
+					}
+					return json.RawMessage(resp.Output), nil
+				})
+			}
+		})
+	}
+}
+
+// Table returns the external plugins
+// as rows of a table.
+func (r *DescribeResponse) Table(wide bool) ([]string, [][]string) {
+	headers := []string{"NAME", "STATE", "RESTARTS", "ERROR"}
+	if wide {
+		headers = append(headers, "VERSION", "SERVICES", "COMMANDS")
+	}
+	rows := [][]string{}
+	for _, desc := range r.Plugins {
+		row := []string{desc.Name, desc.State, strconv.Itoa(int(desc.Restarts)), desc.Error}
+		if wide {
+			var version, services, commands string
+			if desc.Handshake != nil {
+				version = desc.Handshake.PluginVersion
+				services = strings.Join(desc.Handshake.Services, ",")
+				names := []string{}
+				for _, command := range desc.Handshake.Commands {
+					names = append(names, command.Name)
+				}
+				commands = strings.Join(names, ",")
+			}
+			row = append(row, version, services, commands)
+		}
+		rows = append(rows, row)
+	}
+	return headers, rows
+}
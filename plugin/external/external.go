@@ -0,0 +1,207 @@
+/*
+package external runs out of process plugins. Each
+plugin listed in the configuration is either launched
+by Gaffer, which passes it an address to serve on in
+the environment, or connected to at an address it is
+already serving on. Gaffer and the plugin speak the
+versioned Plugin gRPC protocol to configure, run and
+stop the plugin and to publish and subscribe to the
+EventBus. Any services the plugin serves itself are
+proxied through the Gaffer RPC server and its CLI
+commands are exposed with the RPC service.
+*/
+package external
+
+import (
+	"context"
+	"fmt"
+	"github.com/cenkalti/backoff"
+	"github.com/mesanine/gaffer/config"
+	"github.com/mesanine/gaffer/event"
+	"github.com/mesanine/gaffer/host"
+	"github.com/mesanine/gaffer/log"
+	"google.golang.org/grpc"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Version of the plugin protocol
+	// spoken by Gaffer.
+	Version = 1
+	// SocketDir holds the sockets
+	// launched plugins serve on.
+	SocketDir = "/run/gaffer/plugins"
+	// AddressEnv is the environment variable
+	// holding the address a launched plugin
+	// must serve on.
+	AddressEnv = "GAFFER_PLUGIN_ADDRESS"
+	// DialTimeout is how long a plugin has
+	// to start serving and configure itself.
+	DialTimeout = 10 * time.Second
+	// StopTimeout is how long a plugin
+	// has to stop before it is killed.
+	StopTimeout = 10 * time.Second
+)
+
+// External runs each out of process plugin
+// restarting any which fail.
+type External struct {
+	config  config.Config
+	clients map[string]*client
+	err     chan error
+	stop    chan bool
+}
+
+func New() *External {
+	return &External{
+		clients: map[string]*client{},
+		err:     make(chan error, 1),
+		stop:    make(chan bool, 1),
+	}
+}
+
+func (e *External) Name() string { return "external" }
+
+func (e *External) Configure(cfg config.Config) error {
+	clients := map[string]*client{}
+	for _, spec := range cfg.External {
+		if spec.Name == "" {
+			return fmt.Errorf("external plugin has no name")
+		}
+		if _, ok := clients[spec.Name]; ok {
+			return fmt.Errorf("external plugin %s is configured twice", spec.Name)
+		}
+		if (spec.Path == "") == (spec.Address == "") {
+			return fmt.Errorf("external plugin %s needs either a path or an address", spec.Name)
+		}
+		clients[spec.Name] = newClient(spec)
+	}
+	e.config = cfg
+	e.clients = clients
+	return nil
+}
+
+func (e *External) Run(eb *event.EventBus) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := sync.WaitGroup{}
+	for _, c := range e.clients {
+		wg.Add(1)
+		go func(c *client) {
+			defer wg.Done()
+			e.supervise(ctx, eb, c)
+		}(c)
+	}
+	defer wg.Wait()
+	defer cancel()
+	select {
+	case err := <-e.err:
+		return err
+	case <-e.stop:
+		return nil
+	}
+}
+
+func (e *External) Stop() error {
+	e.stop <- true
+	return nil
+}
+
+func (e *External) RPC() *grpc.ServiceDesc { return &_RPC_serviceDesc }
+
+// supervise runs a plugin until ctx is canceled
+// restarting it with a backoff each time it fails.
+func (e *External) supervise(ctx context.Context, eb *event.EventBus, c *client) {
+	bo := backoff.NewExponentialBackOff()
+	bo.MaxElapsedTime = 0
+	for {
+		started := time.Now()
+		err := c.run(ctx, eb, e.config)
+		if ctx.Err() != nil {
+			c.failed(nil)
+			return
+		}
+		c.failed(err)
+		log.Named("external").Warn(fmt.Sprintf("external plugin %s failed: %s", c.spec.Name, err.Error()))
+		// Plugins which ran for a while
+		// are restarted immediately.
+		if time.Since(started) > bo.MaxInterval {
+			bo.Reset()
+		}
+		select {
+		case <-ctx.Done():
+			c.failed(nil)
+			return
+		case <-time.After(bo.NextBackOff()):
+		}
+	}
+}
+
+// Proxies implements the plugin.Proxy interface.
+func (e *External) Proxies(service string) bool {
+	_, ok := e.proxy(service)
+	return ok
+}
+
+// Forward implements the plugin.Proxy interface.
+func (e *External) Forward(method string, stream grpc.ServerStream) error {
+	service := serviceName(method)
+	c, ok := e.proxy(service)
+	if !ok {
+		return fmt.Errorf("no external plugin serves %s", service)
+	}
+	conn, err := c.connection()
+	if err != nil {
+		return err
+	}
+	return forward(conn, method, stream)
+}
+
+func (e *External) proxy(service string) (*client, bool) {
+	for _, c := range e.clients {
+		if c.proxies(service) {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// Describe returns the state, handshake
+// and commands of each external plugin.
+func (e *External) Describe(ctx context.Context, req *DescribeRequest) (*DescribeResponse, error) {
+	if err := host.Check(req.Host); err != nil {
+		return nil, err
+	}
+	resp := &DescribeResponse{Plugins: []*Description{}}
+	for _, c := range e.clients {
+		resp.Plugins = append(resp.Plugins, c.describe())
+	}
+	sort.Slice(resp.Plugins, func(i, j int) bool {
+		return resp.Plugins[i].Name < resp.Plugins[j].Name
+	})
+	return resp, nil
+}
+
+// Call calls a command of an external plugin.
+func (e *External) Call(ctx context.Context, req *CallRequest) (*CommandResponse, error) {
+	if err := host.Check(req.Host); err != nil {
+		return nil, err
+	}
+	c, ok := e.clients[req.Plugin]
+	if !ok {
+		return nil, fmt.Errorf("unknown external plugin %s", req.Plugin)
+	}
+	conn, err := c.connection()
+	if err != nil {
+		return nil, err
+	}
+	return NewPluginClient(conn).Command(ctx, &CommandRequest{Name: req.Command, Args: req.Args})
+}
+
+// serviceName returns the service
+// of a method like /pkg.Service/Method.
+func serviceName(method string) string {
+	return strings.SplitN(strings.TrimPrefix(method, "/"), "/", 2)[0]
+}
@@ -0,0 +1,773 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: github.com/mesanine/gaffer/plugin/external/external.proto
+
+/*
+Package external is a generated protocol buffer package.
+
+It is generated from these files:
+	github.com/mesanine/gaffer/plugin/external/external.proto
+
+It has these top-level messages:
+	HandshakeRequest
+	HandshakeResponse
+	Command
+	ConfigureRequest
+	ConfigureResponse
+	StopRequest
+	StopResponse
+	CommandRequest
+	CommandResponse
+	DescribeRequest
+	DescribeResponse
+	Description
+	CallRequest
+*/
+package external
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import host "github.com/mesanine/gaffer/host"
+import event "github.com/mesanine/gaffer/event"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type HandshakeRequest struct {
+	// Protocol version spoken by Gaffer
+	Version int32 `protobuf:"varint,1,opt,name=version" json:"version,omitempty"`
+}
+
+func (m *HandshakeRequest) Reset()                    { *m = HandshakeRequest{} }
+func (m *HandshakeRequest) String() string            { return proto.CompactTextString(m) }
+func (*HandshakeRequest) ProtoMessage()               {}
+func (*HandshakeRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+func (m *HandshakeRequest) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+type HandshakeResponse struct {
+	// Protocol version spoken by the plugin
+	Version int32  `protobuf:"varint,1,opt,name=version" json:"version,omitempty"`
+	Name    string `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	// Version of the plugin itself
+	PluginVersion string `protobuf:"bytes,3,opt,name=plugin_version,json=pluginVersion" json:"plugin_version,omitempty"`
+	// Fully qualified names of the gRPC services
+	// served by the plugin which are proxied
+	// through the Gaffer RPC server.
+	Services []string `protobuf:"bytes,4,rep,name=services" json:"services,omitempty"`
+	// Event types sent to the plugin,
+	// all events if empty.
+	Subscribe []string `protobuf:"bytes,5,rep,name=subscribe" json:"subscribe,omitempty"`
+	// Commands exposed to the Gaffer CLI
+	Commands []*Command `protobuf:"bytes,6,rep,name=commands" json:"commands,omitempty"`
+}
+
+func (m *HandshakeResponse) Reset()                    { *m = HandshakeResponse{} }
+func (m *HandshakeResponse) String() string            { return proto.CompactTextString(m) }
+func (*HandshakeResponse) ProtoMessage()               {}
+func (*HandshakeResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *HandshakeResponse) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *HandshakeResponse) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *HandshakeResponse) GetPluginVersion() string {
+	if m != nil {
+		return m.PluginVersion
+	}
+	return ""
+}
+
+func (m *HandshakeResponse) GetServices() []string {
+	if m != nil {
+		return m.Services
+	}
+	return nil
+}
+
+func (m *HandshakeResponse) GetSubscribe() []string {
+	if m != nil {
+		return m.Subscribe
+	}
+	return nil
+}
+
+func (m *HandshakeResponse) GetCommands() []*Command {
+	if m != nil {
+		return m.Commands
+	}
+	return nil
+}
+
+// Command describes a CLI command.
+type Command struct {
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Desc string `protobuf:"bytes,2,opt,name=desc" json:"desc,omitempty"`
+	// Names of positional arguments
+	Args []string `protobuf:"bytes,3,rep,name=args" json:"args,omitempty"`
+}
+
+func (m *Command) Reset()                    { *m = Command{} }
+func (m *Command) String() string            { return proto.CompactTextString(m) }
+func (*Command) ProtoMessage()               {}
+func (*Command) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *Command) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Command) GetDesc() string {
+	if m != nil {
+		return m.Desc
+	}
+	return ""
+}
+
+func (m *Command) GetArgs() []string {
+	if m != nil {
+		return m.Args
+	}
+	return nil
+}
+
+type ConfigureRequest struct {
+	// JSON encoded Gaffer configuration
+	Config []byte `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+}
+
+func (m *ConfigureRequest) Reset()                    { *m = ConfigureRequest{} }
+func (m *ConfigureRequest) String() string            { return proto.CompactTextString(m) }
+func (*ConfigureRequest) ProtoMessage()               {}
+func (*ConfigureRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *ConfigureRequest) GetConfig() []byte {
+	if m != nil {
+		return m.Config
+	}
+	return nil
+}
+
+type ConfigureResponse struct {
+}
+
+func (m *ConfigureResponse) Reset()                    { *m = ConfigureResponse{} }
+func (m *ConfigureResponse) String() string            { return proto.CompactTextString(m) }
+func (*ConfigureResponse) ProtoMessage()               {}
+func (*ConfigureResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+type StopRequest struct {
+}
+
+func (m *StopRequest) Reset()                    { *m = StopRequest{} }
+func (m *StopRequest) String() string            { return proto.CompactTextString(m) }
+func (*StopRequest) ProtoMessage()               {}
+func (*StopRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+type StopResponse struct {
+}
+
+func (m *StopResponse) Reset()                    { *m = StopResponse{} }
+func (m *StopResponse) String() string            { return proto.CompactTextString(m) }
+func (*StopResponse) ProtoMessage()               {}
+func (*StopResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+type CommandRequest struct {
+	Name string   `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Args []string `protobuf:"bytes,2,rep,name=args" json:"args,omitempty"`
+}
+
+func (m *CommandRequest) Reset()                    { *m = CommandRequest{} }
+func (m *CommandRequest) String() string            { return proto.CompactTextString(m) }
+func (*CommandRequest) ProtoMessage()               {}
+func (*CommandRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+func (m *CommandRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *CommandRequest) GetArgs() []string {
+	if m != nil {
+		return m.Args
+	}
+	return nil
+}
+
+type CommandResponse struct {
+	// JSON encoded output
+	Output []byte `protobuf:"bytes,1,opt,name=output,proto3" json:"output,omitempty"`
+}
+
+func (m *CommandResponse) Reset()                    { *m = CommandResponse{} }
+func (m *CommandResponse) String() string            { return proto.CompactTextString(m) }
+func (*CommandResponse) ProtoMessage()               {}
+func (*CommandResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+func (m *CommandResponse) GetOutput() []byte {
+	if m != nil {
+		return m.Output
+	}
+	return nil
+}
+
+type DescribeRequest struct {
+	Host *host.Host `protobuf:"bytes,1,opt,name=host" json:"host,omitempty"`
+}
+
+func (m *DescribeRequest) Reset()                    { *m = DescribeRequest{} }
+func (m *DescribeRequest) String() string            { return proto.CompactTextString(m) }
+func (*DescribeRequest) ProtoMessage()               {}
+func (*DescribeRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+func (m *DescribeRequest) GetHost() *host.Host {
+	if m != nil {
+		return m.Host
+	}
+	return nil
+}
+
+type DescribeResponse struct {
+	Plugins []*Description `protobuf:"bytes,1,rep,name=plugins" json:"plugins,omitempty"`
+}
+
+func (m *DescribeResponse) Reset()                    { *m = DescribeResponse{} }
+func (m *DescribeResponse) String() string            { return proto.CompactTextString(m) }
+func (*DescribeResponse) ProtoMessage()               {}
+func (*DescribeResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+func (m *DescribeResponse) GetPlugins() []*Description {
+	if m != nil {
+		return m.Plugins
+	}
+	return nil
+}
+
+// Description is the state of an
+// external plugin and its handshake.
+type Description struct {
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// Running, Failed or Stopped
+	State     string             `protobuf:"bytes,2,opt,name=state" json:"state,omitempty"`
+	Error     string             `protobuf:"bytes,3,opt,name=error" json:"error,omitempty"`
+	Restarts  uint32             `protobuf:"varint,4,opt,name=restarts" json:"restarts,omitempty"`
+	Handshake *HandshakeResponse `protobuf:"bytes,5,opt,name=handshake" json:"handshake,omitempty"`
+}
+
+func (m *Description) Reset()                    { *m = Description{} }
+func (m *Description) String() string            { return proto.CompactTextString(m) }
+func (*Description) ProtoMessage()               {}
+func (*Description) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+func (m *Description) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Description) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *Description) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *Description) GetRestarts() uint32 {
+	if m != nil {
+		return m.Restarts
+	}
+	return 0
+}
+
+func (m *Description) GetHandshake() *HandshakeResponse {
+	if m != nil {
+		return m.Handshake
+	}
+	return nil
+}
+
+type CallRequest struct {
+	Host    *host.Host `protobuf:"bytes,1,opt,name=host" json:"host,omitempty"`
+	Plugin  string     `protobuf:"bytes,2,opt,name=plugin" json:"plugin,omitempty"`
+	Command string     `protobuf:"bytes,3,opt,name=command" json:"command,omitempty"`
+	Args    []string   `protobuf:"bytes,4,rep,name=args" json:"args,omitempty"`
+}
+
+func (m *CallRequest) Reset()                    { *m = CallRequest{} }
+func (m *CallRequest) String() string            { return proto.CompactTextString(m) }
+func (*CallRequest) ProtoMessage()               {}
+func (*CallRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+func (m *CallRequest) GetHost() *host.Host {
+	if m != nil {
+		return m.Host
+	}
+	return nil
+}
+
+func (m *CallRequest) GetPlugin() string {
+	if m != nil {
+		return m.Plugin
+	}
+	return ""
+}
+
+func (m *CallRequest) GetCommand() string {
+	if m != nil {
+		return m.Command
+	}
+	return ""
+}
+
+func (m *CallRequest) GetArgs() []string {
+	if m != nil {
+		return m.Args
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*HandshakeRequest)(nil), "external.HandshakeRequest")
+	proto.RegisterType((*HandshakeResponse)(nil), "external.HandshakeResponse")
+	proto.RegisterType((*Command)(nil), "external.Command")
+	proto.RegisterType((*ConfigureRequest)(nil), "external.ConfigureRequest")
+	proto.RegisterType((*ConfigureResponse)(nil), "external.ConfigureResponse")
+	proto.RegisterType((*StopRequest)(nil), "external.StopRequest")
+	proto.RegisterType((*StopResponse)(nil), "external.StopResponse")
+	proto.RegisterType((*CommandRequest)(nil), "external.CommandRequest")
+	proto.RegisterType((*CommandResponse)(nil), "external.CommandResponse")
+	proto.RegisterType((*DescribeRequest)(nil), "external.DescribeRequest")
+	proto.RegisterType((*DescribeResponse)(nil), "external.DescribeResponse")
+	proto.RegisterType((*Description)(nil), "external.Description")
+	proto.RegisterType((*CallRequest)(nil), "external.CallRequest")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for Plugin service
+
+type PluginClient interface {
+	Handshake(ctx context.Context, in *HandshakeRequest, opts ...grpc.CallOption) (*HandshakeResponse, error)
+	Configure(ctx context.Context, in *ConfigureRequest, opts ...grpc.CallOption) (*ConfigureResponse, error)
+	// Run streams subscribed events to the plugin
+	// and events published by the plugin back to
+	// Gaffer. The plugin is running until the
+	// stream ends.
+	Run(ctx context.Context, opts ...grpc.CallOption) (Plugin_RunClient, error)
+	Stop(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (*StopResponse, error)
+	Command(ctx context.Context, in *CommandRequest, opts ...grpc.CallOption) (*CommandResponse, error)
+}
+
+type pluginClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewPluginClient(cc *grpc.ClientConn) PluginClient {
+	return &pluginClient{cc}
+}
+
+func (c *pluginClient) Handshake(ctx context.Context, in *HandshakeRequest, opts ...grpc.CallOption) (*HandshakeResponse, error) {
+	out := new(HandshakeResponse)
+	err := grpc.Invoke(ctx, "/external.Plugin/Handshake", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pluginClient) Configure(ctx context.Context, in *ConfigureRequest, opts ...grpc.CallOption) (*ConfigureResponse, error) {
+	out := new(ConfigureResponse)
+	err := grpc.Invoke(ctx, "/external.Plugin/Configure", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pluginClient) Run(ctx context.Context, opts ...grpc.CallOption) (Plugin_RunClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Plugin_serviceDesc.Streams[0], c.cc, "/external.Plugin/Run", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &pluginRunClient{stream}
+	return x, nil
+}
+
+type Plugin_RunClient interface {
+	Send(*event.Event) error
+	Recv() (*event.Event, error)
+	grpc.ClientStream
+}
+
+type pluginRunClient struct {
+	grpc.ClientStream
+}
+
+func (x *pluginRunClient) Send(m *event.Event) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *pluginRunClient) Recv() (*event.Event, error) {
+	m := new(event.Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *pluginClient) Stop(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (*StopResponse, error) {
+	out := new(StopResponse)
+	err := grpc.Invoke(ctx, "/external.Plugin/Stop", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pluginClient) Command(ctx context.Context, in *CommandRequest, opts ...grpc.CallOption) (*CommandResponse, error) {
+	out := new(CommandResponse)
+	err := grpc.Invoke(ctx, "/external.Plugin/Command", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Plugin service
+
+type PluginServer interface {
+	Handshake(context.Context, *HandshakeRequest) (*HandshakeResponse, error)
+	Configure(context.Context, *ConfigureRequest) (*ConfigureResponse, error)
+	// Run streams subscribed events to the plugin
+	// and events published by the plugin back to
+	// Gaffer. The plugin is running until the
+	// stream ends.
+	Run(Plugin_RunServer) error
+	Stop(context.Context, *StopRequest) (*StopResponse, error)
+	Command(context.Context, *CommandRequest) (*CommandResponse, error)
+}
+
+func RegisterPluginServer(s *grpc.Server, srv PluginServer) {
+	s.RegisterService(&_Plugin_serviceDesc, srv)
+}
+
+func _Plugin_Handshake_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HandshakeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginServer).Handshake(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/external.Plugin/Handshake",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginServer).Handshake(ctx, req.(*HandshakeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Plugin_Configure_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfigureRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginServer).Configure(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/external.Plugin/Configure",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginServer).Configure(ctx, req.(*ConfigureRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Plugin_Run_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PluginServer).Run(&pluginRunServer{stream})
+}
+
+type Plugin_RunServer interface {
+	Send(*event.Event) error
+	Recv() (*event.Event, error)
+	grpc.ServerStream
+}
+
+type pluginRunServer struct {
+	grpc.ServerStream
+}
+
+func (x *pluginRunServer) Send(m *event.Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *pluginRunServer) Recv() (*event.Event, error) {
+	m := new(event.Event)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Plugin_Stop_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginServer).Stop(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/external.Plugin/Stop",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginServer).Stop(ctx, req.(*StopRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Plugin_Command_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommandRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginServer).Command(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/external.Plugin/Command",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginServer).Command(ctx, req.(*CommandRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Plugin_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "external.Plugin",
+	HandlerType: (*PluginServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Handshake",
+			Handler:    _Plugin_Handshake_Handler,
+		},
+		{
+			MethodName: "Configure",
+			Handler:    _Plugin_Configure_Handler,
+		},
+		{
+			MethodName: "Stop",
+			Handler:    _Plugin_Stop_Handler,
+		},
+		{
+			MethodName: "Command",
+			Handler:    _Plugin_Command_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Run",
+			Handler:       _Plugin_Run_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "github.com/mesanine/gaffer/plugin/external/external.proto",
+}
+
+// Client API for RPC service
+
+type RPCClient interface {
+	Describe(ctx context.Context, in *DescribeRequest, opts ...grpc.CallOption) (*DescribeResponse, error)
+	Call(ctx context.Context, in *CallRequest, opts ...grpc.CallOption) (*CommandResponse, error)
+}
+
+type rPCClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewRPCClient(cc *grpc.ClientConn) RPCClient {
+	return &rPCClient{cc}
+}
+
+func (c *rPCClient) Describe(ctx context.Context, in *DescribeRequest, opts ...grpc.CallOption) (*DescribeResponse, error) {
+	out := new(DescribeResponse)
+	err := grpc.Invoke(ctx, "/external.RPC/Describe", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rPCClient) Call(ctx context.Context, in *CallRequest, opts ...grpc.CallOption) (*CommandResponse, error) {
+	out := new(CommandResponse)
+	err := grpc.Invoke(ctx, "/external.RPC/Call", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for RPC service
+
+type RPCServer interface {
+	Describe(context.Context, *DescribeRequest) (*DescribeResponse, error)
+	Call(context.Context, *CallRequest) (*CommandResponse, error)
+}
+
+func RegisterRPCServer(s *grpc.Server, srv RPCServer) {
+	s.RegisterService(&_RPC_serviceDesc, srv)
+}
+
+func _RPC_Describe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DescribeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCServer).Describe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/external.RPC/Describe",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCServer).Describe(ctx, req.(*DescribeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RPC_Call_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CallRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCServer).Call(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/external.RPC/Call",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCServer).Call(ctx, req.(*CallRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _RPC_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "external.RPC",
+	HandlerType: (*RPCServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Describe",
+			Handler:    _RPC_Describe_Handler,
+		},
+		{
+			MethodName: "Call",
+			Handler:    _RPC_Call_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "github.com/mesanine/gaffer/plugin/external/external.proto",
+}
+
+func init() {
+	proto.RegisterFile("github.com/mesanine/gaffer/plugin/external/external.proto", fileDescriptor0)
+}
+
+var fileDescriptor0 = []byte{
+	// 628 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x54, 0x5b, 0x6e, 0x13, 0x3d,
+	0x14, 0xee, 0x34, 0x93, 0x34, 0x39, 0xe9, 0xd5, 0xff, 0x4f, 0x35, 0x9d, 0x22, 0x14, 0x59, 0x42,
+	0x84, 0xaa, 0x24, 0x10, 0x1e, 0xa0, 0x3c, 0x21, 0x85, 0xa2, 0x3e, 0x56, 0x46, 0xe2, 0x15, 0x4d,
+	0x52, 0x37, 0x19, 0x91, 0xd8, 0x83, 0xed, 0xa9, 0x58, 0x01, 0x2b, 0x61, 0x19, 0x6c, 0x82, 0x1d,
+	0x21, 0x5f, 0x67, 0x48, 0xd3, 0x88, 0x97, 0x91, 0xbf, 0x73, 0xf3, 0x77, 0xce, 0xf9, 0x3c, 0x70,
+	0x31, 0xcb, 0xd5, 0xbc, 0x9c, 0x0c, 0xa6, 0x7c, 0x39, 0x5c, 0x52, 0x99, 0xb1, 0x9c, 0xd1, 0xe1,
+	0x2c, 0xbb, 0xbd, 0xa5, 0x62, 0x58, 0x2c, 0xca, 0x59, 0xce, 0x86, 0xf4, 0xbb, 0xa2, 0x82, 0x65,
+	0x8b, 0x70, 0x18, 0x14, 0x82, 0x2b, 0x8e, 0xda, 0x1e, 0xa7, 0x67, 0x1b, 0x8a, 0xcc, 0xb9, 0x54,
+	0xe6, 0x63, 0xb3, 0xd2, 0xf3, 0x0d, 0xb1, 0xf4, 0x8e, 0x32, 0x65, 0xbf, 0x36, 0x1a, 0x9f, 0xc3,
+	0xe1, 0x55, 0xc6, 0x6e, 0xe4, 0x3c, 0xfb, 0x4a, 0x09, 0xfd, 0x56, 0x52, 0xa9, 0x50, 0x02, 0x3b,
+	0x77, 0x54, 0xc8, 0x9c, 0xb3, 0x24, 0xea, 0x45, 0xfd, 0x26, 0xf1, 0x10, 0xff, 0x8e, 0xe0, 0xa8,
+	0x16, 0x2e, 0x0b, 0xce, 0x24, 0x7d, 0x38, 0x1e, 0x21, 0x88, 0x59, 0xb6, 0xa4, 0xc9, 0x76, 0x2f,
+	0xea, 0x77, 0x88, 0x39, 0xa3, 0xa7, 0xb0, 0x6f, 0xfb, 0xfe, 0xe2, 0x93, 0x1a, 0xc6, 0xbb, 0x67,
+	0xad, 0x9f, 0x5d, 0x6a, 0x0a, 0x6d, 0x49, 0xc5, 0x5d, 0x3e, 0xa5, 0x32, 0x89, 0x7b, 0x8d, 0x7e,
+	0x87, 0x04, 0x8c, 0x1e, 0x43, 0x47, 0x96, 0x13, 0x39, 0x15, 0xf9, 0x84, 0x26, 0x4d, 0xe3, 0xac,
+	0x0c, 0xe8, 0x05, 0xb4, 0xa7, 0x7c, 0xb9, 0xd4, 0x34, 0x93, 0x56, 0xaf, 0xd1, 0xef, 0x8e, 0x8e,
+	0x06, 0x61, 0xb2, 0x63, 0xeb, 0x21, 0x21, 0x04, 0x5f, 0xc2, 0x8e, 0x33, 0x06, 0xba, 0x51, 0x8d,
+	0x2e, 0x82, 0xf8, 0x86, 0xca, 0xa9, 0x6f, 0x41, 0x9f, 0xb5, 0x2d, 0x13, 0x33, 0x99, 0x34, 0xcc,
+	0xd5, 0xe6, 0x8c, 0xcf, 0xe0, 0x70, 0xcc, 0xd9, 0x6d, 0x3e, 0x2b, 0x45, 0x18, 0xe4, 0x31, 0xb4,
+	0xa6, 0xc6, 0x66, 0x2a, 0xee, 0x12, 0x87, 0xf0, 0x7f, 0x70, 0x54, 0x8b, 0xb5, 0x53, 0xc4, 0x7b,
+	0xd0, 0xfd, 0xa4, 0x78, 0xe1, 0x72, 0xf1, 0x3e, 0xec, 0x5a, 0xe8, 0xdc, 0x6f, 0x61, 0xdf, 0x73,
+	0x77, 0xd5, 0x1f, 0x60, 0x6b, 0x98, 0x6d, 0xd7, 0x98, 0x3d, 0x87, 0x83, 0x90, 0xe9, 0x36, 0x76,
+	0x0c, 0x2d, 0x5e, 0xaa, 0xa2, 0x54, 0x9e, 0x98, 0x45, 0xf8, 0x15, 0x1c, 0x7c, 0xa0, 0x76, 0x8c,
+	0xfe, 0x96, 0x27, 0x10, 0x6b, 0x71, 0x99, 0xc0, 0xee, 0x08, 0x06, 0x1a, 0x0c, 0xae, 0xb8, 0x54,
+	0xc4, 0xd8, 0xf1, 0x18, 0x0e, 0xab, 0x14, 0x57, 0x7e, 0x08, 0x3b, 0x76, 0x99, 0x32, 0x89, 0xcc,
+	0x02, 0x1e, 0x55, 0x0b, 0xb0, 0xc1, 0x85, 0xca, 0x39, 0x23, 0x3e, 0x0a, 0xff, 0x8c, 0xa0, 0x5b,
+	0x73, 0xac, 0x6d, 0xed, 0x7f, 0x68, 0x4a, 0x95, 0x29, 0x2f, 0x26, 0x0b, 0xb4, 0x95, 0x0a, 0xc1,
+	0x85, 0x13, 0x91, 0x05, 0x5a, 0x3c, 0x82, 0x4a, 0x95, 0x09, 0xa5, 0xc5, 0x13, 0xf5, 0xf7, 0x48,
+	0xc0, 0xe8, 0x02, 0x3a, 0x73, 0x2f, 0xe1, 0xa4, 0x69, 0xba, 0x3a, 0xad, 0xe8, 0xdd, 0x53, 0x37,
+	0xa9, 0xa2, 0xb1, 0x84, 0xee, 0x38, 0x5b, 0x2c, 0xfe, 0x71, 0x34, 0x7a, 0xca, 0xb6, 0x41, 0x47,
+	0xd9, 0x21, 0xfd, 0x5e, 0x9c, 0xfa, 0x1c, 0x6b, 0x0f, 0xc3, 0xfa, 0xe2, 0x6a, 0x7d, 0xa3, 0x5f,
+	0xdb, 0xd0, 0xba, 0xb6, 0x89, 0x1f, 0xa1, 0x13, 0xf8, 0xa1, 0x74, 0x2d, 0x69, 0xc3, 0x2c, 0xdd,
+	0xd4, 0x10, 0xde, 0xd2, 0x75, 0x82, 0xfe, 0xea, 0x75, 0x56, 0x05, 0x9c, 0x9e, 0xae, 0xf5, 0x85,
+	0x3a, 0xcf, 0xa0, 0x41, 0x4a, 0x86, 0x76, 0x07, 0xf6, 0x8f, 0x72, 0xa9, 0xbf, 0xe9, 0x5f, 0x08,
+	0x6f, 0xf5, 0xa3, 0x97, 0x11, 0x7a, 0x03, 0xb1, 0x16, 0x33, 0xaa, 0xe9, 0xa0, 0xa6, 0xf5, 0xf4,
+	0x78, 0xd5, 0x1c, 0x6e, 0x78, 0x5f, 0x3d, 0xce, 0xe4, 0xfe, 0x23, 0x76, 0xe9, 0x27, 0x6b, 0x3c,
+	0xbe, 0xc2, 0xe8, 0x47, 0x04, 0x0d, 0x72, 0x3d, 0x46, 0x63, 0x68, 0x7b, 0x9d, 0xa2, 0x93, 0x55,
+	0x39, 0x06, 0xb9, 0xa7, 0xe9, 0x3a, 0x57, 0xa0, 0xf3, 0x0e, 0x62, 0x2d, 0x80, 0x7a, 0x1f, 0x35,
+	0x41, 0x6c, 0x24, 0x32, 0x69, 0x99, 0x1f, 0xee, 0xeb, 0x3f, 0x03, 0x00, 0xa5, 0x74, 0xab, 0x79,
+	0x11, 0x06, 0x00, 0x00,
+}
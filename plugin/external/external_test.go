@@ -0,0 +1,159 @@
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/mesanine/gaffer/config"
+	"github.com/mesanine/gaffer/event"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/transport"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mockPlugin publishes an event for each event
+// it receives and serves the RPC service itself.
+type mockPlugin struct {
+	mu     sync.Mutex
+	config []byte
+}
+
+func (m *mockPlugin) Handshake(ctx context.Context, req *HandshakeRequest) (*HandshakeResponse, error) {
+	return &HandshakeResponse{
+		Version:   Version,
+		Name:      "mock",
+		Services:  []string{"external.RPC"},
+		Subscribe: []string{"PING"},
+		Commands:  []*Command{{Name: "echo", Args: []string{"TEXT"}}},
+	}, nil
+}
+
+func (m *mockPlugin) Configure(ctx context.Context, req *ConfigureRequest) (*ConfigureResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config = req.Config
+	return &ConfigureResponse{}, nil
+}
+
+func (m *mockPlugin) Run(stream Plugin_RunServer) error {
+	for {
+		evt, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(&event.Event{Type: "PONG", Id: evt.Id}); err != nil {
+			return err
+		}
+	}
+}
+
+func (m *mockPlugin) Stop(ctx context.Context, req *StopRequest) (*StopResponse, error) {
+	return &StopResponse{}, nil
+}
+
+func (m *mockPlugin) Command(ctx context.Context, req *CommandRequest) (*CommandResponse, error) {
+	return &CommandResponse{Output: []byte(fmt.Sprintf("%q", req.Args[0]))}, nil
+}
+
+func (m *mockPlugin) Describe(ctx context.Context, req *DescribeRequest) (*DescribeResponse, error) {
+	return &DescribeResponse{Plugins: []*Description{{Name: "proxied"}}}, nil
+}
+
+func (m *mockPlugin) Call(ctx context.Context, req *CallRequest) (*CommandResponse, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func serve(t *testing.T, dir, name string, register func(*grpc.Server), opts ...grpc.ServerOption) string {
+	path := filepath.Join(dir, name)
+	listener, err := net.Listen("unix", path)
+	assert.NoError(t, err)
+	server := grpc.NewServer(opts...)
+	register(server)
+	go server.Serve(listener)
+	return fmt.Sprintf("unix://%s", path)
+}
+
+func TestExternal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gaffer-external")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	mock := &mockPlugin{}
+	address := serve(t, dir, "mock.sock", func(s *grpc.Server) {
+		RegisterPluginServer(s, mock)
+		RegisterRPCServer(s, mock)
+	})
+	cfg := config.Config{User: "admin:secret", External: []config.External{{Name: "mock", Address: address}}}
+	e := New()
+	assert.NoError(t, e.Configure(cfg))
+	eb := event.NewEventBus()
+	eb.Start()
+	defer eb.Stop()
+	sub := event.NewSubscriber()
+	eb.Subscribe(sub)
+	go e.Run(eb)
+	defer e.Stop()
+	// Wait for the handshake
+	for i := 0; i < 100 && !e.Proxies("external.RPC"); i++ {
+		time.Sleep(50 * time.Millisecond)
+	}
+	assert.True(t, e.Proxies("external.RPC"))
+	assert.False(t, e.Proxies("system.RPC"))
+	// Credentials are not sent to the plugin
+	mock.mu.Lock()
+	configured := config.Config{}
+	assert.NoError(t, json.Unmarshal(mock.config, &configured))
+	assert.NotContains(t, string(mock.config), "secret")
+	mock.mu.Unlock()
+	assert.Empty(t, configured.User)
+	assert.Equal(t, "mock", configured.External[0].Name)
+	eb.Push(event.Event{Type: "IGNORED", Id: "0"})
+	eb.Push(event.Event{Type: "PING", Id: "1"})
+	timeout := time.After(5 * time.Second)
+loop:
+	for {
+		select {
+		case evt := <-sub.Chan():
+			if evt.Type == "PONG" {
+				assert.Equal(t, "1", evt.Id)
+				break loop
+			}
+		case <-timeout:
+			t.Fatal("plugin did not publish an event")
+		}
+	}
+	resp, err := e.Call(context.Background(), &CallRequest{Plugin: "mock", Command: "echo", Args: []string{"hello"}})
+	assert.NoError(t, err)
+	assert.Equal(t, `"hello"`, string(resp.Output))
+	desc, err := e.Describe(context.Background(), &DescribeRequest{})
+	assert.NoError(t, err)
+	assert.Len(t, desc.Plugins, 1)
+	assert.Equal(t, Running, desc.Plugins[0].State)
+	assert.Equal(t, "echo", desc.Plugins[0].Handshake.Commands[0].Name)
+	// Calls to the plugin's own service are
+	// forwarded like the Gaffer RPC server.
+	front := serve(t, dir, "front.sock", func(*grpc.Server) {}, grpc.UnknownServiceHandler(
+		func(srv interface{}, stream grpc.ServerStream) error {
+			ts, _ := transport.StreamFromContext(stream.Context())
+			return e.Forward(ts.Method(), stream)
+		}),
+	)
+	opts, err := config.Config{}.DailOpts()
+	assert.NoError(t, err)
+	conn, err := grpc.Dial(front, opts...)
+	assert.NoError(t, err)
+	defer conn.Close()
+	proxied, err := NewRPCClient(conn).Describe(context.Background(), &DescribeRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, "proxied", proxied.Plugins[0].Name)
+}
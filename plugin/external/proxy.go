@@ -0,0 +1,84 @@
+package external
+
+import (
+	"github.com/mesanine/gaffer/user"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"io"
+)
+
+// frame is an opaque message forwarded
+// between streams without decoding it.
+type frame struct {
+	payload []byte
+}
+
+func (f *frame) Reset()         { f.payload = nil }
+func (f *frame) String() string { return string(f.payload) }
+func (f *frame) ProtoMessage()  {}
+
+// Marshal implements proto.Marshaler
+func (f *frame) Marshal() ([]byte, error) { return f.payload, nil }
+
+// Unmarshal implements proto.Unmarshaler
+func (f *frame) Unmarshal(raw []byte) error {
+	f.payload = append([]byte{}, raw...)
+	return nil
+}
+
+// forwardDesc describes every forwarded method as
+// bidirectional which is compatible with unary and
+// streaming methods alike on the wire.
+var forwardDesc = &grpc.StreamDesc{
+	ServerStreams: true,
+	ClientStreams: true,
+}
+
+// forward forwards the messages of a server stream
+// to method on conn and the replies back again.
+func forward(conn *grpc.ClientConn, method string, server grpc.ServerStream) error {
+	ctx, cancel := context.WithCancel(server.Context())
+	defer cancel()
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		md = md.Copy()
+		// Gaffer credentials are
+		// not passed to plugins.
+		delete(md, user.MetadataID)
+		delete(md, user.MetadataToken)
+		ctx = metadata.NewOutgoingContext(ctx, md)
+	}
+	client, err := grpc.NewClientStream(ctx, forwardDesc, conn, method)
+	if err != nil {
+		return err
+	}
+	go func() {
+		for {
+			f := &frame{}
+			if err := server.RecvMsg(f); err != nil {
+				// Canceling the context aborts the
+				// client stream on other errors.
+				if err == io.EOF {
+					client.CloseSend()
+				}
+				return
+			}
+			if err := client.SendMsg(f); err != nil {
+				return
+			}
+		}
+	}()
+	for {
+		f := &frame{}
+		if err := client.RecvMsg(f); err != nil {
+			server.SetTrailer(client.Trailer())
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if err := server.SendMsg(f); err != nil {
+			return err
+		}
+	}
+}
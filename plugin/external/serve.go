@@ -0,0 +1,30 @@
+package external
+
+import (
+	"fmt"
+	"github.com/mesanine/gaffer/plugin"
+	"google.golang.org/grpc"
+	"os"
+)
+
+// Serve serves a plugin on the address Gaffer
+// passes in the environment. Services the plugin
+// implements itself are registered by each register
+// function and proxied through the Gaffer RPC server
+// if they are listed in the handshake.
+func Serve(srv PluginServer, register ...func(*grpc.Server)) error {
+	address := os.Getenv(AddressEnv)
+	if address == "" {
+		return fmt.Errorf("%s is not set", AddressEnv)
+	}
+	listener, err := plugin.Listen(address)
+	if err != nil {
+		return err
+	}
+	server := grpc.NewServer()
+	RegisterPluginServer(server, srv)
+	for _, fn := range register {
+		fn(server)
+	}
+	return server.Serve(listener)
+}
@@ -0,0 +1,345 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/julienschmidt/httprouter"
+	"github.com/mesanine/gaffer/config"
+	"github.com/mesanine/gaffer/log"
+	"github.com/mesanine/ginit"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	marshaler   = &jsonpb.Marshaler{OrigName: true}
+	unmarshaler = &jsonpb.Unmarshaler{AllowUnknownFields: true}
+)
+
+// Gateway exposes the RPC methods of each
+// plugin as JSON endpoints over HTTP:
+//
+//	GET|POST /v1/<plugin>/<method>
+//	GET|POST /v1/<plugin>/<method>/:id
+//
+// Request messages are decoded from a JSON body,
+// the query string and the :id path parameter.
+// Server streaming methods respond with newline
+// delimited JSON or with Server-Sent Events if the
+// client accepts text/event-stream. Client streaming
+// methods read newline delimited JSON messages
+// from the request body.
+type Gateway struct {
+	auth     *Auth
+	server   *http.Server
+	listener net.Listener
+}
+
+func NewGateway(cfg config.Config) (*Gateway, error) {
+	auth, err := NewAuth(cfg)
+	if err != nil {
+		return nil, err
+	}
+	listener, err := Listen(cfg.HTTPAddress)
+	if err != nil {
+		return nil, err
+	}
+	return &Gateway{
+		auth:     auth,
+		server:   &http.Server{},
+		listener: listener,
+	}, nil
+}
+
+func (g *Gateway) Run(reg *Registry) error {
+	router := g.routes(reg)
+	g.server.Handler = router
+	err := g.server.Serve(g.listener)
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
+
+// routes routes the RPC methods of each plugin and of
+// the registry which is served as the plugins plugin.
+func (g *Gateway) routes(reg *Registry) *httprouter.Router {
+	router := httprouter.New()
+	for _, plugin := range reg.plugins {
+		if rpc, ok := plugin.(RPC); ok {
+			desc := rpc.RPC()
+			for _, method := range desc.Methods {
+				g.route(router, plugin.Name(), method.MethodName, g.unary(plugin, method))
+			}
+			for _, stream := range desc.Streams {
+				g.route(router, plugin.Name(), stream.StreamName, g.stream(plugin, stream))
+			}
+		}
+	}
+	desc := reg.RPC()
+	for _, method := range desc.Methods {
+		g.route(router, "plugins", method.MethodName, g.unary(reg, method))
+	}
+	return router
+}
+
+// Handle implements the ginit.Handler interface.
+func (g Gateway) Handle(sig os.Signal) error {
+	if ginit.Terminal(sig) {
+		return g.server.Close()
+	}
+	return nil
+}
+
+func (g *Gateway) route(router *httprouter.Router, name, method string, handle httprouter.Handle) {
+	path := fmt.Sprintf("/v1/%s/%s", name, strings.ToLower(method))
+	for _, p := range []string{path, path + "/:id"} {
+		router.GET(p, handle)
+		router.POST(p, handle)
+	}
+	log.Log.Info(fmt.Sprintf("plugin %s registers HTTP endpoint: %s", name, path))
+}
+
+func (g *Gateway) unary(srv interface{}, method grpc.MethodDesc) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		if err := g.auth.CheckRequest(r); err != nil {
+			writeError(w, err)
+			return
+		}
+		resp, err := method.Handler(srv, r.Context(), func(msg interface{}) error {
+			return decodeRequest(r, ps, msg)
+		}, nil)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		marshaler.Marshal(w, resp.(proto.Message))
+	}
+}
+
+func (g *Gateway) stream(srv interface{}, desc grpc.StreamDesc) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		if err := g.auth.CheckRequest(r); err != nil {
+			writeError(w, err)
+			return
+		}
+		stream := &httpStream{
+			w:    w,
+			r:    r,
+			ps:   ps,
+			desc: desc,
+			sse:  strings.Contains(r.Header.Get("Accept"), "text/event-stream"),
+		}
+		if err := desc.Handler(srv, stream); err != nil {
+			if !stream.sent {
+				writeError(w, err)
+				return
+			}
+			// Headers were already written so the
+			// error is sent as the final message.
+			stream.send(fmt.Sprintf(`{"error":%q}`, grpc.ErrorDesc(err)))
+		}
+	}
+}
+
+// httpStream implements grpc.ServerStream
+// on top of an HTTP request.
+type httpStream struct {
+	w        http.ResponseWriter
+	r        *http.Request
+	ps       httprouter.Params
+	desc     grpc.StreamDesc
+	sse      bool
+	sent     bool
+	received bool
+	body     *json.Decoder
+}
+
+func (s *httpStream) Context() context.Context     { return s.r.Context() }
+func (s *httpStream) SetHeader(metadata.MD) error  { return nil }
+func (s *httpStream) SendHeader(metadata.MD) error { return nil }
+func (s *httpStream) SetTrailer(metadata.MD)       {}
+
+func (s *httpStream) SendMsg(m interface{}) error {
+	raw, err := marshaler.MarshalToString(m.(proto.Message))
+	if err != nil {
+		return err
+	}
+	return s.send(raw)
+}
+
+func (s *httpStream) RecvMsg(m interface{}) error {
+	if !s.desc.ClientStreams {
+		// Server streams receive
+		// exactly one request.
+		if s.received {
+			return io.EOF
+		}
+		s.received = true
+		return decodeRequest(s.r, s.ps, m)
+	}
+	if s.body == nil {
+		s.body = json.NewDecoder(s.r.Body)
+	}
+	var raw json.RawMessage
+	if err := s.body.Decode(&raw); err != nil {
+		return err
+	}
+	return unmarshaler.Unmarshal(bytes.NewReader(raw), m.(proto.Message))
+}
+
+func (s *httpStream) send(raw string) error {
+	if !s.sent {
+		switch {
+		case !s.desc.ServerStreams:
+			s.w.Header().Set("Content-Type", "application/json")
+		case s.sse:
+			s.w.Header().Set("Content-Type", "text/event-stream")
+		default:
+			s.w.Header().Set("Content-Type", "application/x-ndjson")
+		}
+		s.sent = true
+	}
+	var err error
+	if s.sse && s.desc.ServerStreams {
+		_, err = fmt.Fprintf(s.w, "data: %s\n\n", raw)
+	} else {
+		_, err = fmt.Fprintln(s.w, raw)
+	}
+	if err != nil {
+		return err
+	}
+	if flusher, ok := s.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return nil
+}
+
+// decodeRequest decodes an RPC request message from
+// the JSON body, query string and path parameters.
+func decodeRequest(r *http.Request, ps httprouter.Params, msg interface{}) error {
+	fields := map[string]json.RawMessage{}
+	if r.Method == http.MethodPost && r.Body != nil {
+		err := json.NewDecoder(r.Body).Decode(&fields)
+		if err != nil && err != io.EOF {
+			return grpc.Errorf(codes.InvalidArgument, "bad request body: %s", err.Error())
+		}
+	}
+	values := r.URL.Query()
+	if id := ps.ByName("id"); id != "" {
+		values.Set("id", id)
+	}
+	for key := range values {
+		value, err := queryValue(msg, key, values[key])
+		if err != nil {
+			return err
+		}
+		fields[key] = value
+	}
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return grpc.Errorf(codes.InvalidArgument, "bad request: %s", err.Error())
+	}
+	err = unmarshaler.Unmarshal(bytes.NewReader(raw), msg.(proto.Message))
+	if err != nil {
+		return grpc.Errorf(codes.InvalidArgument, "bad request: %s", err.Error())
+	}
+	return nil
+}
+
+// queryValue encodes the query string values of key as
+// JSON according to the type of the matching message
+// field. Values of unknown fields are encoded as strings.
+func queryValue(msg interface{}, key string, values []string) (json.RawMessage, error) {
+	value := values[0]
+	t := reflect.TypeOf(msg).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if strings.Split(field.Tag.Get("json"), ",")[0] != key {
+			continue
+		}
+		if ft := field.Type; ft.Kind() == reflect.Slice && ft.Elem().Kind() == reflect.String {
+			// Repeated strings may be given
+			// as a parameter for each value.
+			return marshalValue(key, values)
+		}
+		if strings.Contains(field.Tag.Get("protobuf"), ",enum=") {
+			// Enums are numbers or their
+			// names which are upper case.
+			if _, err := strconv.ParseInt(value, 10, 32); err == nil {
+				return json.RawMessage(value), nil
+			}
+			return marshalValue(key, strings.ToUpper(value))
+		}
+		switch field.Type.Kind() {
+		case reflect.String:
+		case reflect.Bool:
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, badValue(key, value)
+			}
+			return marshalValue(key, b)
+		case reflect.Int32, reflect.Int64, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				return nil, badValue(key, value)
+			}
+			return json.RawMessage(value), nil
+		case reflect.Slice:
+			if field.Type.Elem().Kind() == reflect.Uint8 {
+				// Bytes are base64 strings.
+				break
+			}
+			fallthrough
+		default:
+			if !json.Valid([]byte(value)) {
+				return nil, badValue(key, value)
+			}
+			return json.RawMessage(value), nil
+		}
+		break
+	}
+	return marshalValue(key, value)
+}
+
+func marshalValue(key string, v interface{}) (json.RawMessage, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, "bad value for %s: %s", key, err.Error())
+	}
+	return raw, nil
+}
+
+func badValue(key, value string) error {
+	return grpc.Errorf(codes.InvalidArgument, "bad value for %s: %q", key, value)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch grpc.Code(err) {
+	case codes.InvalidArgument:
+		status = http.StatusBadRequest
+	case codes.NotFound:
+		status = http.StatusNotFound
+	case codes.PermissionDenied:
+		status = http.StatusForbidden
+	case codes.Unauthenticated:
+		w.Header().Set("WWW-Authenticate", `Basic realm="gaffer"`)
+		status = http.StatusUnauthorized
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": grpc.ErrorDesc(err)})
+}
@@ -0,0 +1,156 @@
+package plugin
+
+import (
+	"bufio"
+	"encoding/json"
+	"github.com/julienschmidt/httprouter"
+	"github.com/mesanine/gaffer/config"
+	"github.com/mesanine/gaffer/host"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type MockRPCPlugin struct {
+	MockPlugin
+}
+
+func (mp MockRPCPlugin) Name() string { return "mock" }
+
+func (mp MockRPCPlugin) RPC() *grpc.ServiceDesc {
+	return &grpc.ServiceDesc{
+		ServiceName: "mock.RPC",
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{
+			{
+				MethodName: "Echo",
+				Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+					in := &host.Host{}
+					if err := dec(in); err != nil {
+						return nil, err
+					}
+					return in, nil
+				},
+			},
+		},
+		Streams: []grpc.StreamDesc{
+			{
+				StreamName: "Repeat",
+				Handler: func(srv interface{}, stream grpc.ServerStream) error {
+					in := &host.Host{}
+					if err := stream.RecvMsg(in); err != nil {
+						return err
+					}
+					for i := int32(0); i < in.Port; i++ {
+						if err := stream.SendMsg(in); err != nil {
+							return err
+						}
+					}
+					return nil
+				},
+				ServerStreams: true,
+			},
+		},
+	}
+}
+
+func newTestGateway(t *testing.T, cfg config.Config) *httptest.Server {
+	auth, err := NewAuth(cfg)
+	assert.NoError(t, err)
+	gateway := &Gateway{auth: auth}
+	mock := &MockRPCPlugin{}
+	router := httprouter.New()
+	desc := mock.RPC()
+	gateway.route(router, mock.Name(), "Echo", gateway.unary(mock, desc.Methods[0]))
+	gateway.route(router, mock.Name(), "Repeat", gateway.stream(mock, desc.Streams[0]))
+	return httptest.NewServer(router)
+}
+
+func TestGatewayUnary(t *testing.T) {
+	server := newTestGateway(t, config.Config{})
+	defer server.Close()
+	resp, err := http.Post(server.URL+"/v1/mock/echo/foo?port=10", "application/json", strings.NewReader(`{"address": "127.0.0.1"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	h := &host.Host{}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(h))
+	assert.Equal(t, "127.0.0.1", h.Address)
+	assert.Equal(t, int32(10), h.Port)
+	resp, err = http.Get(server.URL + "/v1/mock/echo?port=bad")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestGatewayStream(t *testing.T) {
+	server := newTestGateway(t, config.Config{})
+	defer server.Close()
+	resp, err := http.Get(server.URL + "/v1/mock/repeat?name=foo&port=3")
+	assert.NoError(t, err)
+	assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+	scanner := bufio.NewScanner(resp.Body)
+	var count int
+	for scanner.Scan() {
+		h := &host.Host{}
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), h))
+		assert.Equal(t, "foo", h.Name)
+		count++
+	}
+	assert.Equal(t, 3, count)
+}
+
+func TestGatewayAuth(t *testing.T) {
+	server := newTestGateway(t, config.Config{User: "foo:bar"})
+	defer server.Close()
+	resp, err := http.Get(server.URL + "/v1/mock/echo")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/v1/mock/echo", nil)
+	req.SetBasicAuth("foo", "bar")
+	resp, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestGatewayQuery(t *testing.T) {
+	server := newTestGateway(t, config.Config{})
+	defer server.Close()
+	for query, status := range map[string]int{
+		"leader=true":              http.StatusOK,
+		"leader=yes":               http.StatusBadRequest,
+		"port=1.5e1":               http.StatusBadRequest,
+		`labels={"zone":"a"}`:      http.StatusOK,
+		"labels=zone":              http.StatusBadRequest,
+		"name=true&unknown=plain":  http.StatusOK,
+		"boot_time=1500000000&x=1": http.StatusOK,
+	} {
+		resp, err := http.Get(server.URL + "/v1/mock/echo?" + strings.Replace(query, `"`, "%22", -1))
+		assert.NoError(t, err)
+		assert.Equal(t, status, resp.StatusCode, query)
+	}
+	resp, err := http.Get(server.URL + "/v1/mock/echo?leader=true&name=true")
+	assert.NoError(t, err)
+	h := &host.Host{}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(h))
+	assert.True(t, h.Leader)
+	assert.Equal(t, "true", h.Name)
+}
+
+func TestGatewayRegistry(t *testing.T) {
+	auth, err := NewAuth(config.Config{})
+	assert.NoError(t, err)
+	reg := NewRegistry()
+	assert.NoError(t, reg.Register(&MockPlugin{}))
+	gateway := &Gateway{auth: auth}
+	server := httptest.NewServer(gateway.routes(reg))
+	defer server.Close()
+	resp, err := http.Get(server.URL + "/v1/plugins/list")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	list := &ListResponse{}
+	assert.NoError(t, unmarshaler.Unmarshal(resp.Body, list))
+	assert.Len(t, list.Plugins, 1)
+}
@@ -0,0 +1,144 @@
+package kmsg
+
+import (
+	"bytes"
+	"fmt"
+	"go.uber.org/zap/zapcore"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Entry is a record read from /dev/kmsg.
+type Entry struct {
+	// Priority is the syslog priority
+	// combining facility and level.
+	Priority int
+	// Sequence number of the record
+	// which is unique until reboot.
+	Sequence uint64
+	// Monotonic time since boot
+	// the record was written.
+	Monotonic time.Duration
+	Message   string
+}
+
+// Level returns the syslog level.
+func (e Entry) Level() int { return e.Priority & 7 }
+
+// Facility returns the syslog facility.
+func (e Entry) Facility() int { return e.Priority >> 3 }
+
+// ZapLevel maps the syslog level
+// to a level of the Gaffer log.
+func (e Entry) ZapLevel() zapcore.Level {
+	switch level := e.Level(); {
+	case level <= 3:
+		return zapcore.ErrorLevel
+	case level == 4:
+		return zapcore.WarnLevel
+	case level <= 6:
+		return zapcore.InfoLevel
+	}
+	return zapcore.DebugLevel
+}
+
+// Parse parses a record formatted
+// as described in dev-kmsg(5):
+//
+// PRIORITY,SEQUENCE,TIMESTAMP,FLAGS[,...];MESSAGE
+//
+// Continuation lines of dictionary
+// properties are discarded.
+func Parse(raw []byte) (*Entry, error) {
+	raw = bytes.SplitN(raw, []byte{'\n'}, 2)[0]
+	parts := bytes.SplitN(raw, []byte{';'}, 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("bad kernel message: %q", raw)
+	}
+	fields := strings.Split(string(parts[0]), ",")
+	if len(fields) < 4 {
+		return nil, fmt.Errorf("bad kernel message prefix: %q", parts[0])
+	}
+	priority, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return nil, err
+	}
+	seq, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	usec, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &Entry{
+		Priority:  priority,
+		Sequence:  seq,
+		Monotonic: time.Duration(usec) * time.Microsecond,
+		Message:   string(parts[1]),
+	}, nil
+}
+
+var (
+	// Out of memory: Killed process 1234 (stress) total-vm:...
+	// Memory cgroup out of memory: Kill process 1234 (stress) score ...
+	oomKilled = regexp.MustCompile(`[Kk]ill(?:ed)? process (\d+) \(([^)]*)\)`)
+	// oom-kill:constraint=CONSTRAINT_MEMCG,...,task_memcg=/foo,task=stress,pid=1234,uid=0
+	oomKill = regexp.MustCompile(`^oom-kill:.*task_memcg=([^,]*),.*pid=(\d+)`)
+	// Task in /foo killed as a result of limit of /foo
+	oomTask = regexp.MustCompile(`^Task in (\S+) killed as a result of limit`)
+	// stress[1234]: segfault at 0 ip ... sp ... error 4 in ...
+	segfault = regexp.MustCompile(`^(.+)\[(\d+)\]: segfault at`)
+)
+
+// Match is a notable kernel message
+// naming the process it concerns.
+type Match struct {
+	Type string
+	PID  int
+	// Command is the name
+	// of the process.
+	Command string
+	// Cgroup of the process if it
+	// is named by the message.
+	Cgroup string
+}
+
+// matcher finds notable messages. The kernel
+// describes a single OOM kill over several
+// messages so the cgroup of the killed
+// process is carried between them.
+type matcher struct {
+	cgroup string
+	pid    int
+}
+
+// match returns the notable
+// message described by msg.
+func (m *matcher) match(msg string) *Match {
+	if found := oomKill.FindStringSubmatch(msg); found != nil {
+		m.cgroup = found[1]
+		m.pid, _ = strconv.Atoi(found[2])
+		return nil
+	}
+	if found := oomTask.FindStringSubmatch(msg); found != nil {
+		m.cgroup, m.pid = found[1], 0
+		return nil
+	}
+	if found := oomKilled.FindStringSubmatch(msg); found != nil {
+		pid, _ := strconv.Atoi(found[1])
+		match := &Match{Type: "oom", PID: pid, Command: found[2]}
+		if m.cgroup != "" && (m.pid == 0 || m.pid == pid) {
+			match.Cgroup = m.cgroup
+		}
+		m.cgroup, m.pid = "", 0
+		return match
+	}
+	if found := segfault.FindStringSubmatch(msg); found != nil {
+		pid, _ := strconv.Atoi(found[2])
+		return &Match{Type: "segfault", PID: pid, Command: found[1]}
+	}
+	return nil
+}
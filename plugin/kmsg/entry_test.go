@@ -0,0 +1,43 @@
+package kmsg
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	entry, err := Parse([]byte("30,1234,5678901,-;systemd[1]: started\n SUBSYSTEM=foo\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1234), entry.Sequence)
+	assert.Equal(t, 5678901*time.Microsecond, entry.Monotonic)
+	assert.Equal(t, 3, entry.Facility())
+	assert.Equal(t, 6, entry.Level())
+	assert.Equal(t, zapcore.InfoLevel, entry.ZapLevel())
+	assert.Equal(t, "systemd[1]: started", entry.Message)
+	entry, err = Parse([]byte("3,1,2,c,extra;a;b"))
+	assert.NoError(t, err)
+	assert.Equal(t, zapcore.ErrorLevel, entry.ZapLevel())
+	assert.Equal(t, "a;b", entry.Message)
+	_, err = Parse([]byte("garbage"))
+	assert.Error(t, err)
+	_, err = Parse([]byte("x,1,2,-;msg"))
+	assert.Error(t, err)
+}
+
+func TestMatch(t *testing.T) {
+	m := &matcher{}
+	assert.Nil(t, m.match("oom-kill:constraint=CONSTRAINT_MEMCG,nodemask=(null),cpuset=web,mems_allowed=0,oom_memcg=/gaffer/web,task_memcg=/gaffer/web,task=stress,pid=1234,uid=0"))
+	match := m.match("Memory cgroup out of memory: Killed process 1234 (stress) total-vm:1000kB")
+	assert.Equal(t, &Match{Type: "oom", PID: 1234, Command: "stress", Cgroup: "/gaffer/web"}, match)
+	// Older kernels
+	assert.Nil(t, m.match("Task in /db killed as a result of limit of /db"))
+	match = m.match("Memory cgroup out of memory: Kill process 99 (postgres) score 1000 or sacrifice child")
+	assert.Equal(t, &Match{Type: "oom", PID: 99, Command: "postgres", Cgroup: "/db"}, match)
+	match = m.match("Out of memory: Killed process 5 (java) total-vm:1kB")
+	assert.Equal(t, "", match.Cgroup)
+	match = m.match("nginx: worker[42]: segfault at 0 ip 00007f sp 00007ffd error 4 in libc.so[7f+1000]")
+	assert.Equal(t, &Match{Type: "segfault", PID: 42, Command: "nginx: worker"}, match)
+	assert.Nil(t, m.match("eth0: link up"))
+}
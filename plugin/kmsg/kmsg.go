@@ -0,0 +1,162 @@
+package kmsg
+
+import (
+	"fmt"
+	"github.com/mesanine/gaffer/config"
+	"github.com/mesanine/gaffer/event"
+	"github.com/mesanine/gaffer/log"
+	"github.com/mesanine/gaffer/service"
+	"github.com/mesanine/gaffer/store"
+	"go.uber.org/zap"
+	"os"
+	"syscall"
+)
+
+const (
+	// Device is the kernel log buffer.
+	Device = "/dev/kmsg"
+	// maxRecord is the largest record
+	// returned by a single read.
+	maxRecord = 8192
+)
+
+// Kmsg writes kernel messages into the Gaffer log
+// with the kernel logger name and pushes events
+// for OOM kills and segfaults of services.
+type Kmsg struct {
+	// seq is the sequence number of the last
+	// record logged so messages are not
+	// logged again if the plugin restarts.
+	seq  uint64
+	seen bool
+	// services holds the ID of each service
+	// which may own a killed process.
+	services map[string]bool
+	err      chan error
+	stop     chan bool
+}
+
+func New() *Kmsg {
+	return &Kmsg{
+		services: map[string]bool{},
+		err:      make(chan error, 1),
+		stop:     make(chan bool, 1),
+	}
+}
+
+func (k *Kmsg) Name() string { return "kmsg" }
+
+// Configure loads the ID of each service. Services which
+// have exited are still included as their processes are
+// often killed shortly before their exit is reported.
+func (k *Kmsg) Configure(cfg config.Config) error {
+	services, err := store.New(cfg, "services").Services()
+	if err != nil {
+		return err
+	}
+	for _, svc := range services {
+		k.services[svc.Id] = true
+	}
+	return nil
+}
+
+func (k *Kmsg) Run(eb *event.EventBus) error {
+	fd, err := os.Open(Device)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+	entries := make(chan *Entry)
+	done := make(chan struct{})
+	defer close(done)
+	go k.read(fd, entries, done)
+	var (
+		m      = &matcher{}
+		kernel = log.Named("kernel")
+	)
+	for {
+		select {
+		case err := <-k.err:
+			return err
+		case <-k.stop:
+			return nil
+		case entry := <-entries:
+			if k.seen && entry.Sequence <= k.seq {
+				continue
+			}
+			k.seq, k.seen = entry.Sequence, true
+			if ce := kernel.Check(entry.ZapLevel(), entry.Message); ce != nil {
+				ce.Write(
+					zap.Uint64("seq", entry.Sequence),
+					zap.Duration("monotonic", entry.Monotonic),
+					zap.Int("facility", entry.Facility()),
+				)
+			}
+			if match := m.match(entry.Message); match != nil {
+				k.notify(eb, match, entry)
+			}
+		}
+	}
+}
+
+func (k *Kmsg) Stop() error {
+	k.stop <- true
+	return nil
+}
+
+// notify pushes an event for a notable message
+// with the ID of the service which owns the
+// process if it can be found. OOM kills report
+// the cgroup of the process but segfaults do not
+// and the process has usually exited by the time
+// its cgroups are read from /proc, in which case
+// the event has no service ID.
+func (k *Kmsg) notify(eb *event.EventBus, match *Match, entry *Entry) {
+	paths := service.Cgroups(service.Proc, match.PID)
+	if match.Cgroup != "" {
+		paths = append([]string{match.Cgroup}, paths...)
+	}
+	id := service.Owner(paths, k.services)
+	et := event.KERNEL_SEGFAULT
+	if match.Type == "oom" {
+		et = event.KERNEL_OOM
+	}
+	if id != "" {
+		log.Named("kmsg").Warn(fmt.Sprintf("service %s process %d (%s) was killed: %s", id, match.PID, match.Command, match.Type))
+	}
+	eb.Push(event.New(et, event.WithID(id), event.WithMessage(entry.Message)))
+}
+
+// read sends each record of the kernel log
+// buffer to entries until done is closed.
+func (k *Kmsg) read(fd *os.File, entries chan *Entry, done chan struct{}) {
+	buf := make([]byte, maxRecord)
+	for {
+		n, err := fd.Read(buf)
+		if err != nil {
+			// Records were overwritten
+			// before they could be read.
+			if pe, ok := err.(*os.PathError); ok && pe.Err == syscall.EPIPE {
+				log.Named("kmsg").Warn("kernel messages were overwritten before they were read")
+				continue
+			}
+			select {
+			case <-done:
+				// Closed by Run
+			case k.err <- err:
+			default:
+			}
+			return
+		}
+		entry, err := Parse(buf[:n])
+		if err != nil {
+			log.Named("kmsg").Warn(err.Error())
+			continue
+		}
+		select {
+		case entries <- entry:
+		case <-done:
+			return
+		}
+	}
+}
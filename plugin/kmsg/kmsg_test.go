@@ -0,0 +1,44 @@
+package kmsg
+
+import (
+	"github.com/mesanine/gaffer/config"
+	"github.com/mesanine/gaffer/event"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNotify(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gaffer-kmsg")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	for _, id := range []string{"web", "db"} {
+		bundle := filepath.Join(dir, "services", id)
+		assert.NoError(t, os.MkdirAll(bundle, 0755))
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(bundle, "config.json"), []byte("{}"), 0644))
+	}
+	cfg := config.New()
+	cfg.Store.BasePath = dir
+	k := New()
+	assert.NoError(t, k.Configure(*cfg))
+	assert.Equal(t, map[string]bool{"web": true, "db": true}, k.services)
+	eb := event.NewEventBus()
+	eb.Start()
+	defer eb.Stop()
+	sub := event.NewSubscriber()
+	eb.Subscribe(sub)
+	entry := &Entry{Message: "Killed process 1234 (nginx)"}
+	// OOM kills report the cgroup of the process
+	k.notify(eb, &Match{Type: "oom", PID: 1234, Command: "nginx", Cgroup: "/gaffer/web"}, entry)
+	evt := sub.Next()
+	assert.Equal(t, string(event.KERNEL_OOM), evt.Type)
+	assert.Equal(t, "web", evt.Id)
+	assert.Equal(t, entry.Message, evt.Message)
+	// The segfaulting process has exited
+	k.notify(eb, &Match{Type: "segfault", PID: -1, Command: "postgres"}, entry)
+	evt = sub.Next()
+	assert.Equal(t, string(event.KERNEL_SEGFAULT), evt.Type)
+	assert.Equal(t, "", evt.Id)
+}
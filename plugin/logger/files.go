@@ -0,0 +1,437 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	// PollInterval is how often a followed
+	// log file is checked for new lines.
+	PollInterval = 50 * time.Millisecond
+	// ChunkSize is the size of each
+	// block read when scanning backwards.
+	ChunkSize = 4096
+	// backupTimeFormat is the timestamp lumberjack
+	// inserts into the name of each backup.
+	backupTimeFormat = "2006-01-02T15-04-05.000"
+	compressSuffix   = ".gz"
+)
+
+// line is a log line, the identity of the file
+// it was read from and the offset following it.
+type line struct {
+	content []byte
+	file    string
+	offset  int64
+}
+
+// fileID identifies a file by its device and
+// inode which are unchanged when it is rotated.
+func fileID(info os.FileInfo) string {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return fmt.Sprintf("%d:%d", stat.Dev, stat.Ino)
+	}
+	return info.Name()
+}
+
+// statID returns the identity
+// of the file at path.
+func statID(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	return fileID(info), nil
+}
+
+// openID opens the file at path
+// returning it with its identity.
+func openID(path string) (*os.File, string, error) {
+	fd, err := os.Open(path)
+	if err != nil {
+		return nil, "", err
+	}
+	info, err := fd.Stat()
+	if err != nil {
+		fd.Close()
+		return nil, "", err
+	}
+	return fd, fileID(info), nil
+}
+
+// follower reads lines from a log file
+// reopening it if it is rotated or truncated.
+type follower struct {
+	path    string
+	id      string
+	fd      *os.File
+	reader  *bufio.Reader
+	offset  int64
+	partial []byte
+	// draining is set once the file was rotated
+	// until the old file is read to its end.
+	draining bool
+}
+
+// newFollower opens the log file at path seeking to
+// offset or to the end of the file if offset is negative.
+func newFollower(path string, offset int64) (*follower, error) {
+	fd, id, err := openID(path)
+	if err != nil {
+		return nil, err
+	}
+	whence := io.SeekStart
+	if offset < 0 {
+		offset = 0
+		whence = io.SeekEnd
+	}
+	pos, err := fd.Seek(offset, whence)
+	if err != nil {
+		fd.Close()
+		return nil, err
+	}
+	return &follower{
+		path:   path,
+		id:     id,
+		fd:     fd,
+		reader: bufio.NewReader(fd),
+		offset: pos,
+	}, nil
+}
+
+// next returns the next line. At the end of the
+// file it returns io.EOF unless follow is set in
+// which case it waits for another line to be written
+// reopening the file if it was rotated or truncated.
+func (f *follower) next(ctx context.Context, follow bool) (*line, error) {
+	for {
+		raw, err := f.reader.ReadBytes('\n')
+		f.offset += int64(len(raw))
+		f.partial = append(f.partial, raw...)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		// A line without a trailing newline is only complete
+		// once the file is no longer followed or written to.
+		if err == nil || ((!follow || f.draining) && len(f.partial) > 0) {
+			content := f.partial
+			f.partial = nil
+			return &line{content: content, file: f.id, offset: f.offset}, nil
+		}
+		if !follow {
+			return nil, io.EOF
+		}
+		if f.draining {
+			if err := f.reopen(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		rotated, truncated, err := f.changed()
+		if err != nil {
+			return nil, err
+		}
+		if rotated {
+			// Lines written before the rotation
+			// are read from the old file first.
+			f.draining = true
+			continue
+		}
+		if truncated {
+			if err := f.reopen(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(PollInterval):
+		}
+	}
+}
+
+// changed reports if the file at path was replaced
+// by a rotation or truncated since it was opened.
+func (f *follower) changed() (rotated, truncated bool, err error) {
+	current, err := os.Stat(f.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// Rotation is in progress
+			return false, false, nil
+		}
+		return false, false, err
+	}
+	opened, err := f.fd.Stat()
+	if err != nil {
+		return false, false, err
+	}
+	if !os.SameFile(opened, current) {
+		return true, false, nil
+	}
+	return false, current.Size() < f.offset, nil
+}
+
+// reopen opens the file at path from the
+// beginning discarding any partial line.
+func (f *follower) reopen() error {
+	fd, id, err := openID(f.path)
+	if err != nil {
+		return err
+	}
+	f.fd.Close()
+	f.fd = fd
+	f.id = id
+	f.reader = bufio.NewReader(fd)
+	f.offset = 0
+	f.partial = nil
+	f.draining = false
+	return nil
+}
+
+// seek moves to offset or to the beginning
+// of the file if it is shorter than offset.
+func (f *follower) seek(offset int64) error {
+	info, err := f.fd.Stat()
+	if err != nil {
+		return err
+	}
+	if offset > info.Size() {
+		offset = 0
+	}
+	if _, err := f.fd.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	f.reader.Reset(f.fd)
+	f.offset = offset
+	f.partial = nil
+	return nil
+}
+
+func (f *follower) Close() error { return f.fd.Close() }
+
+// tail returns the last n lines written before the
+// follower's offset reading backwards through the
+// rotated backups until n lines are found.
+func (f *follower) tail(n int) ([]*line, error) {
+	lines, err := lastLines(f.fd, f.id, f.offset, n)
+	if err != nil {
+		return nil, err
+	}
+	paths, err := backups(f.path)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		if len(lines) >= n {
+			break
+		}
+		// The followed file was rotated
+		// after it was opened.
+		if id, _ := statID(path); id == f.id {
+			continue
+		}
+		found, err := lastBackupLines(path, n-len(lines))
+		if err != nil {
+			if os.IsNotExist(err) {
+				// Removed or compressed
+				// since it was listed.
+				continue
+			}
+			return nil, err
+		}
+		lines = append(found, lines...)
+	}
+	return lines, nil
+}
+
+// lastLines returns the last n lines before
+// size by reading backwards in chunks.
+func lastLines(r io.ReaderAt, id string, size int64, n int) ([]*line, error) {
+	if n <= 0 {
+		return []*line{}, nil
+	}
+	var (
+		buf []byte
+		pos = size
+	)
+	// Another newline is needed as the
+	// first line read may be partial.
+	for pos > 0 && bytes.Count(buf, []byte{'\n'}) <= n {
+		chunk := int64(ChunkSize)
+		if chunk > pos {
+			chunk = pos
+		}
+		pos -= chunk
+		raw := make([]byte, chunk)
+		if _, err := r.ReadAt(raw, pos); err != nil && err != io.EOF {
+			return nil, err
+		}
+		buf = append(raw, buf...)
+	}
+	lines := []*line{}
+	offset := pos
+	for _, content := range bytes.SplitAfter(buf, []byte{'\n'}) {
+		if len(content) == 0 {
+			continue
+		}
+		offset += int64(len(content))
+		lines = append(lines, &line{content: content, file: id, offset: offset})
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines, nil
+}
+
+// lastBackupLines returns the last n lines of a
+// backup which is read forwards if compressed.
+func lastBackupLines(path string, n int) ([]*line, error) {
+	if !strings.HasSuffix(path, compressSuffix) {
+		fd, id, err := openID(path)
+		if err != nil {
+			return nil, err
+		}
+		defer fd.Close()
+		info, err := fd.Stat()
+		if err != nil {
+			return nil, err
+		}
+		return lastLines(fd, id, info.Size(), n)
+	}
+	lines := []*line{}
+	err := readFile(path, 0, func(l *line) error {
+		lines = append(lines, l)
+		if len(lines) > n {
+			lines = lines[1:]
+		}
+		return nil
+	})
+	return lines, err
+}
+
+// readFile calls fn with each line of a log file or
+// compressed backup after offset until fn fails.
+func readFile(path string, offset int64, fn func(*line) error) error {
+	fd, id, err := openID(path)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+	var r io.Reader = fd
+	if strings.HasSuffix(path, compressSuffix) {
+		zr, err := gzip.NewReader(fd)
+		if err != nil {
+			return err
+		}
+		defer zr.Close()
+		// Offsets are within the
+		// uncompressed content.
+		if _, err := io.CopyN(ioutil.Discard, zr, offset); err != nil && err != io.EOF {
+			return err
+		}
+		r = zr
+	} else if _, err := fd.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	reader := bufio.NewReader(r)
+	for {
+		raw, err := reader.ReadBytes('\n')
+		if len(raw) > 0 {
+			offset += int64(len(raw))
+			if err := fn(&line{content: raw, file: id, offset: offset}); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// backups returns the rotated and possibly compressed
+// backups of the log file at path from newest to oldest.
+func backups(path string) ([]string, error) {
+	dir := filepath.Dir(path)
+	ext := filepath.Ext(path)
+	prefix := strings.TrimSuffix(filepath.Base(path), ext) + "-"
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	found := map[time.Time]string{}
+	times := []time.Time{}
+	for _, info := range infos {
+		name := strings.TrimSuffix(info.Name(), compressSuffix)
+		if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		t, err := time.Parse(backupTimeFormat, name[len(prefix):len(name)-len(ext)])
+		if err != nil {
+			continue
+		}
+		// A backup being compressed exists twice,
+		// prefer the complete uncompressed file.
+		if other, ok := found[t]; ok {
+			if strings.HasSuffix(other, compressSuffix) {
+				found[t] = filepath.Join(dir, info.Name())
+			}
+			continue
+		}
+		found[t] = filepath.Join(dir, info.Name())
+		times = append(times, t)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].After(times[j]) })
+	paths := []string{}
+	for _, t := range times {
+		paths = append(paths, found[t])
+	}
+	return paths, nil
+}
+
+// rotatedSince returns the backups of the log file
+// at path from oldest to newest skipping any which
+// were rotated before t.
+func rotatedSince(path string, t time.Time) ([]string, error) {
+	paths, err := backups(path)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{}
+	for _, backup := range paths {
+		if !t.IsZero() {
+			if rotated, ok := rotationTime(backup); ok && rotated.Before(t) {
+				break
+			}
+		}
+		files = append([]string{backup}, files...)
+	}
+	return files, nil
+}
+
+// rotationTime returns the time a backup was rotated
+// which it is named after in UTC by lumberjack.
+func rotationTime(backup string) (time.Time, bool) {
+	name := strings.TrimSuffix(filepath.Base(backup), compressSuffix)
+	stamp := strings.TrimSuffix(name, filepath.Ext(name))
+	if len(stamp) < len(backupTimeFormat) {
+		return time.Time{}, false
+	}
+	rotated, err := time.Parse(backupTimeFormat, stamp[len(stamp)-len(backupTimeFormat):])
+	return rotated, err == nil
+}
@@ -0,0 +1,92 @@
+package logger
+
+import (
+	"compress/gzip"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func contents(lines []*line) string {
+	var out string
+	for _, l := range lines {
+		out += string(l.content)
+	}
+	return out
+}
+
+func TestTail(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gaffer-logger")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "gaffer.log")
+	// Oldest backup is compressed
+	fd, err := os.Create(filepath.Join(dir, "gaffer-2017-01-01T00-00-00.000.log.gz"))
+	assert.NoError(t, err)
+	zw := gzip.NewWriter(fd)
+	zw.Write([]byte("1\n2\n3\n"))
+	assert.NoError(t, zw.Close())
+	assert.NoError(t, fd.Close())
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "gaffer-2017-01-02T00-00-00.000.log"), []byte("4\n5\n"), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "other.log"), []byte("x\n"), 0644))
+	// Lines longer than a chunk
+	long := strings.Repeat("a", ChunkSize*2) + "\n"
+	assert.NoError(t, ioutil.WriteFile(path, []byte("6\n"+long+"7\n"), 0644))
+	f, err := newFollower(path, -1)
+	assert.NoError(t, err)
+	defer f.Close()
+	lines, err := f.tail(2)
+	assert.NoError(t, err)
+	assert.Equal(t, long+"7\n", contents(lines))
+	lines, err = f.tail(6)
+	assert.NoError(t, err)
+	assert.Equal(t, "3\n4\n5\n6\n"+long+"7\n", contents(lines))
+	lines, err = f.tail(100)
+	assert.NoError(t, err)
+	assert.Equal(t, "1\n2\n3\n4\n5\n6\n"+long+"7\n", contents(lines))
+}
+
+func TestFollowRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gaffer-logger")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "gaffer.log")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("1\n"), 0644))
+	f, err := newFollower(path, 0)
+	assert.NoError(t, err)
+	defer f.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	l, err := f.next(ctx, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "1\n", string(l.content))
+	// Rotate the file like lumberjack after
+	// a line without a trailing newline
+	fd, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	assert.NoError(t, err)
+	fd.Write([]byte("2"))
+	fd.Close()
+	assert.NoError(t, os.Rename(path, filepath.Join(dir, "gaffer-2017-01-01T00-00-00.000.log")))
+	assert.NoError(t, ioutil.WriteFile(path, []byte("222\n"), 0644))
+	// The old file is read to its end first
+	l, err = f.next(ctx, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "2", string(l.content))
+	l, err = f.next(ctx, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "222\n", string(l.content))
+	assert.Equal(t, int64(4), l.offset)
+	// Truncate and rewrite the file
+	assert.NoError(t, ioutil.WriteFile(path, []byte("3\n"), 0644))
+	l, err = f.next(ctx, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "3\n", string(l.content))
+	_, err = f.next(ctx, false)
+	assert.Equal(t, io.EOF, err)
+}
@@ -2,17 +2,25 @@ package logger
 
 import (
 	"bufio"
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/jawher/mow.cli"
 	"github.com/mesanine/gaffer/config"
 	"github.com/mesanine/gaffer/event"
+	"github.com/mesanine/gaffer/host"
 	"github.com/mesanine/gaffer/log"
+	"github.com/mesanine/gaffer/store"
 	"github.com/mesanine/gaffer/util"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"io"
 	"os"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -21,14 +29,19 @@ import (
 // configured log device.
 type Logger struct {
 	path string
-	err  chan error
-	stop chan bool
+	json bool
+	// services holds the ID of each service
+	// entries may be attributed to.
+	services map[string]bool
+	err      chan error
+	stop     chan bool
 }
 
 func New() *Logger {
 	return &Logger{
-		err:  make(chan error, 1),
-		stop: make(chan bool, 1),
+		services: map[string]bool{},
+		err:      make(chan error, 1),
+		stop:     make(chan bool, 1),
 	}
 }
 
@@ -36,6 +49,14 @@ func (l Logger) Name() string { return "logger" }
 
 func (l *Logger) Configure(cfg config.Config) error {
 	l.path = fmt.Sprintf("%s/%s", cfg.Logger.LogDir, "gaffer.log")
+	l.json = cfg.Logger.JSON
+	services, err := store.New(cfg, "services").Services()
+	if err != nil {
+		return err
+	}
+	for _, svc := range services {
+		l.services[svc.Id] = true
+	}
 	return nil
 }
 
@@ -57,75 +78,261 @@ func (l Logger) RPC() *grpc.ServiceDesc { return &_RPC_serviceDesc }
 
 // Write triggers a logging event on this server causing
 // it to be recorded in the log file if configured or
-// just to be displayed to stdout.
+// just to be displayed to stdout. Entries are attributed
+// to the calling peer and authenticated user and those
+// written by services are logged under their ID.
 func (l Logger) Write(stream RPC_WriteServer) error {
+	var (
+		fields = origin(stream.Context())
+		id     = l.caller(stream.Context())
+	)
 	for {
 		data, err := stream.Recv()
 		if err == io.EOF {
-			return nil
+			return stream.SendAndClose(&WriteResponse{})
 		}
 		if err != nil {
 			return err
 		}
-		log.Log.Info(string(data.Content), zap.Int64("offset", data.Offset))
+		if err := write(log.Log, data, id, fields); err != nil {
+			return grpc.Errorf(codes.InvalidArgument, "%s", err.Error())
+		}
 	}
 }
 
 // Read reads log data line by line from the Gaffer
-// configured log directory if it exists.
+// configured log directory if it exists. Following
+// continues across rotations of the log file and
+// each line is sent with a cursor to resume after.
 func (l Logger) Read(req *ReadRequest, stream RPC_ReadServer) error {
-	fd, err := os.Open(l.path)
-	if err != nil {
-		return err
+	var sent, limit int64
+	// Lines are the size of the
+	// tail which is unlimited.
+	if !req.Tail {
+		limit = req.Lines
+	}
+	// last is the time of the last entry
+	// read which lines such as stack
+	// traces are attributed to.
+	var last time.Time
+	send := func(l *line) error {
+		if ts, ok := lineTime(l.content); ok {
+			last = ts
+		}
+		cursor := &Cursor{File: l.file, Offset: l.offset}
+		if !last.IsZero() {
+			cursor.Time = last.UnixNano()
+		}
+		err := stream.Send(&LogData{
+			Content: l.content,
+			Offset:  l.offset,
+			Cursor:  cursor,
+		})
+		if err != nil {
+			return err
+		}
+		sent++
+		if limit > 0 && sent >= limit {
+			return errLimit
+		}
+		return nil
+	}
+	if req.Since != 0 {
+		send = since(time.Unix(0, req.Since), send)
+	}
+	f, err := open(l.path, req, send)
+	if err == errLimit {
+		return nil
 	}
-	defer fd.Close()
-	_, err = fd.Seek(req.Offset, 0)
 	if err != nil {
 		return err
 	}
-	var offset int
-	reader := bufio.NewReader(fd)
-	for i := 0; i < int(req.Lines) || int(req.Lines) == 0; i++ {
-		raw, err := reader.ReadBytes('\n')
-		// BUG: If the log file rotates
-		// while following the remote client
-		// won't pickup the new changes until
-		// they re-establish the RPC connection.
-		if err == io.EOF && req.Follow {
-			// avoid busy loop
-			time.Sleep(50 * time.Millisecond)
-			continue
-		}
+	defer f.Close()
+	if req.Tail && !req.Follow {
+		return nil
+	}
+	for {
+		line, err := f.next(stream.Context(), req.Follow)
 		if err == io.EOF {
 			return nil
 		}
 		if err != nil {
 			return err
 		}
-		offset += len(raw)
-		err = stream.Send(&LogData{
-			Content: raw,
-			Offset:  int64(offset),
-		})
+		if err := send(line); err != nil {
+			if err == errLimit {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// Query streams the structured entries of the log
+// file and its backups which match the request
+// from oldest to newest.
+func (l Logger) Query(req *QueryRequest, stream RPC_QueryServer) error {
+	if !l.json {
+		return fmt.Errorf("the log is not JSON encoded")
+	}
+	q, err := newQuery(req)
+	if err != nil {
+		return err
+	}
+	files, err := q.files(l.path)
+	if err != nil {
+		return err
+	}
+	var failed error
+	send := func(record *Record) bool {
+		failed = stream.Send(record)
+		return failed == nil
+	}
+	for _, path := range files {
+		more, err := q.scan(path, send)
 		if err != nil {
 			return err
 		}
+		if !more {
+			break
+		}
 	}
-	return nil
+	return failed
+}
+
+// GetLevel returns the global level and the
+// level of each named logger.
+func (l Logger) GetLevel(ctx context.Context, req *GetLevelRequest) (*LevelResponse, error) {
+	return levels(), nil
+}
+
+// SetLevel changes the level of a named logger
+// or the global level, temporarily if a
+// duration is given.
+func (l Logger) SetLevel(ctx context.Context, req *SetLevelRequest) (*LevelResponse, error) {
+	if req.Clear {
+		log.ResetLevel(req.Logger)
+		return levels(), nil
+	}
+	var level zapcore.Level
+	if err := level.UnmarshalText([]byte(strings.ToLower(req.Level))); err != nil {
+		return nil, err
+	}
+	log.SetLevel(req.Logger, level, time.Duration(req.Duration)*time.Second)
+	log.Named("logger").Info(
+		"log level changed",
+		zap.String("logger", req.Logger),
+		zap.String("level", level.String()),
+		zap.Int64("duration", req.Duration),
+	)
+	return levels(), nil
+}
+
+func levels() *LevelResponse {
+	resp := &LevelResponse{Levels: []*Level{}}
+	for _, level := range log.Levels() {
+		l := &Level{Logger: level.Name, Level: level.Level.String()}
+		if !level.Revert.IsZero() {
+			l.Revert = level.Revert.UnixNano()
+		}
+		resp.Levels = append(resp.Levels, l)
+	}
+	return resp
+}
+
+// Sinks returns the counters of
+// each remote log destination.
+func (l Logger) Sinks(ctx context.Context, req *SinksRequest) (*SinksResponse, error) {
+	resp := &SinksResponse{Sinks: []*Sink{}}
+	for _, stats := range log.Sinks() {
+		resp.Sinks = append(resp.Sinks, &Sink{
+			Format:    stats.Format,
+			Address:   stats.Address,
+			Sent:      stats.Sent,
+			Dropped:   stats.Dropped,
+			Connected: stats.Connected,
+		})
+	}
+	return resp, nil
+}
+
+// Table returns the levels as rows of a table,
+// the global level is shown with the logger *.
+func (r *LevelResponse) Table(wide bool) ([]string, [][]string) {
+	rows := [][]string{}
+	for _, level := range r.Levels {
+		name, revert := level.Logger, "-"
+		if name == "" {
+			name = "*"
+		}
+		if level.Revert != 0 {
+			revert = time.Unix(0, level.Revert).Format(time.RFC3339)
+		}
+		rows = append(rows, []string{name, level.Level, revert})
+	}
+	return []string{"LOGGER", "LEVEL", "REVERT"}, rows
+}
+
+// Table returns the sinks as rows of a table.
+func (r *SinksResponse) Table(wide bool) ([]string, [][]string) {
+	rows := [][]string{}
+	for _, sink := range r.Sinks {
+		rows = append(rows, []string{
+			sink.Format,
+			sink.Address,
+			fmt.Sprintf("%t", sink.Connected),
+			fmt.Sprintf("%d", sink.Sent),
+			fmt.Sprintf("%d", sink.Dropped),
+		})
+	}
+	return []string{"FORMAT", "ADDRESS", "CONNECTED", "SENT", "DROPPED"}, rows
+}
+
+// formatRecord formats a record as a single line
+// unless the output is YAML or a template. JSON
+// records are each a single object.
+func formatRecord(out config.Output, record *Record) ([]byte, error) {
+	switch out.Format {
+	case util.JSON:
+		return json.Marshal(record)
+	case util.Table, util.Wide:
+		keys := []string{}
+		for key := range record.Fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		line := fmt.Sprintf("%s %-5s ", time.Unix(0, record.Time).Format(timeFormat), strings.ToUpper(record.Level))
+		if record.Logger != "" {
+			line += fmt.Sprintf("[%s] ", record.Logger)
+		}
+		line += record.Message
+		for _, key := range keys {
+			line += fmt.Sprintf(" %s=%s", key, record.Fields[key])
+		}
+		if out.Format == util.Wide && record.Caller != "" {
+			line += fmt.Sprintf(" caller=%s", record.Caller)
+		}
+		return []byte(line), nil
+	}
+	buf := bytes.NewBuffer(nil)
+	// Each record is a YAML document
+	if out.Format == util.YAML {
+		buf.WriteString("---\n")
+	}
+	if err := util.Format(buf, out, record); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
 }
 
 func (l Logger) CLI(cfg *config.Config) cli.CmdInitializer {
 	return func(cmd *cli.Cmd) {
-		var client RPCClient
-		cmd.Before = func() {
-			conn, err := util.NewClientConn(*cfg)
-			util.Maybe(err)
-			client = NewRPCClient(conn)
-		}
 		cmd.Command("read", "Read from the server log", func(cmd *cli.Cmd) {
 			var (
 				follow = cmd.BoolOpt("f follow", false, "follow log output")
-				lines  = cmd.IntOpt("n lines", 0, "number of lines to read")
+				lines  = cmd.IntOpt("n lines", 0, "number of last lines to read")
+				head   = cmd.BoolOpt("head", false, "read the first lines rather than the last")
+				since  = cmd.StringOpt("since", "", "read from a duration before now or a timestamp")
 			)
 			cmd.Spec = "[OPTIONS]"
 			var req *ReadRequest
@@ -133,33 +340,191 @@ func (l Logger) CLI(cfg *config.Config) cli.CmdInitializer {
 				req = &ReadRequest{
 					Follow: *follow,
 					Lines:  int64(*lines),
+					Tail:   *lines > 0 && !*head,
+				}
+				if *since != "" {
+					t, err := ParseTime(*since)
+					util.Maybe(err)
+					req.Since = t.UnixNano()
+					req.Tail = false
 				}
 			}
 			cmd.Action = func() {
-				stream, err := client.Read(context.Background(), req, cfg.CallOpts()...)
-				util.Maybe(err)
-				for {
-					data, err := stream.Recv()
-					if err == io.EOF {
-						break
+				util.Remote(*cfg, func(h *host.Host, conn *grpc.ClientConn) (interface{}, error) {
+					stream, err := NewRPCClient(conn).Read(context.Background(), req, cfg.CallOpts()...)
+					if err != nil {
+						return nil, err
+					}
+					for {
+						data, err := stream.Recv()
+						if err == io.EOF {
+							return nil, nil
+						}
+						if err != nil {
+							return nil, err
+						}
+						if h != nil {
+							fmt.Fprintf(os.Stdout, "%s: %s\n", h.Name, string(data.Content))
+						} else {
+							fmt.Fprintln(os.Stdout, string(data.Content))
+						}
 					}
+				})
+			}
+		})
+		cmd.Command("query", "Query structured entries of the server log", func(cmd *cli.Cmd) {
+			var (
+				level    = cmd.StringOpt("level", "info", "minimum level")
+				since    = cmd.StringOpt("since", "", "earliest entry as a duration before now or a timestamp")
+				until    = cmd.StringOpt("until", "", "latest entry as a duration before now or a timestamp")
+				service  = cmd.StringOpt("s service", "", "logger name such as a service ID")
+				contains = cmd.StringOpt("c contains", "", "substring of the message")
+				match    = cmd.StringOpt("m match", "", "regular expression matching the message")
+				limit    = cmd.IntOpt("n limit", 0, "maximum number of entries")
+			)
+			cmd.Spec = "[OPTIONS]"
+			req := &QueryRequest{}
+			cmd.Before = func() {
+				req.Level = *level
+				req.Logger = *service
+				req.Contains = *contains
+				req.Match = *match
+				req.Limit = int64(*limit)
+				if *since != "" {
+					t, err := ParseTime(*since)
 					util.Maybe(err)
-					//util.JSONToStdout(data)
-					fmt.Fprintln(os.Stdout, string(data.Content))
+					req.Since = t.UnixNano()
 				}
+				if *until != "" {
+					t, err := ParseTime(*until)
+					util.Maybe(err)
+					req.Until = t.UnixNano()
+				}
+			}
+			cmd.Action = func() {
+				util.Remote(*cfg, func(h *host.Host, conn *grpc.ClientConn) (interface{}, error) {
+					stream, err := NewRPCClient(conn).Query(context.Background(), req, cfg.CallOpts()...)
+					if err != nil {
+						return nil, err
+					}
+					for {
+						record, err := stream.Recv()
+						if err == io.EOF {
+							return nil, nil
+						}
+						if err != nil {
+							return nil, err
+						}
+						raw, err := formatRecord(cfg.Output, record)
+						if err != nil {
+							return nil, err
+						}
+						if h != nil {
+							fmt.Fprintf(os.Stdout, "%s: %s\n", h.Name, string(raw))
+						} else {
+							fmt.Fprintln(os.Stdout, string(raw))
+						}
+					}
+				})
+			}
+		})
+		cmd.Command("level", "Show or change the log level", func(cmd *cli.Cmd) {
+			var (
+				level      = cmd.StringArg("LEVEL", "", "level such as debug")
+				logger     = cmd.StringOpt("l logger", "", "logger name such as a plugin or service ID")
+				duration   = cmd.StringOpt("for", "", "duration after which the previous level is restored")
+				clearLevel = cmd.BoolOpt("clear", false, "log at the global level again")
+			)
+			cmd.Spec = "[OPTIONS] [LEVEL]"
+			cmd.Action = func() {
+				util.Remote(*cfg, func(h *host.Host, conn *grpc.ClientConn) (interface{}, error) {
+					client := NewRPCClient(conn)
+					if *level == "" && !*clearLevel {
+						return client.GetLevel(context.Background(), &GetLevelRequest{}, cfg.CallOpts()...)
+					}
+					req := &SetLevelRequest{Level: *level, Logger: *logger, Clear: *clearLevel}
+					if *duration != "" {
+						d, err := time.ParseDuration(*duration)
+						if err != nil {
+							return nil, err
+						}
+						// Durations are sent in seconds and
+						// zero would make the level permanent.
+						if d < time.Second {
+							return nil, fmt.Errorf("duration %s is shorter than 1s", d)
+						}
+						req.Duration = int64(d / time.Second)
+					}
+					return client.SetLevel(context.Background(), req, cfg.CallOpts()...)
+				})
+			}
+		})
+		cmd.Command("sinks", "Show remote log destinations", func(cmd *cli.Cmd) {
+			cmd.Action = func() {
+				util.Remote(*cfg, func(h *host.Host, conn *grpc.ClientConn) (interface{}, error) {
+					return NewRPCClient(conn).Sinks(context.Background(), &SinksRequest{}, cfg.CallOpts()...)
+				})
 			}
 		})
 		cmd.Command("write", "Write to the remote server log", func(cmd *cli.Cmd) {
+			var (
+				source = cmd.StringOpt("s source", "", "logger name such as a service ID")
+				level  = cmd.StringOpt("level", "info", "level of each line")
+				fields = cmd.StringsOpt("f field", []string{}, "field of each line formatted as KEY=VALUE")
+			)
+			cmd.Spec = "[OPTIONS]"
+			parsed := map[string]string{}
+			cmd.Before = func() {
+				for _, field := range *fields {
+					parts := strings.SplitN(field, "=", 2)
+					if len(parts) != 2 {
+						util.Maybe(fmt.Errorf("bad field %s, expected KEY=VALUE", field))
+					}
+					parsed[parts[0]] = parts[1]
+				}
+			}
 			cmd.Action = func() {
-				stream, err := client.Write(context.Background(), cfg.CallOpts()...)
-				util.Maybe(err)
 				scanner := bufio.NewScanner(os.Stdin)
-				var offset int
-				for scanner.Scan() {
-					offset += len(scanner.Bytes())
-					err = stream.Send(&LogData{Content: scanner.Bytes(), Offset: int64(offset)})
-					util.Maybe(err)
+				var input [][]byte
+				if cfg.Remote.Selected() {
+					// Input is sent to every
+					// host so read it up front.
+					for scanner.Scan() {
+						input = append(input, append([]byte{}, scanner.Bytes()...))
+					}
+					util.Maybe(scanner.Err())
 				}
+				util.Remote(*cfg, func(h *host.Host, conn *grpc.ClientConn) (interface{}, error) {
+					stream, err := NewRPCClient(conn).Write(context.Background(), cfg.CallOpts()...)
+					if err != nil {
+						return nil, err
+					}
+					var offset int
+					send := func(line []byte) error {
+						offset += len(line)
+						return stream.Send(&LogData{
+							Content: line,
+							Offset:  int64(offset),
+							Source:  *source,
+							Level:   *level,
+							Fields:  parsed,
+						})
+					}
+					if h == nil {
+						for scanner.Scan() {
+							if err := send(scanner.Bytes()); err != nil {
+								return nil, err
+							}
+						}
+					} else {
+						for _, line := range input {
+							if err := send(line); err != nil {
+								return nil, err
+							}
+						}
+					}
+					return stream.CloseAndRecv()
+				})
 			}
 		})
 	}
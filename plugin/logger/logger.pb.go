@@ -11,6 +11,16 @@ It has these top-level messages:
 	WriteResponse
 	ReadRequest
 	LogData
+	Cursor
+	QueryRequest
+	Record
+	GetLevelRequest
+	SetLevelRequest
+	LevelResponse
+	Level
+	SinksRequest
+	SinksResponse
+	Sink
 */
 package logger
 
@@ -46,6 +56,16 @@ type ReadRequest struct {
 	Follow bool  `protobuf:"varint,1,opt,name=follow" json:"follow,omitempty"`
 	Lines  int64 `protobuf:"varint,2,opt,name=lines" json:"lines,omitempty"`
 	Offset int64 `protobuf:"varint,3,opt,name=offset" json:"offset,omitempty"`
+	// Read the last lines before the end of
+	// the log file including rotated backups
+	// rather than reading from the offset.
+	Tail bool `protobuf:"varint,4,opt,name=tail" json:"tail,omitempty"`
+	// Read from the first line written at or
+	// after this Unix time in nanoseconds.
+	Since int64 `protobuf:"varint,5,opt,name=since" json:"since,omitempty"`
+	// Resume reading after a cursor
+	// returned by a previous read.
+	Cursor *Cursor `protobuf:"bytes,6,opt,name=cursor" json:"cursor,omitempty"`
 }
 
 func (m *ReadRequest) Reset()                    { *m = ReadRequest{} }
@@ -74,9 +94,44 @@ func (m *ReadRequest) GetOffset() int64 {
 	return 0
 }
 
+func (m *ReadRequest) GetTail() bool {
+	if m != nil {
+		return m.Tail
+	}
+	return false
+}
+
+func (m *ReadRequest) GetSince() int64 {
+	if m != nil {
+		return m.Since
+	}
+	return 0
+}
+
+func (m *ReadRequest) GetCursor() *Cursor {
+	if m != nil {
+		return m.Cursor
+	}
+	return nil
+}
+
 type LogData struct {
 	Content []byte `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
-	Offset  int64  `protobuf:"varint,2,opt,name=offset" json:"offset,omitempty"`
+	// Offset following the content
+	// within the file it was read from
+	Offset int64 `protobuf:"varint,2,opt,name=offset" json:"offset,omitempty"`
+	// Cursor to resume reading after
+	// the content.
+	Cursor *Cursor `protobuf:"bytes,3,opt,name=cursor" json:"cursor,omitempty"`
+	// Logger name written content is
+	// attributed to such as a service ID,
+	// prefixed with peer unless written
+	// by a service
+	Source string `protobuf:"bytes,4,opt,name=source" json:"source,omitempty"`
+	// Level of written content, info if empty
+	Level string `protobuf:"bytes,5,opt,name=level" json:"level,omitempty"`
+	// Additional fields of written content
+	Fields map[string]string `protobuf:"bytes,6,rep,name=fields" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 }
 
 func (m *LogData) Reset()                    { *m = LogData{} }
@@ -98,10 +153,400 @@ func (m *LogData) GetOffset() int64 {
 	return 0
 }
 
+func (m *LogData) GetCursor() *Cursor {
+	if m != nil {
+		return m.Cursor
+	}
+	return nil
+}
+
+func (m *LogData) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+func (m *LogData) GetLevel() string {
+	if m != nil {
+		return m.Level
+	}
+	return ""
+}
+
+func (m *LogData) GetFields() map[string]string {
+	if m != nil {
+		return m.Fields
+	}
+	return nil
+}
+
+// Cursor is a position in the log
+// which is stable across rotations.
+type Cursor struct {
+	// Device and inode of the file
+	File   string `protobuf:"bytes,1,opt,name=file" json:"file,omitempty"`
+	Offset int64  `protobuf:"varint,2,opt,name=offset" json:"offset,omitempty"`
+	// Unix time in nanoseconds of the last entry
+	// read which finds the backup the file was
+	// rotated to once it is compressed
+	Time int64 `protobuf:"varint,3,opt,name=time" json:"time,omitempty"`
+}
+
+func (m *Cursor) Reset()                    { *m = Cursor{} }
+func (m *Cursor) String() string            { return proto.CompactTextString(m) }
+func (*Cursor) ProtoMessage()               {}
+func (*Cursor) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *Cursor) GetFile() string {
+	if m != nil {
+		return m.File
+	}
+	return ""
+}
+
+func (m *Cursor) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *Cursor) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+type QueryRequest struct {
+	// Minimum level such as warn
+	Level string `protobuf:"bytes,1,opt,name=level" json:"level,omitempty"`
+	// Unix time in nanoseconds of the earliest
+	// and latest records, unbounded if zero
+	Since int64 `protobuf:"varint,2,opt,name=since" json:"since,omitempty"`
+	Until int64 `protobuf:"varint,3,opt,name=until" json:"until,omitempty"`
+	// Logger name such as a service ID
+	Logger string `protobuf:"bytes,4,opt,name=logger" json:"logger,omitempty"`
+	// Substring of the message
+	Contains string `protobuf:"bytes,5,opt,name=contains" json:"contains,omitempty"`
+	// Regular expression matching the message
+	Match string `protobuf:"bytes,6,opt,name=match" json:"match,omitempty"`
+	// Maximum number of records, all if zero
+	Limit int64 `protobuf:"varint,7,opt,name=limit" json:"limit,omitempty"`
+}
+
+func (m *QueryRequest) Reset()                    { *m = QueryRequest{} }
+func (m *QueryRequest) String() string            { return proto.CompactTextString(m) }
+func (*QueryRequest) ProtoMessage()               {}
+func (*QueryRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+func (m *QueryRequest) GetLevel() string {
+	if m != nil {
+		return m.Level
+	}
+	return ""
+}
+
+func (m *QueryRequest) GetSince() int64 {
+	if m != nil {
+		return m.Since
+	}
+	return 0
+}
+
+func (m *QueryRequest) GetUntil() int64 {
+	if m != nil {
+		return m.Until
+	}
+	return 0
+}
+
+func (m *QueryRequest) GetLogger() string {
+	if m != nil {
+		return m.Logger
+	}
+	return ""
+}
+
+func (m *QueryRequest) GetContains() string {
+	if m != nil {
+		return m.Contains
+	}
+	return ""
+}
+
+func (m *QueryRequest) GetMatch() string {
+	if m != nil {
+		return m.Match
+	}
+	return ""
+}
+
+func (m *QueryRequest) GetLimit() int64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+// Record is a structured log entry.
+type Record struct {
+	// Unix time in nanoseconds
+	Time    int64  `protobuf:"varint,1,opt,name=time" json:"time,omitempty"`
+	Level   string `protobuf:"bytes,2,opt,name=level" json:"level,omitempty"`
+	Logger  string `protobuf:"bytes,3,opt,name=logger" json:"logger,omitempty"`
+	Message string `protobuf:"bytes,4,opt,name=message" json:"message,omitempty"`
+	Caller  string `protobuf:"bytes,5,opt,name=caller" json:"caller,omitempty"`
+	// Additional fields, values other than
+	// strings are JSON encoded
+	Fields map[string]string `protobuf:"bytes,6,rep,name=fields" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+}
+
+func (m *Record) Reset()                    { *m = Record{} }
+func (m *Record) String() string            { return proto.CompactTextString(m) }
+func (*Record) ProtoMessage()               {}
+func (*Record) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *Record) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func (m *Record) GetLevel() string {
+	if m != nil {
+		return m.Level
+	}
+	return ""
+}
+
+func (m *Record) GetLogger() string {
+	if m != nil {
+		return m.Logger
+	}
+	return ""
+}
+
+func (m *Record) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *Record) GetCaller() string {
+	if m != nil {
+		return m.Caller
+	}
+	return ""
+}
+
+func (m *Record) GetFields() map[string]string {
+	if m != nil {
+		return m.Fields
+	}
+	return nil
+}
+
+type GetLevelRequest struct {
+}
+
+func (m *GetLevelRequest) Reset()                    { *m = GetLevelRequest{} }
+func (m *GetLevelRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetLevelRequest) ProtoMessage()               {}
+func (*GetLevelRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+type SetLevelRequest struct {
+	// Level such as debug
+	Level string `protobuf:"bytes,1,opt,name=level" json:"level,omitempty"`
+	// Logger name such as a service ID,
+	// the global level if empty
+	Logger string `protobuf:"bytes,2,opt,name=logger" json:"logger,omitempty"`
+	// Seconds after which the previous
+	// level is restored, never if zero
+	Duration int64 `protobuf:"varint,3,opt,name=duration" json:"duration,omitempty"`
+	// Remove the level of the named logger
+	// so it logs at the global level
+	Clear bool `protobuf:"varint,4,opt,name=clear" json:"clear,omitempty"`
+}
+
+func (m *SetLevelRequest) Reset()                    { *m = SetLevelRequest{} }
+func (m *SetLevelRequest) String() string            { return proto.CompactTextString(m) }
+func (*SetLevelRequest) ProtoMessage()               {}
+func (*SetLevelRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+func (m *SetLevelRequest) GetLevel() string {
+	if m != nil {
+		return m.Level
+	}
+	return ""
+}
+
+func (m *SetLevelRequest) GetLogger() string {
+	if m != nil {
+		return m.Logger
+	}
+	return ""
+}
+
+func (m *SetLevelRequest) GetDuration() int64 {
+	if m != nil {
+		return m.Duration
+	}
+	return 0
+}
+
+func (m *SetLevelRequest) GetClear() bool {
+	if m != nil {
+		return m.Clear
+	}
+	return false
+}
+
+type LevelResponse struct {
+	// The global level followed by
+	// the level of each named logger
+	Levels []*Level `protobuf:"bytes,1,rep,name=levels" json:"levels,omitempty"`
+}
+
+func (m *LevelResponse) Reset()                    { *m = LevelResponse{} }
+func (m *LevelResponse) String() string            { return proto.CompactTextString(m) }
+func (*LevelResponse) ProtoMessage()               {}
+func (*LevelResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+func (m *LevelResponse) GetLevels() []*Level {
+	if m != nil {
+		return m.Levels
+	}
+	return nil
+}
+
+type Level struct {
+	Logger string `protobuf:"bytes,1,opt,name=logger" json:"logger,omitempty"`
+	Level  string `protobuf:"bytes,2,opt,name=level" json:"level,omitempty"`
+	// Unix time in nanoseconds the previous
+	// level is restored, never if zero
+	Revert int64 `protobuf:"varint,3,opt,name=revert" json:"revert,omitempty"`
+}
+
+func (m *Level) Reset()                    { *m = Level{} }
+func (m *Level) String() string            { return proto.CompactTextString(m) }
+func (*Level) ProtoMessage()               {}
+func (*Level) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+func (m *Level) GetLogger() string {
+	if m != nil {
+		return m.Logger
+	}
+	return ""
+}
+
+func (m *Level) GetLevel() string {
+	if m != nil {
+		return m.Level
+	}
+	return ""
+}
+
+func (m *Level) GetRevert() int64 {
+	if m != nil {
+		return m.Revert
+	}
+	return 0
+}
+
+type SinksRequest struct {
+}
+
+func (m *SinksRequest) Reset()                    { *m = SinksRequest{} }
+func (m *SinksRequest) String() string            { return proto.CompactTextString(m) }
+func (*SinksRequest) ProtoMessage()               {}
+func (*SinksRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+type SinksResponse struct {
+	Sinks []*Sink `protobuf:"bytes,1,rep,name=sinks" json:"sinks,omitempty"`
+}
+
+func (m *SinksResponse) Reset()                    { *m = SinksResponse{} }
+func (m *SinksResponse) String() string            { return proto.CompactTextString(m) }
+func (*SinksResponse) ProtoMessage()               {}
+func (*SinksResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+func (m *SinksResponse) GetSinks() []*Sink {
+	if m != nil {
+		return m.Sinks
+	}
+	return nil
+}
+
+// Sink is a remote log destination.
+type Sink struct {
+	Format  string `protobuf:"bytes,1,opt,name=format" json:"format,omitempty"`
+	Address string `protobuf:"bytes,2,opt,name=address" json:"address,omitempty"`
+	// Entries written to the destination
+	Sent uint64 `protobuf:"varint,3,opt,name=sent" json:"sent,omitempty"`
+	// Entries dropped while the
+	// destination was unreachable
+	Dropped   uint64 `protobuf:"varint,4,opt,name=dropped" json:"dropped,omitempty"`
+	Connected bool   `protobuf:"varint,5,opt,name=connected" json:"connected,omitempty"`
+}
+
+func (m *Sink) Reset()                    { *m = Sink{} }
+func (m *Sink) String() string            { return proto.CompactTextString(m) }
+func (*Sink) ProtoMessage()               {}
+func (*Sink) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+func (m *Sink) GetFormat() string {
+	if m != nil {
+		return m.Format
+	}
+	return ""
+}
+
+func (m *Sink) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *Sink) GetSent() uint64 {
+	if m != nil {
+		return m.Sent
+	}
+	return 0
+}
+
+func (m *Sink) GetDropped() uint64 {
+	if m != nil {
+		return m.Dropped
+	}
+	return 0
+}
+
+func (m *Sink) GetConnected() bool {
+	if m != nil {
+		return m.Connected
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterType((*WriteResponse)(nil), "logger.WriteResponse")
 	proto.RegisterType((*ReadRequest)(nil), "logger.ReadRequest")
 	proto.RegisterType((*LogData)(nil), "logger.LogData")
+	proto.RegisterType((*Cursor)(nil), "logger.Cursor")
+	proto.RegisterType((*QueryRequest)(nil), "logger.QueryRequest")
+	proto.RegisterType((*Record)(nil), "logger.Record")
+	proto.RegisterType((*GetLevelRequest)(nil), "logger.GetLevelRequest")
+	proto.RegisterType((*SetLevelRequest)(nil), "logger.SetLevelRequest")
+	proto.RegisterType((*LevelResponse)(nil), "logger.LevelResponse")
+	proto.RegisterType((*Level)(nil), "logger.Level")
+	proto.RegisterType((*SinksRequest)(nil), "logger.SinksRequest")
+	proto.RegisterType((*SinksResponse)(nil), "logger.SinksResponse")
+	proto.RegisterType((*Sink)(nil), "logger.Sink")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -117,6 +562,10 @@ const _ = grpc.SupportPackageIsVersion4
 type RPCClient interface {
 	Read(ctx context.Context, in *ReadRequest, opts ...grpc.CallOption) (RPC_ReadClient, error)
 	Write(ctx context.Context, opts ...grpc.CallOption) (RPC_WriteClient, error)
+	Query(ctx context.Context, in *QueryRequest, opts ...grpc.CallOption) (RPC_QueryClient, error)
+	GetLevel(ctx context.Context, in *GetLevelRequest, opts ...grpc.CallOption) (*LevelResponse, error)
+	SetLevel(ctx context.Context, in *SetLevelRequest, opts ...grpc.CallOption) (*LevelResponse, error)
+	Sinks(ctx context.Context, in *SinksRequest, opts ...grpc.CallOption) (*SinksResponse, error)
 }
 
 type rPCClient struct {
@@ -193,11 +642,74 @@ func (x *rPCWriteClient) CloseAndRecv() (*WriteResponse, error) {
 	return m, nil
 }
 
+func (c *rPCClient) Query(ctx context.Context, in *QueryRequest, opts ...grpc.CallOption) (RPC_QueryClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_RPC_serviceDesc.Streams[2], c.cc, "/logger.RPC/Query", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &rPCQueryClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type RPC_QueryClient interface {
+	Recv() (*Record, error)
+	grpc.ClientStream
+}
+
+type rPCQueryClient struct {
+	grpc.ClientStream
+}
+
+func (x *rPCQueryClient) Recv() (*Record, error) {
+	m := new(Record)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *rPCClient) GetLevel(ctx context.Context, in *GetLevelRequest, opts ...grpc.CallOption) (*LevelResponse, error) {
+	out := new(LevelResponse)
+	err := grpc.Invoke(ctx, "/logger.RPC/GetLevel", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rPCClient) SetLevel(ctx context.Context, in *SetLevelRequest, opts ...grpc.CallOption) (*LevelResponse, error) {
+	out := new(LevelResponse)
+	err := grpc.Invoke(ctx, "/logger.RPC/SetLevel", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rPCClient) Sinks(ctx context.Context, in *SinksRequest, opts ...grpc.CallOption) (*SinksResponse, error) {
+	out := new(SinksResponse)
+	err := grpc.Invoke(ctx, "/logger.RPC/Sinks", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for RPC service
 
 type RPCServer interface {
 	Read(*ReadRequest, RPC_ReadServer) error
 	Write(RPC_WriteServer) error
+	Query(*QueryRequest, RPC_QueryServer) error
+	GetLevel(context.Context, *GetLevelRequest) (*LevelResponse, error)
+	SetLevel(context.Context, *SetLevelRequest) (*LevelResponse, error)
+	Sinks(context.Context, *SinksRequest) (*SinksResponse, error)
 }
 
 func RegisterRPCServer(s *grpc.Server, srv RPCServer) {
@@ -251,10 +763,98 @@ func (x *rPCWriteServer) Recv() (*LogData, error) {
 	return m, nil
 }
 
+func _RPC_Query_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(QueryRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RPCServer).Query(m, &rPCQueryServer{stream})
+}
+
+type RPC_QueryServer interface {
+	Send(*Record) error
+	grpc.ServerStream
+}
+
+type rPCQueryServer struct {
+	grpc.ServerStream
+}
+
+func (x *rPCQueryServer) Send(m *Record) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _RPC_GetLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCServer).GetLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/logger.RPC/GetLevel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCServer).GetLevel(ctx, req.(*GetLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RPC_SetLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCServer).SetLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/logger.RPC/SetLevel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCServer).SetLevel(ctx, req.(*SetLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RPC_Sinks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SinksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCServer).Sinks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/logger.RPC/Sinks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCServer).Sinks(ctx, req.(*SinksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _RPC_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "logger.RPC",
 	HandlerType: (*RPCServer)(nil),
-	Methods:     []grpc.MethodDesc{},
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetLevel",
+			Handler:    _RPC_GetLevel_Handler,
+		},
+		{
+			MethodName: "SetLevel",
+			Handler:    _RPC_SetLevel_Handler,
+		},
+		{
+			MethodName: "Sinks",
+			Handler:    _RPC_Sinks_Handler,
+		},
+	},
 	Streams: []grpc.StreamDesc{
 		{
 			StreamName:    "Read",
@@ -266,6 +866,11 @@ var _RPC_serviceDesc = grpc.ServiceDesc{
 			Handler:       _RPC_Write_Handler,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "Query",
+			Handler:       _RPC_Query_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "github.com/mesanine/gaffer/plugin/logger/logger.proto",
 }
@@ -275,20 +880,53 @@ func init() {
 }
 
 var fileDescriptor0 = []byte{
-	// 238 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x5c, 0x90, 0x3f, 0x4f, 0xc3, 0x30,
-	0x10, 0xc5, 0xeb, 0x86, 0xa6, 0xe8, 0x00, 0x55, 0x32, 0x7f, 0x14, 0x75, 0xaa, 0x32, 0x65, 0x4a,
-	0x2a, 0x2a, 0x26, 0x46, 0x18, 0x19, 0x90, 0x19, 0x98, 0xdd, 0x72, 0x36, 0x96, 0x5c, 0x5f, 0x88,
-	0x1d, 0xf1, 0xf5, 0x51, 0x1d, 0x47, 0x0a, 0x9d, 0xac, 0xdf, 0xb3, 0x9f, 0xef, 0xbd, 0x83, 0x27,
-	0x6d, 0xc2, 0x77, 0xbf, 0xaf, 0x0f, 0x74, 0x6c, 0x8e, 0xe8, 0xa5, 0x33, 0x0e, 0x1b, 0x2d, 0x95,
-	0xc2, 0xae, 0x69, 0x6d, 0xaf, 0x8d, 0x6b, 0x2c, 0x69, 0x8d, 0x5d, 0x3a, 0xea, 0xb6, 0xa3, 0x40,
-	0x3c, 0x1f, 0xa8, 0x5c, 0xc1, 0xcd, 0x67, 0x67, 0x02, 0x0a, 0xf4, 0x2d, 0x39, 0x8f, 0xe5, 0x07,
-	0x5c, 0x09, 0x94, 0x5f, 0x02, 0x7f, 0x7a, 0xf4, 0x81, 0x3f, 0x40, 0xae, 0xc8, 0x5a, 0xfa, 0x2d,
-	0xd8, 0x86, 0x55, 0x97, 0x22, 0x11, 0xbf, 0x83, 0x85, 0x35, 0x0e, 0x7d, 0x31, 0xdf, 0xb0, 0x2a,
-	0x13, 0x03, 0x9c, 0x5e, 0x93, 0x52, 0x1e, 0x43, 0x91, 0x45, 0x39, 0x51, 0xf9, 0x0c, 0xcb, 0x37,
-	0xd2, 0xaf, 0x32, 0x48, 0x5e, 0xc0, 0xf2, 0x40, 0x2e, 0xa0, 0x0b, 0xf1, 0xc7, 0x6b, 0x31, 0xe2,
-	0xc4, 0x3c, 0x9f, 0x9a, 0x1f, 0x2d, 0x64, 0xe2, 0xfd, 0x85, 0x6f, 0xe1, 0xe2, 0x14, 0x8c, 0xdf,
-	0xd6, 0xa9, 0xc8, 0x24, 0xe6, 0x7a, 0x35, 0x8a, 0x69, 0x4c, 0x39, 0xdb, 0x32, 0xbe, 0x83, 0x45,
-	0xec, 0xc6, 0xcf, 0x6f, 0xd7, 0xf7, 0xa3, 0xf0, 0xbf, 0xfb, 0xac, 0x62, 0xfb, 0x3c, 0xee, 0x67,
-	0xf7, 0x17, 0x00, 0x00, 0xff, 0xff, 0xbf, 0x2f, 0xe6, 0x0e, 0x58, 0x01, 0x00, 0x00,
+	// 760 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x55, 0xcd, 0x8e, 0xeb, 0x34,
+	0x14, 0x9e, 0xa4, 0x6d, 0xa6, 0x3d, 0x6d, 0xa7, 0x60, 0xe6, 0x42, 0x54, 0x58, 0x54, 0x91, 0x40,
+	0x5d, 0xb5, 0x97, 0x56, 0x5c, 0x01, 0x62, 0x77, 0xf9, 0x5b, 0x0c, 0x12, 0xb8, 0x0b, 0xd6, 0xb9,
+	0xc9, 0x69, 0xaf, 0x35, 0x89, 0xdd, 0xb1, 0x9d, 0x41, 0xb3, 0x44, 0xe2, 0x55, 0xd8, 0xf3, 0x2e,
+	0x3c, 0x06, 0x2f, 0x81, 0xfc, 0x97, 0xb4, 0x45, 0x83, 0x58, 0xb0, 0xaa, 0x3f, 0xdb, 0xe7, 0x9c,
+	0xef, 0xf3, 0x77, 0x72, 0x0a, 0x9f, 0x1d, 0x98, 0x7e, 0xdb, 0xbc, 0x59, 0x15, 0xa2, 0x5e, 0xd7,
+	0xa8, 0x72, 0xce, 0x38, 0xae, 0x0f, 0xf9, 0x7e, 0x8f, 0x72, 0x7d, 0xac, 0x9a, 0x03, 0xe3, 0xeb,
+	0x4a, 0x1c, 0x0e, 0x28, 0xfd, 0xcf, 0xea, 0x28, 0x85, 0x16, 0x24, 0x71, 0x28, 0x9b, 0xc1, 0xf4,
+	0x67, 0xc9, 0x34, 0x52, 0x54, 0x47, 0xc1, 0x15, 0x66, 0xbf, 0x47, 0x30, 0xa6, 0x98, 0x97, 0x14,
+	0x1f, 0x1a, 0x54, 0x9a, 0xbc, 0x0f, 0xc9, 0x5e, 0x54, 0x95, 0xf8, 0x25, 0x8d, 0x16, 0xd1, 0x72,
+	0x48, 0x3d, 0x22, 0xb7, 0x30, 0xa8, 0x18, 0x47, 0x95, 0xc6, 0x8b, 0x68, 0xd9, 0xa3, 0x0e, 0x98,
+	0xdb, 0x62, 0xbf, 0x57, 0xa8, 0xd3, 0x9e, 0xdd, 0xf6, 0x88, 0x10, 0xe8, 0xeb, 0x9c, 0x55, 0x69,
+	0xdf, 0xe6, 0xb0, 0x6b, 0x93, 0x41, 0x31, 0x5e, 0x60, 0x3a, 0x70, 0x19, 0x2c, 0x20, 0x9f, 0x40,
+	0x52, 0x34, 0x52, 0x09, 0x99, 0x26, 0x8b, 0x68, 0x39, 0xde, 0xdc, 0xac, 0x3c, 0xef, 0xd7, 0x76,
+	0x97, 0xfa, 0xd3, 0xec, 0xd7, 0x18, 0xae, 0xef, 0xc4, 0xe1, 0xeb, 0x5c, 0xe7, 0x24, 0x85, 0xeb,
+	0x42, 0x70, 0x8d, 0x5c, 0x5b, 0x92, 0x13, 0x1a, 0xe0, 0x09, 0x9f, 0xf8, 0x8c, 0x4f, 0x57, 0xa5,
+	0xf7, 0x6f, 0x55, 0x4c, 0xbc, 0x12, 0x8d, 0x2c, 0xd0, 0x32, 0x1f, 0x51, 0x8f, 0xac, 0x7a, 0x7c,
+	0xc4, 0xca, 0x72, 0x1f, 0x51, 0x07, 0xc8, 0x16, 0x92, 0x3d, 0xc3, 0xaa, 0x54, 0x69, 0xb2, 0xe8,
+	0x2d, 0xc7, 0x9b, 0x0f, 0x43, 0x56, 0x4f, 0x74, 0xf5, 0xad, 0x3d, 0xfd, 0x86, 0x6b, 0xf9, 0x44,
+	0xfd, 0xd5, 0xf9, 0x17, 0x30, 0x3e, 0xd9, 0x26, 0xef, 0x40, 0xef, 0x1e, 0x9f, 0xac, 0x8e, 0x11,
+	0x35, 0x4b, 0x53, 0xeb, 0x31, 0xaf, 0x1a, 0xb4, 0x12, 0x46, 0xd4, 0x81, 0x2f, 0xe3, 0xcf, 0xa3,
+	0xec, 0x7b, 0x48, 0x1c, 0x5f, 0xf3, 0xbe, 0x7b, 0x56, 0xa1, 0x0f, 0xb3, 0xeb, 0x67, 0xb5, 0x1b,
+	0x2f, 0x58, 0x8d, 0xde, 0x21, 0xbb, 0xce, 0xfe, 0x88, 0x60, 0xf2, 0x53, 0x83, 0xf2, 0x29, 0xd8,
+	0xde, 0x0a, 0x8c, 0x4e, 0x05, 0xb6, 0x96, 0xc5, 0xa7, 0x96, 0xdd, 0xc2, 0xa0, 0xe1, 0x9a, 0x55,
+	0x3e, 0xa3, 0x03, 0xa6, 0xbc, 0x53, 0x1f, 0x9e, 0xce, 0x21, 0x32, 0x87, 0xa1, 0x71, 0x27, 0x67,
+	0x5c, 0xf9, 0xd7, 0x6b, 0xb1, 0xc9, 0x54, 0xe7, 0xba, 0x78, 0x6b, 0xbd, 0x1f, 0x51, 0x07, 0x5c,
+	0xab, 0xd5, 0x4c, 0xa7, 0xd7, 0xa1, 0xd5, 0x6a, 0xa6, 0xb3, 0xbf, 0x22, 0x48, 0x28, 0x16, 0x42,
+	0x96, 0xad, 0xa2, 0xa8, 0x53, 0xd4, 0x09, 0x88, 0x4f, 0x05, 0x74, 0xa4, 0x7a, 0x67, 0xa4, 0x52,
+	0xb8, 0xae, 0x51, 0xa9, 0xfc, 0x10, 0x8c, 0x0e, 0xd0, 0x44, 0x14, 0x79, 0x55, 0xa1, 0xf4, 0x64,
+	0x3d, 0x22, 0x9b, 0x0b, 0xaf, 0xe7, 0xc1, 0x6b, 0xc7, 0xe9, 0xff, 0xb6, 0xfa, 0x5d, 0x98, 0x7d,
+	0x87, 0xfa, 0xce, 0x88, 0xf0, 0x16, 0x65, 0x0f, 0x30, 0xdb, 0x9d, 0x6f, 0x3d, 0xe3, 0x5a, 0x27,
+	0x3a, 0xbe, 0x74, 0xa2, 0x6c, 0x64, 0xae, 0x99, 0xe0, 0xde, 0xba, 0x16, 0x9b, 0x4c, 0x45, 0x85,
+	0xb9, 0xf4, 0x5f, 0xac, 0x03, 0xd9, 0x2b, 0x98, 0xfa, 0x7a, 0x6e, 0x5a, 0x90, 0x8f, 0x21, 0xb1,
+	0x35, 0x54, 0x1a, 0xd9, 0x57, 0x98, 0xb6, 0x1d, 0x6f, 0xaf, 0xf9, 0xc3, 0xec, 0x07, 0x18, 0xdc,
+	0x5d, 0x50, 0x89, 0xce, 0xa8, 0x3c, 0xeb, 0x96, 0xc4, 0x47, 0x94, 0xed, 0x34, 0x71, 0x28, 0xbb,
+	0x81, 0xc9, 0x8e, 0xf1, 0x7b, 0x15, 0x5e, 0x62, 0x0b, 0x53, 0x8f, 0x3d, 0xad, 0xcc, 0xf6, 0xe9,
+	0x7d, 0x60, 0x35, 0x09, 0xac, 0xcc, 0x2d, 0xea, 0x8e, 0xb2, 0xdf, 0x22, 0xe8, 0x1b, 0xec, 0x26,
+	0x9c, 0xac, 0x73, 0x1d, 0x38, 0x39, 0x64, 0x7a, 0x22, 0x2f, 0x4b, 0x89, 0x4a, 0x79, 0x56, 0x01,
+	0x9a, 0x7e, 0x53, 0xc8, 0x1d, 0xab, 0x3e, 0xb5, 0x6b, 0x73, 0xbb, 0x94, 0xe2, 0x78, 0xc4, 0xd2,
+	0x3e, 0x59, 0x9f, 0x06, 0x48, 0x3e, 0x82, 0x51, 0x21, 0x38, 0xc7, 0x42, 0x63, 0x69, 0x9b, 0x68,
+	0x48, 0xbb, 0x8d, 0xcd, 0x9f, 0x31, 0xf4, 0xe8, 0x8f, 0xaf, 0xc9, 0x4b, 0xe8, 0x9b, 0xb1, 0x4b,
+	0xde, 0xeb, 0xfa, 0xa8, 0x1d, 0xc2, 0xf3, 0xd9, 0xc5, 0x20, 0xc9, 0xae, 0x5e, 0x46, 0x64, 0x0b,
+	0x03, 0x3b, 0xba, 0xc9, 0xe5, 0xe9, 0xfc, 0x45, 0xd8, 0x38, 0x1f, 0xed, 0x57, 0xcb, 0x88, 0x7c,
+	0x0a, 0x03, 0xfb, 0x9d, 0x93, 0xdb, 0x70, 0xe7, 0xf4, 0xb3, 0x9f, 0xdf, 0x9c, 0x77, 0xb1, 0xad,
+	0xf3, 0x15, 0x0c, 0x43, 0xeb, 0x91, 0x0f, 0xc2, 0xf9, 0x45, 0x33, 0x76, 0x25, 0xcf, 0xfa, 0x23,
+	0xbb, 0x32, 0xd1, 0xbb, 0x7f, 0x44, 0xef, 0xfe, 0x6b, 0xf4, 0x2b, 0x18, 0x58, 0x67, 0x3b, 0xba,
+	0xa7, 0xc6, 0xcf, 0x5f, 0x5c, 0xec, 0x86, 0xb8, 0x37, 0x89, 0xfd, 0x97, 0xdb, 0xfe, 0x3d, 0x00,
+	0xea, 0x23, 0xae, 0xef, 0x1e, 0x07, 0x00, 0x00,
 }
@@ -0,0 +1,197 @@
+package logger
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"go.uber.org/zap/zapcore"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// timeFormat is the ISO8601 format
+// of timestamps in the Gaffer log.
+const timeFormat = "2006-01-02T15:04:05.000Z0700"
+
+// Keys of the production and development
+// encoder configurations respectively.
+var (
+	timeKeys    = []string{"ts", "T"}
+	levelKeys   = []string{"level", "L"}
+	loggerKeys  = []string{"logger", "N"}
+	messageKeys = []string{"msg", "M"}
+	callerKeys  = []string{"caller", "C"}
+)
+
+// query matches records against a QueryRequest.
+type query struct {
+	level zapcore.Level
+	since time.Time
+	until time.Time
+	match *regexp.Regexp
+	req   *QueryRequest
+	count int64
+}
+
+func newQuery(req *QueryRequest) (*query, error) {
+	q := &query{req: req}
+	if err := q.level.UnmarshalText([]byte(strings.ToLower(req.Level))); err != nil {
+		return nil, err
+	}
+	if req.Since != 0 {
+		q.since = time.Unix(0, req.Since)
+	}
+	if req.Until != 0 {
+		q.until = time.Unix(0, req.Until)
+	}
+	if req.Match != "" {
+		match, err := regexp.Compile(req.Match)
+		if err != nil {
+			return nil, err
+		}
+		q.match = match
+	}
+	return q, nil
+}
+
+// files returns the log file and its backups from
+// oldest to newest skipping backups rotated before
+// the start of the query.
+func (q *query) files(path string) ([]string, error) {
+	files, err := rotatedSince(path, q.since)
+	if err != nil {
+		return nil, err
+	}
+	return append(files, path), nil
+}
+
+// scan calls fn with each matching record
+// in the file at path until fn returns false.
+func (q *query) scan(path string, fn func(*Record) bool) (bool, error) {
+	fd, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	defer fd.Close()
+	var r io.Reader = fd
+	if strings.HasSuffix(path, compressSuffix) {
+		zr, err := gzip.NewReader(fd)
+		if err != nil {
+			return false, err
+		}
+		defer zr.Close()
+		r = zr
+	}
+	reader := bufio.NewReader(r)
+	for {
+		raw, err := reader.ReadBytes('\n')
+		if len(raw) > 0 {
+			// Lines which are not JSON
+			// entries are skipped.
+			if record, ok := parseRecord(raw); ok && q.matches(record) {
+				q.count++
+				if !fn(record) {
+					return false, nil
+				}
+				if q.req.Limit > 0 && q.count >= q.req.Limit {
+					return false, nil
+				}
+			}
+		}
+		if err == io.EOF {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+}
+
+func (q *query) matches(record *Record) bool {
+	var level zapcore.Level
+	if err := level.UnmarshalText([]byte(strings.ToLower(record.Level))); err != nil || level < q.level {
+		return false
+	}
+	t := time.Unix(0, record.Time)
+	if !q.since.IsZero() && t.Before(q.since) {
+		return false
+	}
+	if !q.until.IsZero() && t.After(q.until) {
+		return false
+	}
+	if q.req.Logger != "" && record.Logger != q.req.Logger {
+		return false
+	}
+	if q.req.Contains != "" && !strings.Contains(record.Message, q.req.Contains) {
+		return false
+	}
+	if q.match != nil && !q.match.MatchString(record.Message) {
+		return false
+	}
+	return true
+}
+
+// parseRecord parses a zap JSON encoded entry.
+func parseRecord(raw []byte) (*Record, bool) {
+	entry := map[string]json.RawMessage{}
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return nil, false
+	}
+	record := &Record{Fields: map[string]string{}}
+	ts := popString(entry, timeKeys)
+	t, err := time.Parse(timeFormat, ts)
+	if err != nil {
+		return nil, false
+	}
+	record.Time = t.UnixNano()
+	record.Level = popString(entry, levelKeys)
+	record.Logger = popString(entry, loggerKeys)
+	record.Message = popString(entry, messageKeys)
+	record.Caller = popString(entry, callerKeys)
+	for key, value := range entry {
+		// String values are unquoted, any
+		// other values remain JSON encoded.
+		var s string
+		if err := json.Unmarshal(value, &s); err == nil {
+			record.Fields[key] = s
+		} else {
+			record.Fields[key] = string(value)
+		}
+	}
+	return record, true
+}
+
+// popString removes the first of keys
+// present in entry returning its value.
+func popString(entry map[string]json.RawMessage, keys []string) string {
+	for _, key := range keys {
+		if raw, ok := entry[key]; ok {
+			delete(entry, key)
+			var s string
+			json.Unmarshal(raw, &s)
+			return s
+		}
+	}
+	return ""
+}
+
+// ParseTime parses a time for the CLI which is
+// either a duration before now such as 10m or
+// an RFC3339 timestamp.
+func ParseTime(s string) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("bad time %s, expected a duration or RFC3339 timestamp", s)
+	}
+	return t, nil
+}
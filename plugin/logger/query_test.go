@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestQuery(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gaffer-logger")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "gaffer.log")
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "gaffer-2017-01-01T00-00-00.000.log"), []byte(
+		`{"level":"WARN","ts":"2016-12-31T23:00:00.000Z","msg":"old warning"}`+"\n",
+	), 0644))
+	assert.NoError(t, ioutil.WriteFile(path, []byte(
+		`{"level":"INFO","ts":"2017-01-02T00:00:00.000Z","msg":"started"}`+"\n"+
+			"not json\n"+
+			`{"level":"DEBUG","ts":"2017-01-02T00:00:01.000Z","logger":"foo","msg":"foo","stdout":"hello"}`+"\n"+
+			`{"level":"ERROR","ts":"2017-01-02T00:00:02.000+0100","logger":"foo","msg":"foo failed","code":1}`+"\n",
+	), 0644))
+	find := func(req *QueryRequest) []*Record {
+		q, err := newQuery(req)
+		assert.NoError(t, err)
+		files, err := q.files(path)
+		assert.NoError(t, err)
+		records := []*Record{}
+		for _, file := range files {
+			more, err := q.scan(file, func(r *Record) bool {
+				records = append(records, r)
+				return true
+			})
+			assert.NoError(t, err)
+			if !more {
+				break
+			}
+		}
+		return records
+	}
+	records := find(&QueryRequest{Level: "debug"})
+	assert.Len(t, records, 4)
+	assert.Equal(t, "old warning", records[0].Message)
+	assert.Equal(t, "hello", records[2].Fields["stdout"])
+	assert.Equal(t, "1", records[3].Fields["code"])
+	records = find(&QueryRequest{Level: "warn"})
+	assert.Len(t, records, 2)
+	records = find(&QueryRequest{Level: "debug", Logger: "foo", Match: "fail(ed)?$"})
+	assert.Len(t, records, 1)
+	assert.Equal(t, "ERROR", records[0].Level)
+	since, _ := time.Parse(time.RFC3339, "2017-01-01T12:00:00Z")
+	records = find(&QueryRequest{Since: since.UnixNano(), Contains: "start"})
+	assert.Len(t, records, 1)
+	records = find(&QueryRequest{Level: "debug", Limit: 2})
+	assert.Len(t, records, 2)
+}
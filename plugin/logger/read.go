@@ -0,0 +1,173 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// errLimit stops reading once the
+// requested lines have been sent.
+var errLimit = errors.New("line limit reached")
+
+// open returns a follower of the log file positioned
+// for the request after sending any earlier lines it
+// requests including those from rotated backups.
+func open(path string, req *ReadRequest, send func(*line) error) (*follower, error) {
+	switch {
+	case req.Cursor != nil:
+		return resume(path, req.Cursor, send)
+	case req.Since != 0:
+		return replay(path, time.Unix(0, req.Since), send)
+	case req.Tail:
+		f, err := newFollower(path, -1)
+		if err != nil {
+			return nil, err
+		}
+		lines, err := f.tail(int(req.Lines))
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		for _, l := range lines {
+			if err := send(l); err != nil {
+				f.Close()
+				return nil, err
+			}
+		}
+		return f, nil
+	}
+	return newFollower(path, req.Offset)
+}
+
+// resume sends the lines after a cursor in a
+// rotated backup and any newer backups before
+// returning a follower of the log file. The log
+// file itself is followed from the cursor if
+// it has not been rotated.
+func resume(path string, cursor *Cursor, send func(*line) error) (*follower, error) {
+	f, err := newFollower(path, 0)
+	if err != nil {
+		return nil, err
+	}
+	if f.id == cursor.File {
+		if err := f.seek(cursor.Offset); err != nil {
+			f.Close()
+			return nil, err
+		}
+		return f, nil
+	}
+	paths, err := rotatedSince(path, time.Time{})
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	for i, backup := range paths {
+		if id, _ := statID(backup); id == cursor.File {
+			if err := sendFiles(paths[i:], f.id, cursor.Offset, send); err != nil {
+				f.Close()
+				return nil, err
+			}
+			return f, nil
+		}
+	}
+	// A compressed backup is a new file so it is found
+	// by time instead. The file of the cursor was rotated
+	// to the oldest backup rotated after the last entry
+	// read and offsets are within its decompressed lines.
+	if cursor.Time != 0 {
+		t := time.Unix(0, cursor.Time)
+		for i, backup := range paths {
+			// Backup names are truncated to milliseconds
+			rotated, ok := rotationTime(backup)
+			if !ok || rotated.Add(time.Millisecond).Before(t) {
+				continue
+			}
+			if !strings.HasSuffix(backup, compressSuffix) {
+				break
+			}
+			if err := sendFiles(paths[i:], f.id, cursor.Offset, send); err != nil {
+				f.Close()
+				return nil, err
+			}
+			return f, nil
+		}
+	}
+	f.Close()
+	// The file was removed so lines
+	// after the cursor are lost.
+	return nil, fmt.Errorf("log file %s of the cursor no longer exists", cursor.File)
+}
+
+// replay sends the lines of the rotated backups
+// which may have been written after t before
+// returning a follower of the log file.
+func replay(path string, t time.Time, send func(*line) error) (*follower, error) {
+	f, err := newFollower(path, 0)
+	if err != nil {
+		return nil, err
+	}
+	paths, err := rotatedSince(path, t)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := sendFiles(paths, f.id, 0, send); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// sendFiles sends the lines of each file from the
+// offset in the first skipping the followed file
+// if it was rotated after it was opened.
+func sendFiles(paths []string, followed string, offset int64, send func(*line) error) error {
+	for i, path := range paths {
+		if id, _ := statID(path); id == followed {
+			continue
+		}
+		if i > 0 {
+			offset = 0
+		}
+		if err := readFile(path, offset, send); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// since drops lines written before t. Lines
+// without a timestamp such as stack traces
+// are dropped with the line before them.
+func since(t time.Time, send func(*line) error) func(*line) error {
+	var passed bool
+	return func(l *line) error {
+		if !passed {
+			if ts, ok := lineTime(l.content); ok {
+				passed = !ts.Before(t)
+			}
+		}
+		if !passed {
+			return nil
+		}
+		return send(l)
+	}
+}
+
+// lineTime returns the time of a JSON or console
+// encoded entry which begins with a timestamp.
+func lineTime(raw []byte) (time.Time, bool) {
+	if record, ok := parseRecord(raw); ok {
+		return time.Unix(0, record.Time), true
+	}
+	i := bytes.IndexByte(raw, '\t')
+	if i < 0 {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(timeFormat, string(raw[:i]))
+	return t, err == nil
+}
@@ -0,0 +1,93 @@
+package logger
+
+import (
+	"compress/gzip"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func read(t *testing.T, path string, req *ReadRequest) []*line {
+	lines := []*line{}
+	send := func(l *line) error {
+		lines = append(lines, l)
+		return nil
+	}
+	if req.Since != 0 {
+		send = since(time.Unix(0, req.Since), send)
+	}
+	f, err := open(path, req, send)
+	assert.NoError(t, err)
+	defer f.Close()
+	for {
+		l, err := f.next(context.Background(), false)
+		if err == io.EOF {
+			return lines
+		}
+		assert.NoError(t, err)
+		send(l)
+	}
+}
+
+func TestReadCursor(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gaffer-logger")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "gaffer.log")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(
+		"2017-01-01T00:00:00.000Z\tINFO\tone\n"+
+			"2017-01-01T00:01:00.000Z\tINFO\ttwo\n"+
+			"\tstack trace\n",
+	), 0644))
+	lines := read(t, path, &ReadRequest{})
+	assert.Len(t, lines, 3)
+	cursor := &Cursor{File: lines[1].file, Offset: lines[1].offset}
+	// Resume in the same file
+	lines = read(t, path, &ReadRequest{Cursor: cursor})
+	assert.Equal(t, "\tstack trace\n", contents(lines))
+	// Append then rotate
+	fd, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	assert.NoError(t, err)
+	fd.Write([]byte("2017-01-01T00:02:00.000Z\tINFO\tthree\n"))
+	fd.Close()
+	assert.NoError(t, os.Rename(path, filepath.Join(dir, "gaffer-2017-01-01T00-03-00.000.log")))
+	assert.NoError(t, ioutil.WriteFile(path, []byte("2017-01-01T00:04:00.000Z\tINFO\tfour\n"), 0644))
+	lines = read(t, path, &ReadRequest{Cursor: cursor})
+	assert.Equal(t, "\tstack trace\n"+
+		"2017-01-01T00:02:00.000Z\tINFO\tthree\n"+
+		"2017-01-01T00:04:00.000Z\tINFO\tfour\n", contents(lines))
+	// Compress the backup into a new file
+	backup := filepath.Join(dir, "gaffer-2017-01-01T00-03-00.000.log")
+	raw, err := ioutil.ReadFile(backup)
+	assert.NoError(t, err)
+	gz, err := os.Create(backup + compressSuffix)
+	assert.NoError(t, err)
+	zw := gzip.NewWriter(gz)
+	zw.Write(raw)
+	zw.Close()
+	gz.Close()
+	assert.NoError(t, os.Remove(backup))
+	_, err = open(path, &ReadRequest{Cursor: cursor}, nil)
+	assert.Error(t, err)
+	// The compressed backup is found by
+	// the time of the last entry read
+	ts, _ := time.Parse(time.RFC3339, "2017-01-01T00:01:00Z")
+	cursor.Time = ts.UnixNano()
+	lines = read(t, path, &ReadRequest{Cursor: cursor})
+	assert.Equal(t, "\tstack trace\n"+
+		"2017-01-01T00:02:00.000Z\tINFO\tthree\n"+
+		"2017-01-01T00:04:00.000Z\tINFO\tfour\n", contents(lines))
+	_, err = open(path, &ReadRequest{Cursor: &Cursor{File: "0:0"}}, nil)
+	assert.Error(t, err)
+	// Lines since the second including
+	// the stack trace which follows it
+	ts, _ = time.Parse(time.RFC3339, "2017-01-01T00:00:30Z")
+	lines = read(t, path, &ReadRequest{Since: ts.UnixNano()})
+	assert.Len(t, lines, 4)
+	assert.Equal(t, "2017-01-01T00:01:00.000Z\tINFO\ttwo\n", string(lines[0].content))
+}
@@ -0,0 +1,117 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"github.com/mesanine/gaffer/plugin"
+	"github.com/mesanine/gaffer/service"
+	"github.com/mesanine/gaffer/user"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"sort"
+	"strings"
+)
+
+// reserved are keys of written fields
+// which would clash with the encoder
+// or the attribution of the entry.
+var reserved = map[string]bool{
+	"offset":     true,
+	"peer":       true,
+	"pid":        true,
+	"user":       true,
+	"stacktrace": true,
+}
+
+func init() {
+	for _, keys := range [][]string{timeKeys, levelKeys, loggerKeys, messageKeys, callerKeys} {
+		for _, key := range keys {
+			reserved[key] = true
+		}
+	}
+}
+
+// origin returns fields attributing written
+// entries to the calling peer and the ID of
+// the user it authenticated as.
+func origin(ctx context.Context) []zapcore.Field {
+	fields := []zapcore.Field{}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		// Clients of unix sockets
+		// are usually unnamed.
+		addr := p.Addr.String()
+		if addr == "" || addr == "@" {
+			addr = p.Addr.Network()
+		}
+		fields = append(fields, zap.String("peer", addr))
+		if pa, ok := p.Addr.(plugin.PeerAddr); ok {
+			fields = append(fields, zap.Int("pid", pa.PID))
+		}
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md[user.MetadataID]; len(v) > 0 && v[0] != "" {
+			fields = append(fields, zap.String("user", v[0]))
+		}
+	}
+	return fields
+}
+
+// caller returns the ID of the service whose
+// process is the client of a unix socket or
+// "" if the caller is not a service.
+func (l Logger) caller(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	addr, ok := p.Addr.(plugin.PeerAddr)
+	if !ok {
+		return ""
+	}
+	return service.Owner(service.Cgroups(service.Proc, addr.PID), l.services)
+}
+
+// write logs written content with the level,
+// source and fields of data. Entries written
+// by the service id are logged under its name
+// and other callers under peer so they cannot
+// write as a service. Levels which would
+// panic or exit are not permitted.
+func write(logger *zap.Logger, data *LogData, id string, origin []zapcore.Field) error {
+	level := zapcore.InfoLevel
+	if data.Level != "" {
+		if err := level.UnmarshalText([]byte(strings.ToLower(data.Level))); err != nil {
+			return err
+		}
+		if level > zapcore.ErrorLevel {
+			return fmt.Errorf("level %s may not be written", data.Level)
+		}
+	}
+	switch {
+	case id != "":
+		logger = logger.Named(id)
+		if data.Source != "" && data.Source != id {
+			logger = logger.Named(data.Source)
+		}
+	case data.Source != "":
+		logger = logger.Named("peer").Named(data.Source)
+	}
+	fields := append([]zapcore.Field{zap.Int64("offset", data.Offset)}, origin...)
+	keys := []string{}
+	for key := range data.Fields {
+		if reserved[key] {
+			return fmt.Errorf("field %s is reserved", key)
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fields = append(fields, zap.String(key, data.Fields[key]))
+	}
+	if ce := logger.Check(level, string(data.Content)); ce != nil {
+		ce.Write(fields...)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(core)
+	origin := []zapcore.Field{zap.String("peer", "unix"), zap.String("user", "admin")}
+	assert.NoError(t, write(logger, &LogData{
+		Content: []byte("hello"),
+		Offset:  5,
+		Source:  "web",
+		Level:   "WARN",
+		Fields:  map[string]string{"request": "42"},
+	}, "", origin))
+	entries := logs.AllUntimed()
+	assert.Len(t, entries, 1)
+	// Callers which are not services
+	// cannot write as a service
+	assert.Equal(t, "peer.web", entries[0].LoggerName)
+	assert.Equal(t, zapcore.WarnLevel, entries[0].Level)
+	assert.Equal(t, "hello", entries[0].Message)
+	enc := zapcore.NewMapObjectEncoder()
+	for _, field := range entries[0].Context {
+		field.AddTo(enc)
+	}
+	assert.Equal(t, map[string]interface{}{
+		"offset":  int64(5),
+		"peer":    "unix",
+		"user":    "admin",
+		"request": "42",
+	}, enc.Fields)
+	assert.NoError(t, write(logger, &LogData{Content: []byte("default")}, "", nil))
+	assert.Equal(t, zapcore.InfoLevel, logs.AllUntimed()[1].Level)
+	assert.Error(t, write(logger, &LogData{Level: "fatal"}, "", nil))
+	assert.Error(t, write(logger, &LogData{Level: "loud"}, "", nil))
+	assert.Error(t, write(logger, &LogData{Fields: map[string]string{"msg": "x"}}, "", nil))
+	assert.Len(t, logs.AllUntimed(), 2)
+	// Services write under their own name
+	for source, name := range map[string]string{"": "web", "web": "web", "kernel": "web.kernel"} {
+		assert.NoError(t, write(logger, &LogData{Source: source}, "web", nil))
+		entries := logs.AllUntimed()
+		assert.Equal(t, name, entries[len(entries)-1].LoggerName)
+	}
+}
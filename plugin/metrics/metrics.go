@@ -5,34 +5,40 @@ import (
 	"github.com/mesanine/gaffer/event"
 	"github.com/mesanine/gaffer/log"
 	"go.uber.org/zap"
+	"sync"
 )
 
 type Metrics struct {
-	err  chan error
-	stop chan bool
+	err   chan error
+	stop  chan bool
+	ready chan struct{}
+	once  sync.Once
 }
 
 func New() *Metrics {
 	return &Metrics{
-		err:  make(chan error, 1),
-		stop: make(chan bool, 1),
+		err:   make(chan error, 1),
+		stop:  make(chan bool, 1),
+		ready: make(chan struct{}),
 	}
 }
 
-func (m Metrics) Name() string { return "metrics" }
+func (m *Metrics) Name() string { return "metrics" }
 
-func (m Metrics) Configure(cfg config.Config) error { return nil }
+func (m *Metrics) Configure(cfg config.Config) error { return nil }
 
-func (m Metrics) Run(e *event.EventBus) error {
+func (m *Metrics) Run(e *event.EventBus) error {
 	sub := event.NewSubscriber()
 	e.Subscribe(sub)
+	defer e.Unsubscribe(sub)
+	m.once.Do(func() { close(m.ready) })
 	ec := sub.Chan()
 	for {
 		select {
 		case evt := <-ec:
 			if event.Is(event.SERVICE_METRICS)(evt) {
 				// TODO TODO
-				log.Log.Info("processing metric event", zap.Any("event", evt))
+				log.Named("metrics").Info("processing metric event", zap.Any("event", evt))
 			}
 		case err := <-m.err:
 			return err
@@ -42,7 +48,11 @@ func (m Metrics) Run(e *event.EventBus) error {
 	}
 }
 
-func (m Metrics) Stop() error {
+// Ready is closed once Run has
+// subscribed to the EventBus.
+func (m *Metrics) Ready() <-chan struct{} { return m.ready }
+
+func (m *Metrics) Stop() error {
 	m.stop <- true
 	return nil
 }
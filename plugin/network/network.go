@@ -0,0 +1,171 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"github.com/cenkalti/backoff"
+	"github.com/mesanine/gaffer/config"
+	"github.com/mesanine/gaffer/event"
+	"github.com/mesanine/gaffer/host"
+	"github.com/mesanine/gaffer/log"
+	netconf "github.com/mesanine/gaffer/network"
+	"go.uber.org/zap"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	// DHCPTimeout is how long to wait
+	// for each reply from a DHCP server.
+	DHCPTimeout = 5 * time.Second
+	// CheckInterval is how often the
+	// host address is checked until
+	// the network is up.
+	CheckInterval = 1 * time.Second
+)
+
+// Network leases addresses for interfaces
+// configured with DHCP and pushes NETWORK_UP
+// onto the EventBus once this host has a
+// usable address.
+type Network struct {
+	config config.Config
+	err    chan error
+	stop   chan bool
+	ready  chan struct{}
+	once   sync.Once
+}
+
+func New() *Network {
+	return &Network{
+		err:   make(chan error, 1),
+		stop:  make(chan bool, 1),
+		ready: make(chan struct{}),
+	}
+}
+
+func (n *Network) Name() string { return "network" }
+
+func (n *Network) Configure(cfg config.Config) error {
+	n.config = cfg
+	return nil
+}
+
+func (n *Network) Run(eb *event.EventBus) error {
+	sub := event.NewSubscriber()
+	eb.Subscribe(sub)
+	defer eb.Unsubscribe(sub)
+	n.once.Do(func() { close(n.ready) })
+	ec := sub.Chan()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for _, iface := range n.config.Network.Interfaces {
+		if iface.DHCP {
+			go n.lease(ctx, iface.Name)
+		}
+	}
+	ticker := time.NewTicker(CheckInterval)
+	defer ticker.Stop()
+	var self *host.Host
+	for {
+		select {
+		case err := <-n.err:
+			return err
+		case <-n.stop:
+			return nil
+		case evt, ok := <-ec:
+			if !ok {
+				ec = nil
+				continue
+			}
+			// Answer plugins which subscribed
+			// after the network came up.
+			if event.Is(event.REQUEST_NETWORK)(evt) && self != nil {
+				eb.Push(event.New(event.NETWORK_UP, event.WithHost(self)))
+			}
+		case <-ticker.C:
+			if self != nil {
+				continue
+			}
+			h, err := host.Self()
+			if err != nil {
+				continue
+			}
+			self = h
+			log.Named("network").Info(fmt.Sprintf("network is up with address %s", self.Address))
+			eb.Push(event.New(event.NETWORK_UP, event.WithHost(self)))
+		}
+	}
+}
+
+// Ready is closed once Run has
+// subscribed to the EventBus.
+func (n *Network) Ready() <-chan struct{} { return n.ready }
+
+func (n *Network) Stop() error {
+	n.stop <- true
+	return nil
+}
+
+// lease acquires a DHCP lease for the named
+// interface renewing it at half of the lease
+// duration until ctx is canceled.
+func (n *Network) lease(ctx context.Context, name string) {
+	var current *net.IPNet
+	for {
+		var lease *netconf.Lease
+		// Retry until leased
+		bo := backoff.NewExponentialBackOff()
+		bo.MaxElapsedTime = 0
+		err := backoff.RetryNotify(func() error {
+			if err := netconf.LinkUp(name); err != nil {
+				return err
+			}
+			l, err := netconf.DHCP(name, DHCPTimeout)
+			if err != nil {
+				return err
+			}
+			lease = l
+			return nil
+		}, backoff.WithContext(bo, ctx),
+			func(err error, d time.Duration) {
+				log.Named("network").Warn(fmt.Sprintf("failed to lease address for %s: %s", name, err.Error()))
+			},
+		)
+		if err != nil {
+			// Canceled
+			return
+		}
+		log.Named("network").Info(
+			fmt.Sprintf("leased address %s for %s", lease.Address.String(), name),
+			zap.Duration("duration", lease.Duration),
+		)
+		if current != nil && current.String() != lease.Address.String() {
+			if err := netconf.DelAddress(name, current); err != nil {
+				log.Named("network").Warn(fmt.Sprintf("failed to remove address %s: %s", current.String(), err.Error()))
+			}
+		}
+		if err := lease.Apply(name); err != nil {
+			select {
+			case n.err <- err:
+			case <-ctx.Done():
+			}
+			return
+		}
+		current = lease.Address
+		// Static nameservers take precedence
+		// over those leased by DHCP.
+		if len(n.config.Network.Nameservers) == 0 && len(lease.DNS) > 0 {
+			err := netconf.WriteResolvConf(n.config.Network.ResolvConf, lease.DNS, n.config.Network.Search)
+			if err != nil {
+				log.Named("network").Warn(fmt.Sprintf("failed to write resolver configuration: %s", err.Error()))
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(lease.Duration / 2):
+		}
+	}
+}
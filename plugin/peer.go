@@ -0,0 +1,51 @@
+package plugin
+
+import (
+	"net"
+	"syscall"
+)
+
+// PeerAddr is the address of a client of
+// a unix socket with the ID of its process
+// read with SO_PEERCRED when it connected.
+type PeerAddr struct {
+	net.Addr
+	PID int
+}
+
+// peerListener accepts connections whose
+// remote address is a PeerAddr if the
+// credentials of the client are known.
+type peerListener struct {
+	net.Listener
+}
+
+func (l peerListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	uc, ok := conn.(*net.UnixConn)
+	if !ok {
+		return conn, nil
+	}
+	raw, err := uc.SyscallConn()
+	if err != nil {
+		return conn, nil
+	}
+	var cred *syscall.Ucred
+	raw.Control(func(fd uintptr) {
+		cred, err = syscall.GetsockoptUcred(int(fd), syscall.SOL_SOCKET, syscall.SO_PEERCRED)
+	})
+	if err != nil {
+		return conn, nil
+	}
+	return peerConn{Conn: conn, addr: PeerAddr{Addr: conn.RemoteAddr(), PID: int(cred.Pid)}}, nil
+}
+
+type peerConn struct {
+	net.Conn
+	addr PeerAddr
+}
+
+func (c peerConn) RemoteAddr() net.Addr { return c.addr }
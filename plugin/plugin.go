@@ -5,6 +5,7 @@ import (
 	"github.com/mesanine/gaffer/config"
 	"github.com/mesanine/gaffer/event"
 	"google.golang.org/grpc"
+	"time"
 )
 
 // Plugin implements some unit of work
@@ -37,3 +38,41 @@ type RPC interface {
 type CLI interface {
 	CLI(*config.Config) cli.CmdInitializer
 }
+
+// Dependent returns the names of plugins
+// which must be configured and started
+// before this plugin and stopped after it.
+// Dependencies which are not registered
+// are ignored.
+type Dependent interface {
+	Dependencies() []string
+}
+
+// Readier returns a channel which is
+// closed by Run once the plugin is ready.
+// Plugins depending on it are not started
+// until then.
+type Readier interface {
+	Ready() <-chan struct{}
+}
+
+// Graceful returns how long the plugin
+// may take to return once it is stopped
+// if it is longer than the timeout given
+// by the registry.
+type Graceful interface {
+	StopTimeout() time.Duration
+}
+
+// Proxy forwards calls to services which
+// are not described by a grpc.ServiceDesc
+// such as those served by out of process
+// plugins.
+type Proxy interface {
+	// Proxies reports if calls to the fully
+	// qualified service are forwarded.
+	Proxies(service string) bool
+	// Forward forwards a call to a method
+	// like /package.Service/Method.
+	Forward(method string, stream grpc.ServerStream) error
+}
@@ -0,0 +1,303 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: github.com/mesanine/gaffer/plugin/plugin.proto
+
+/*
+Package plugin is a generated protocol buffer package.
+
+It is generated from these files:
+	github.com/mesanine/gaffer/plugin/plugin.proto
+
+It has these top-level messages:
+	ListRequest
+	ListResponse
+	Status
+	RestartRequest
+	RestartResponse
+*/
+package plugin
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import host "github.com/mesanine/gaffer/host"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type ListRequest struct {
+	Host *host.Host `protobuf:"bytes,1,opt,name=host" json:"host,omitempty"`
+}
+
+func (m *ListRequest) Reset()                    { *m = ListRequest{} }
+func (m *ListRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListRequest) ProtoMessage()               {}
+func (*ListRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+func (m *ListRequest) GetHost() *host.Host {
+	if m != nil {
+		return m.Host
+	}
+	return nil
+}
+
+type ListResponse struct {
+	Plugins []*Status `protobuf:"bytes,1,rep,name=plugins" json:"plugins,omitempty"`
+}
+
+func (m *ListResponse) Reset()                    { *m = ListResponse{} }
+func (m *ListResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListResponse) ProtoMessage()               {}
+func (*ListResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *ListResponse) GetPlugins() []*Status {
+	if m != nil {
+		return m.Plugins
+	}
+	return nil
+}
+
+// Status describes a plugin
+// in the registry.
+type Status struct {
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// configuring, running, failed or stopped
+	State string `protobuf:"bytes,2,opt,name=state" json:"state,omitempty"`
+	// Failure policy
+	Policy   string `protobuf:"bytes,3,opt,name=policy" json:"policy,omitempty"`
+	Restarts int32  `protobuf:"varint,4,opt,name=restarts" json:"restarts,omitempty"`
+	// Last error returned
+	Error        string   `protobuf:"bytes,5,opt,name=error" json:"error,omitempty"`
+	Dependencies []string `protobuf:"bytes,6,rep,name=dependencies" json:"dependencies,omitempty"`
+}
+
+func (m *Status) Reset()                    { *m = Status{} }
+func (m *Status) String() string            { return proto.CompactTextString(m) }
+func (*Status) ProtoMessage()               {}
+func (*Status) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *Status) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Status) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *Status) GetPolicy() string {
+	if m != nil {
+		return m.Policy
+	}
+	return ""
+}
+
+func (m *Status) GetRestarts() int32 {
+	if m != nil {
+		return m.Restarts
+	}
+	return 0
+}
+
+func (m *Status) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *Status) GetDependencies() []string {
+	if m != nil {
+		return m.Dependencies
+	}
+	return nil
+}
+
+type RestartRequest struct {
+	Host *host.Host `protobuf:"bytes,1,opt,name=host" json:"host,omitempty"`
+	Name string     `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+}
+
+func (m *RestartRequest) Reset()                    { *m = RestartRequest{} }
+func (m *RestartRequest) String() string            { return proto.CompactTextString(m) }
+func (*RestartRequest) ProtoMessage()               {}
+func (*RestartRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *RestartRequest) GetHost() *host.Host {
+	if m != nil {
+		return m.Host
+	}
+	return nil
+}
+
+func (m *RestartRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type RestartResponse struct {
+}
+
+func (m *RestartResponse) Reset()                    { *m = RestartResponse{} }
+func (m *RestartResponse) String() string            { return proto.CompactTextString(m) }
+func (*RestartResponse) ProtoMessage()               {}
+func (*RestartResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+func init() {
+	proto.RegisterType((*ListRequest)(nil), "plugin.ListRequest")
+	proto.RegisterType((*ListResponse)(nil), "plugin.ListResponse")
+	proto.RegisterType((*Status)(nil), "plugin.Status")
+	proto.RegisterType((*RestartRequest)(nil), "plugin.RestartRequest")
+	proto.RegisterType((*RestartResponse)(nil), "plugin.RestartResponse")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for RPC service
+
+type RPCClient interface {
+	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
+	Restart(ctx context.Context, in *RestartRequest, opts ...grpc.CallOption) (*RestartResponse, error)
+}
+
+type rPCClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewRPCClient(cc *grpc.ClientConn) RPCClient {
+	return &rPCClient{cc}
+}
+
+func (c *rPCClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error) {
+	out := new(ListResponse)
+	err := grpc.Invoke(ctx, "/plugin.RPC/List", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rPCClient) Restart(ctx context.Context, in *RestartRequest, opts ...grpc.CallOption) (*RestartResponse, error) {
+	out := new(RestartResponse)
+	err := grpc.Invoke(ctx, "/plugin.RPC/Restart", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for RPC service
+
+type RPCServer interface {
+	List(context.Context, *ListRequest) (*ListResponse, error)
+	Restart(context.Context, *RestartRequest) (*RestartResponse, error)
+}
+
+func RegisterRPCServer(s *grpc.Server, srv RPCServer) {
+	s.RegisterService(&_RPC_serviceDesc, srv)
+}
+
+func _RPC_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/plugin.RPC/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCServer).List(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RPC_Restart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCServer).Restart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/plugin.RPC/Restart",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCServer).Restart(ctx, req.(*RestartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _RPC_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "plugin.RPC",
+	HandlerType: (*RPCServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "List",
+			Handler:    _RPC_List_Handler,
+		},
+		{
+			MethodName: "Restart",
+			Handler:    _RPC_Restart_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "github.com/mesanine/gaffer/plugin/plugin.proto",
+}
+
+func init() { proto.RegisterFile("github.com/mesanine/gaffer/plugin/plugin.proto", fileDescriptor0) }
+
+var fileDescriptor0 = []byte{
+	// 323 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x51, 0x41, 0x4f, 0xf2, 0x40,
+	0x10, 0xfd, 0x4a, 0x4b, 0xf9, 0x18, 0x08, 0xc6, 0x95, 0xe0, 0xa6, 0x07, 0xd3, 0xec, 0xa9, 0x31,
+	0xb1, 0x24, 0x70, 0xf1, 0xe0, 0x4d, 0x0f, 0x1e, 0x3c, 0x98, 0xf5, 0x17, 0x14, 0x18, 0xa0, 0x09,
+	0xec, 0xd6, 0x9d, 0x6d, 0xa2, 0xff, 0xc6, 0x9f, 0x6a, 0xd8, 0x6d, 0x51, 0x62, 0x62, 0xbc, 0xb4,
+	0x7d, 0x6f, 0xe6, 0xbd, 0x99, 0x79, 0x85, 0x7c, 0x53, 0xda, 0x6d, 0xbd, 0xc8, 0x97, 0x7a, 0x3f,
+	0xdd, 0x23, 0x15, 0xaa, 0x54, 0x38, 0xdd, 0x14, 0xeb, 0x35, 0x9a, 0x69, 0xb5, 0xab, 0x37, 0xa5,
+	0x6a, 0x5e, 0x79, 0x65, 0xb4, 0xd5, 0x2c, 0xf6, 0x28, 0xb9, 0xfe, 0x45, 0xb7, 0xd5, 0x64, 0xdd,
+	0xc3, 0x6b, 0xc4, 0x0d, 0x0c, 0x9e, 0x4a, 0xb2, 0x12, 0x5f, 0x6b, 0x24, 0xcb, 0xae, 0x20, 0x3a,
+	0x14, 0x79, 0x90, 0x06, 0xd9, 0x60, 0x06, 0xb9, 0xeb, 0x7c, 0xd4, 0x64, 0xa5, 0xe3, 0xc5, 0x2d,
+	0x0c, 0x7d, 0x3b, 0x55, 0x5a, 0x11, 0xb2, 0x0c, 0x7a, 0x7e, 0x28, 0xf1, 0x20, 0x0d, 0xb3, 0xc1,
+	0x6c, 0x94, 0x37, 0x2b, 0xbd, 0xd8, 0xc2, 0xd6, 0x24, 0xdb, 0xb2, 0xf8, 0x08, 0x20, 0xf6, 0x1c,
+	0x63, 0x10, 0xa9, 0x62, 0x8f, 0x6e, 0x48, 0x5f, 0xba, 0x6f, 0x36, 0x86, 0x2e, 0xd9, 0xc2, 0x22,
+	0xef, 0x38, 0xd2, 0x03, 0x36, 0x81, 0xb8, 0xd2, 0xbb, 0x72, 0xf9, 0xce, 0x43, 0x47, 0x37, 0x88,
+	0x25, 0xf0, 0xdf, 0x20, 0xd9, 0xc2, 0x58, 0xe2, 0x51, 0x1a, 0x64, 0x5d, 0x79, 0xc4, 0x07, 0x27,
+	0x34, 0x46, 0x1b, 0xde, 0xf5, 0x4e, 0x0e, 0x30, 0x01, 0xc3, 0x15, 0x56, 0xa8, 0x56, 0xa8, 0x96,
+	0x25, 0x12, 0x8f, 0xd3, 0x30, 0xeb, 0xcb, 0x13, 0x4e, 0x3c, 0xc0, 0x48, 0x7a, 0x97, 0x3f, 0xc6,
+	0x71, 0xbc, 0xa4, 0xf3, 0x75, 0x89, 0x38, 0x87, 0xb3, 0xa3, 0x8b, 0x4f, 0x69, 0xf6, 0x06, 0xa1,
+	0x7c, 0xbe, 0x67, 0x73, 0x88, 0x0e, 0xe1, 0xb1, 0x8b, 0x36, 0xa3, 0x6f, 0xc9, 0x27, 0xe3, 0x53,
+	0xd2, 0x2b, 0xc5, 0x3f, 0x76, 0x07, 0xbd, 0xc6, 0x8e, 0x4d, 0xda, 0x96, 0xd3, 0x2d, 0x93, 0xcb,
+	0x1f, 0x7c, 0xab, 0x5e, 0xc4, 0xee, 0x2f, 0xcf, 0x3f, 0x07, 0x00, 0x6d, 0x3e, 0xee, 0xe7, 0x4b,
+	0x02, 0x00, 0x00,
+}
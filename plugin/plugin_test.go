@@ -1,12 +1,18 @@
 package plugin
 
 import (
+	"fmt"
 	"github.com/mesanine/gaffer/config"
 	"github.com/mesanine/gaffer/event"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"io/ioutil"
+	"net"
+	"os"
 	"sync"
 	"syscall"
 	"testing"
+	"time"
 )
 
 type MockPlugin struct {
@@ -43,3 +49,152 @@ func TestRegistry(t *testing.T) {
 	assert.NoError(t, reg.Handle(syscall.SIGINT))
 	wg.Wait()
 }
+
+type MockDependent struct {
+	MockPlugin
+	name string
+	deps []string
+}
+
+func (md MockDependent) Name() string           { return md.name }
+func (md MockDependent) Dependencies() []string { return md.deps }
+
+func TestRegistryOrder(t *testing.T) {
+	reg := NewRegistry()
+	assert.NoError(t, reg.Register(&MockDependent{name: "c", deps: []string{"b", "missing"}}))
+	assert.NoError(t, reg.Register(&MockDependent{name: "b", deps: []string{"a"}}))
+	assert.NoError(t, reg.Register(&MockDependent{name: "a"}))
+	order, err := reg.Order()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, order)
+	assert.NoError(t, reg.Register(&MockDependent{name: "d", deps: []string{"e"}}))
+	assert.NoError(t, reg.Register(&MockDependent{name: "e", deps: []string{"d"}}))
+	_, err = reg.Order()
+	assert.Error(t, err)
+}
+
+type MockFailing struct {
+	MockPlugin
+	runs int
+}
+
+func (mf MockFailing) Name() string { return "failing" }
+
+func (mf *MockFailing) Run(eb *event.EventBus) error {
+	mf.runs++
+	if mf.runs == 1 {
+		return fmt.Errorf("failed")
+	}
+	return mf.MockPlugin.Run(eb)
+}
+
+func TestRegistryRestart(t *testing.T) {
+	reg := NewRegistry()
+	assert.NoError(t, reg.Register(&MockFailing{}))
+	assert.NoError(t, reg.Configure(config.Config{}))
+	done := make(chan error)
+	go func() { done <- reg.Run() }()
+	var status *Status
+	for i := 0; i < 50; i++ {
+		resp, err := reg.List(context.Background(), &ListRequest{})
+		assert.NoError(t, err)
+		status = resp.Plugins[0]
+		if status.State == string(Running) && status.Restarts == 1 {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	assert.Equal(t, string(Running), status.State)
+	assert.Equal(t, int32(1), status.Restarts)
+	assert.Equal(t, "failed", status.Error)
+	assert.NoError(t, reg.Shutdown(time.Second))
+	assert.NoError(t, <-done)
+}
+
+type MockSubscriber struct {
+	MockPlugin
+	subscribed chan struct{}
+	received   chan struct{}
+}
+
+func (ms MockSubscriber) Name() string { return "subscriber" }
+
+func (ms *MockSubscriber) Run(eb *event.EventBus) error {
+	sub := event.NewSubscriber()
+	eb.Subscribe(sub)
+	defer eb.Unsubscribe(sub)
+	ms.subscribed <- struct{}{}
+	for {
+		select {
+		case <-sub.Chan():
+			ms.received <- struct{}{}
+		case <-ms.stop:
+			return nil
+		}
+	}
+}
+
+func TestRegistryRestartSubscriber(t *testing.T) {
+	reg := NewRegistry()
+	ms := &MockSubscriber{
+		subscribed: make(chan struct{}, 1),
+		received:   make(chan struct{}, event.BufferSize*3),
+	}
+	assert.NoError(t, reg.Register(ms))
+	assert.NoError(t, reg.Configure(config.Config{}))
+	done := make(chan error, 1)
+	go func() { done <- reg.Run() }()
+	<-ms.subscribed
+	for i := 0; i < 2; i++ {
+		_, err := reg.Restart(context.Background(), &RestartRequest{Name: "subscriber"})
+		assert.NoError(t, err)
+		<-ms.subscribed
+	}
+	// Restarting the only plugin
+	// does not end Run.
+	select {
+	case err := <-done:
+		t.Fatalf("registry returned after restart: %v", err)
+	default:
+	}
+	pushed := make(chan struct{})
+	go func() {
+		for i := 0; i < event.BufferSize*3; i++ {
+			reg.eventbus.Push(event.New(event.SERVICE_STARTED))
+		}
+		close(pushed)
+	}()
+	timeout := time.After(5 * time.Second)
+	for i := 0; i < event.BufferSize*3; i++ {
+		select {
+		case <-ms.received:
+		case <-timeout:
+			t.Fatalf("received %d events, the event bus is blocked", i)
+		}
+	}
+	<-pushed
+	assert.NoError(t, reg.Shutdown(time.Second))
+	assert.NoError(t, <-done)
+	// Restarting after Run has
+	// returned does not block.
+	_, err := reg.Restart(context.Background(), &RestartRequest{Name: "subscriber"})
+	assert.Error(t, err)
+}
+
+func TestListenPeer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gaffer-peer")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	listener, err := Listen(fmt.Sprintf("unix://%s/gaffer.sock", dir))
+	assert.NoError(t, err)
+	defer listener.Close()
+	client, err := net.Dial("unix", fmt.Sprintf("%s/gaffer.sock", dir))
+	assert.NoError(t, err)
+	defer client.Close()
+	conn, err := listener.Accept()
+	assert.NoError(t, err)
+	defer conn.Close()
+	addr, ok := conn.RemoteAddr().(PeerAddr)
+	assert.True(t, ok)
+	assert.Equal(t, os.Getpid(), addr.PID)
+}
@@ -1,31 +1,66 @@
 package register
 
 import (
+	"context"
 	"fmt"
 	"github.com/cenkalti/backoff"
 	"github.com/mesanine/gaffer/client"
 	"github.com/mesanine/gaffer/config"
 	"github.com/mesanine/gaffer/event"
+	"github.com/mesanine/gaffer/host"
 	"github.com/mesanine/gaffer/log"
+	"github.com/mesanine/gaffer/version"
+	"net/url"
+	"strconv"
+	"sync"
 	"time"
 )
 
 const RegistrationInterval = 25 * time.Second
 
+// state is the state of a service.
+type state int
+
+const (
+	running state = iota
+	// exited services are restarted
+	// after exiting on their own.
+	exited
+	// stopped services were killed
+	// deliberately by the supervisor.
+	stopped
+)
+
 type Server struct {
 	err    chan error
 	stop   chan bool
 	config config.Config
+	mu     sync.Mutex
+	// services tracks the
+	// state of each service.
+	services map[string]state
+	// leader is true while this
+	// host is the cluster leader.
+	leader bool
+	// network is closed once this
+	// host has a usable address.
+	network chan struct{}
+	up      bool
+	ready   chan struct{}
+	once    sync.Once
 }
 
 func New() *Server {
 	return &Server{
-		err:  make(chan error, 1),
-		stop: make(chan bool, 1),
+		err:      make(chan error, 1),
+		stop:     make(chan bool, 1),
+		services: map[string]state{},
+		network:  make(chan struct{}),
+		ready:    make(chan struct{}),
 	}
 }
 
-func (s Server) Name() string { return "register" }
+func (s *Server) Name() string { return "register" }
 
 func (s *Server) Configure(cfg config.Config) error {
 	s.config = cfg
@@ -33,33 +68,44 @@ func (s *Server) Configure(cfg config.Config) error {
 }
 
 func (s *Server) Run(eb *event.EventBus) error {
+	sub := event.NewSubscriber()
+	eb.Subscribe(sub)
+	defer eb.Unsubscribe(sub)
+	s.once.Do(func() { close(s.ready) })
+	go s.track(sub)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if s.networked() {
+		// Ask the network plugin in case
+		// it came up before we subscribed.
+		eb.Push(event.New(event.REQUEST_NETWORK))
+	} else {
+		s.networkUp()
+	}
+	go s.members(ctx, eb)
+	go s.lead(ctx, eb)
 	go func() {
-		s.err <- backoff.RetryNotify(func() error {
-			var cli *client.Client
-			defer func() {
-				if cli != nil {
-					cli.Close()
-				}
-			}()
-			for {
-				if cli == nil {
-					c, err := client.New(s.config)
-					if err != nil {
-						return err
-					}
-					cli = c
-				}
-				err := cli.Register()
-				if err != nil {
-					return err
-				}
-				time.Sleep(RegistrationInterval)
-			}
-		}, backoff.NewConstantBackOff(1*time.Second),
+		select {
+		case <-s.network:
+		case <-ctx.Done():
+			return
+		}
+		err := backoff.RetryNotify(func() error {
+			return s.register(ctx)
+		}, backoff.WithContext(backoff.NewConstantBackOff(1*time.Second), ctx),
 			func(err error, d time.Duration) {
-				log.Log.Warn(fmt.Sprintf("failed to register with etcd: %s", err.Error()))
+				log.Named("register").Warn(fmt.Sprintf("failed to register with etcd: %s", err.Error()))
 			},
 		)
+		// Errors are not reported
+		// once Run has returned.
+		if ctx.Err() != nil {
+			return
+		}
+		select {
+		case s.err <- err:
+		case <-ctx.Done():
+		}
 	}()
 	select {
 	case err := <-s.err:
@@ -69,7 +115,196 @@ func (s *Server) Run(eb *event.EventBus) error {
 	}
 }
 
+// Dependencies implements plugin.Dependent,
+// registration waits for the network.
+func (s *Server) Dependencies() []string { return []string{"network"} }
+
+// Ready is closed once Run has
+// subscribed to the EventBus.
+func (s *Server) Ready() <-chan struct{} { return s.ready }
+
 func (s *Server) Stop() error {
 	s.stop <- true
 	return nil
 }
+
+// track tracks service states
+// from events on the EventBus.
+func (s *Server) track(sub event.Subscriber) {
+	for {
+		evt := sub.Next()
+		if evt == nil {
+			return
+		}
+		switch {
+		case event.Is(event.SERVICE_STARTED)(*evt):
+			s.mu.Lock()
+			s.services[evt.Id] = running
+			s.mu.Unlock()
+		case event.Is(event.SERVICE_EXITED)(*evt):
+			s.mu.Lock()
+			s.services[evt.Id] = exited
+			s.mu.Unlock()
+		case event.Is(event.SERVICE_STOPPED)(*evt):
+			s.mu.Lock()
+			s.services[evt.Id] = stopped
+			s.mu.Unlock()
+		case event.Is(event.NETWORK_UP)(*evt):
+			s.networkUp()
+		}
+	}
+}
+
+// networked checks if the network plugin
+// is enabled in which case registration
+// waits until it pushes NETWORK_UP.
+func (s *Server) networked() bool {
+	for _, name := range s.config.Plugins() {
+		if name == "network" {
+			return true
+		}
+	}
+	return false
+}
+
+// networkUp signals that this
+// host has a usable address.
+func (s *Server) networkUp() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.up {
+		s.up = true
+		close(s.network)
+	}
+}
+
+// members pushes host membership changes
+// onto the EventBus until ctx is canceled.
+func (s *Server) members(ctx context.Context, eb *event.EventBus) {
+	backoff.RetryNotify(func() error {
+		cli, err := client.New(s.config)
+		if err != nil {
+			return err
+		}
+		defer cli.Close()
+		return cli.Watch(ctx, 0, eb.Push)
+	}, backoff.WithContext(backoff.NewConstantBackOff(1*time.Second), ctx),
+		func(err error, d time.Duration) {
+			log.Named("register").Warn(fmt.Sprintf("failed to watch hosts in etcd: %s", err.Error()))
+		},
+	)
+}
+
+// lead campaigns for cluster leadership pushing
+// LEADER_ELECTED and LEADER_LOST events onto the
+// EventBus until ctx is canceled.
+func (s *Server) lead(ctx context.Context, eb *event.EventBus) {
+	backoff.RetryNotify(func() error {
+		self, err := host.Self()
+		if err != nil {
+			return err
+		}
+		cli, err := client.New(s.config)
+		if err != nil {
+			return err
+		}
+		defer cli.Close()
+		lost, err := cli.Elect(ctx, self.Name)
+		if err != nil {
+			return err
+		}
+		s.mu.Lock()
+		s.leader = true
+		s.mu.Unlock()
+		eb.Push(event.New(event.LEADER_ELECTED, event.WithHost(self)))
+		<-lost
+		s.mu.Lock()
+		s.leader = false
+		s.mu.Unlock()
+		eb.Push(event.New(event.LEADER_LOST, event.WithHost(self)))
+		return fmt.Errorf("lost cluster leadership")
+	}, backoff.WithContext(backoff.NewConstantBackOff(1*time.Second), ctx),
+		func(err error, d time.Duration) {
+			log.Named("register").Warn(fmt.Sprintf("cluster election failed: %s", err.Error()))
+		},
+	)
+}
+
+// register registers this host every
+// RegistrationInterval until ctx is canceled.
+func (s *Server) register(ctx context.Context) error {
+	cli, err := client.New(s.config)
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+	for {
+		self, err := s.self()
+		if err != nil {
+			return err
+		}
+		if err := cli.Register(self); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(RegistrationInterval):
+		}
+	}
+}
+
+// self describes this host
+// for registration in etcd.
+func (s *Server) self() (*host.Host, error) {
+	self, err := host.Self()
+	if err != nil {
+		return nil, err
+	}
+	bootTime, err := host.BootTime()
+	if err != nil {
+		return nil, err
+	}
+	self.Port = port(s.config.Address)
+	self.Version = version.Version
+	self.GitSha = version.GitSHA
+	self.BootTime = bootTime
+	self.Labels = s.config.Labels
+	self.Services = s.count()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	self.Leader = s.leader
+	return self, nil
+}
+
+// count counts the services in each state. Only
+// services which exited on their own have failed.
+func (s *Server) count() *host.Services {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	services := &host.Services{}
+	for _, st := range s.services {
+		services.Total++
+		switch st {
+		case running:
+			services.Running++
+		case exited:
+			services.Failed++
+		}
+	}
+	return services
+}
+
+// port returns the port of a tcp://
+// RPC address or zero otherwise.
+func port(address string) int32 {
+	u, err := url.Parse(address)
+	if err != nil || u.Scheme != "tcp" {
+		return 0
+	}
+	p, err := strconv.Atoi(u.Port())
+	if err != nil {
+		return 0
+	}
+	return int32(p)
+}
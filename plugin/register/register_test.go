@@ -0,0 +1,31 @@
+package register
+
+import (
+	"github.com/mesanine/gaffer/host"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPort(t *testing.T) {
+	for address, expected := range map[string]int32{
+		"tcp://0.0.0.0:10000":         10000,
+		"tcp://[::1]:9000":            9000,
+		"tcp://0.0.0.0":               0,
+		"unix:///var/run/gaffer.sock": 0,
+		"":                            0,
+		"%gh&%ij":                     0,
+	} {
+		assert.Equal(t, expected, port(address), address)
+	}
+}
+
+func TestCount(t *testing.T) {
+	s := New()
+	s.services = map[string]state{
+		"web":       running,
+		"db":        exited,
+		"singleton": stopped,
+	}
+	// The stopped singleton has not failed
+	assert.Equal(t, &host.Services{Total: 3, Running: 1, Failed: 1}, s.count())
+}
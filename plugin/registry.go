@@ -2,11 +2,59 @@ package plugin
 
 import (
 	"fmt"
+	"github.com/cenkalti/backoff"
 	"github.com/mesanine/gaffer/config"
 	"github.com/mesanine/gaffer/event"
+	"github.com/mesanine/gaffer/fatal"
+	"github.com/mesanine/gaffer/host"
+	"github.com/mesanine/gaffer/liveness"
 	"github.com/mesanine/gaffer/log"
 	"github.com/mesanine/ginit"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
 	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// ReadyTimeout is how long the registry waits
+	// for a plugin to become ready before starting
+	// the plugins which depend on it.
+	ReadyTimeout = 30 * time.Second
+	// StopTimeout is how long plugins are given
+	// to return when the registry handles a
+	// terminal signal.
+	StopTimeout = 60 * time.Second
+	// RestartReset is how long a plugin must run
+	// before its restart backoff is reset.
+	RestartReset = 1 * time.Minute
+)
+
+// Failure policies applied when
+// a plugin returns an error.
+const (
+	// Restart runs the plugin again
+	// with an exponential backoff.
+	Restart = "restart"
+	// Ignore leaves the plugin failed.
+	Ignore = "ignore"
+	// Fatal calls fatal.Fatal and returns
+	// the error from Registry.Run.
+	Fatal = "fatal"
+)
+
+// State is the lifecycle
+// state of a plugin.
+type State string
+
+const (
+	Configuring = State("configuring")
+	Running     = State("running")
+	Failed      = State("failed")
+	Stopped     = State("stopped")
 )
 
 type shutdown struct {
@@ -14,23 +62,73 @@ type shutdown struct {
 	Err  error
 }
 
+// entry tracks a registered plugin.
+type entry struct {
+	plugin   Plugin
+	state    State
+	policy   string
+	err      error
+	restarts int32
+	// active is true while the plugin
+	// goroutine is running or restarting.
+	active bool
+	// restarting is true while the
+	// Restart RPC stops and starts
+	// the plugin.
+	restarting bool
+	// stopping is true if the
+	// plugin was asked to stop.
+	stopping bool
+	// exited is closed when the
+	// plugin goroutine returns.
+	exited chan struct{}
+	// wake interrupts a restart backoff.
+	wake chan struct{}
+}
+
 // Registry stores a collection of
 // plugins each with a unique name.
 type Registry struct {
 	eventbus *event.EventBus
+	mu       sync.Mutex
 	plugins  map[string]Plugin
+	entries  map[string]*entry
+	// exits receives a message each
+	// time a plugin goroutine returns.
+	exits chan shutdown
+	// done is closed when Run returns
+	// after which exits are not sent.
+	done chan struct{}
+	// closing is true once the registry
+	// is shutting down and no more
+	// plugins may be started.
+	closing bool
 }
 
 func NewRegistry() *Registry {
 	return &Registry{
 		eventbus: event.NewEventBus(),
 		plugins:  map[string]Plugin{},
+		entries:  map[string]*entry{},
+		exits:    make(chan shutdown),
+		done:     make(chan struct{}),
+	}
+}
+
+// Names returns the names of
+// all registered plugins.
+func (r *Registry) Names() []string {
+	names := []string{}
+	for name := range r.plugins {
+		names = append(names, name)
 	}
+	sort.Strings(names)
+	return names
 }
 
 // Plugin returns the plugin with the
 // given id if it exists.
-func (r Registry) Plugin(id string) (Plugin, error) {
+func (r *Registry) Plugin(id string) (Plugin, error) {
 	p, ok := r.plugins[id]
 	if !ok {
 		return nil, fmt.Errorf("no plugin named %s is available", id)
@@ -40,19 +138,97 @@ func (r Registry) Plugin(id string) (Plugin, error) {
 
 // Registry registers a Plugin within
 // the registry.
-func (r Registry) Register(p Plugin) error {
+func (r *Registry) Register(p Plugin) error {
 	if _, ok := r.plugins[p.Name()]; ok {
 		return fmt.Errorf("plugin with name %s is already registered", p.Name())
 	}
 	r.plugins[p.Name()] = p
+	exited := make(chan struct{})
+	close(exited)
+	r.entries[p.Name()] = &entry{
+		plugin: p,
+		state:  Stopped,
+		policy: Restart,
+		exited: exited,
+	}
 	return nil
 }
 
-// Configure configures all of the underlying plugins.
-func (r Registry) Configure(cfg config.Config) error {
-	for name, plugin := range r.plugins {
+// Order returns the names of the registered
+// plugins sorted so each plugin follows its
+// dependencies.
+func (r *Registry) Order() ([]string, error) {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := map[string]int{}
+	order := []string{}
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case visiting:
+			return fmt.Errorf("plugin dependency cycle: %s", strings.Join(append(path, name), " -> "))
+		case visited:
+			return nil
+		}
+		state[name] = visiting
+		if dependent, ok := r.plugins[name].(Dependent); ok {
+			for _, dep := range dependent.Dependencies() {
+				if _, ok := r.plugins[dep]; !ok {
+					continue
+				}
+				if err := visit(dep, append(path, name)); err != nil {
+					return err
+				}
+			}
+		}
+		state[name] = visited
+		order = append(order, name)
+		return nil
+	}
+	for _, name := range r.Names() {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// Configure configures all of the underlying
+// plugins in dependency order.
+func (r *Registry) Configure(cfg config.Config) error {
+	order, err := r.Order()
+	if err != nil {
+		return err
+	}
+	for _, name := range order {
+		policy := cfg.FailurePolicy
+		if p, ok := cfg.FailurePolicies[name]; ok {
+			policy = p
+		}
+		switch policy {
+		case "":
+			policy = Restart
+		case Restart, Ignore, Fatal:
+		default:
+			return fmt.Errorf("plugin %s has unknown failure policy %s", name, policy)
+		}
+		r.mu.Lock()
+		r.entries[name].policy = policy
+		r.entries[name].state = Configuring
+		r.mu.Unlock()
 		log.Log.Info(fmt.Sprintf("configuring plugin %s", name))
-		if err := plugin.Configure(cfg); err != nil {
+		err := r.plugins[name].Configure(cfg)
+		r.mu.Lock()
+		if err != nil {
+			r.entries[name].state = Failed
+			r.entries[name].err = err
+		} else {
+			r.entries[name].state = Stopped
+		}
+		r.mu.Unlock()
+		if err != nil {
 			return err
 		}
 	}
@@ -60,52 +236,330 @@ func (r Registry) Configure(cfg config.Config) error {
 }
 
 // Handle implements the ginit.Handler interface.
-func (r Registry) Handle(sig os.Signal) error {
+func (r *Registry) Handle(sig os.Signal) error {
 	if ginit.Terminal(sig) {
-		for name, plugin := range r.plugins {
-			log.Log.Info(fmt.Sprintf("shutting down plugin %s", name))
-			err := plugin.Stop()
-			if err != nil {
-				return err
-			}
-			log.Log.Info(fmt.Sprintf("shutdown plugin %s", name))
+		return r.Shutdown(StopTimeout)
+	}
+	return nil
+}
+
+// Shutdown stops every plugin in reverse
+// dependency order waiting up to timeout for
+// each to return. No plugins can be started
+// afterwards. Errors are logged and the last
+// one is returned.
+func (r *Registry) Shutdown(timeout time.Duration) error {
+	order, err := r.Order()
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	r.closing = true
+	r.mu.Unlock()
+	var failed error
+	for i := len(order) - 1; i >= 0; i-- {
+		if err := r.Stop(order[i], timeout); err != nil {
+			log.Log.Error(fmt.Sprintf("failed to shutdown plugin %s: %s", order[i], err.Error()))
+			failed = err
+		}
+	}
+	return failed
+}
+
+// Stop stops the named plugin and waits up to
+// timeout for it to return or longer if the
+// plugin is Graceful.
+func (r *Registry) Stop(name string, timeout time.Duration) error {
+	plugin, err := r.Plugin(name)
+	if err != nil {
+		return err
+	}
+	if g, ok := plugin.(Graceful); ok && g.StopTimeout() > timeout {
+		timeout = g.StopTimeout()
+	}
+	r.mu.Lock()
+	e := r.entries[name]
+	running := e.active && e.state == Running
+	if e.active {
+		e.stopping = true
+		// Interrupt any restart backoff
+		select {
+		case e.wake <- struct{}{}:
+		default:
 		}
 	}
+	exited := e.exited
+	r.mu.Unlock()
+	log.Log.Info(fmt.Sprintf("shutting down plugin %s", name))
+	if running {
+		if err := plugin.Stop(); err != nil {
+			return err
+		}
+	}
+	select {
+	case <-exited:
+	case <-time.After(timeout):
+		return fmt.Errorf("plugin %s did not shutdown after %s", name, timeout)
+	}
+	log.Log.Info(fmt.Sprintf("shutdown plugin %s", name))
 	return nil
 }
 
-// Run runs a registry of plugins each
-// in a separate Go routine. It waits until
-// all plugins have returned. If any plugin
-// returns an error the function returns
-// immediately.
-func (r Registry) Run() error {
-	shutdownCh := make(chan shutdown)
-	// Launch each plugin in the registry
-	for name, plugin := range r.plugins {
-		log.Log.Info(fmt.Sprintf("launching plugin %s", name))
-		go func(plugin Plugin) {
-			err := plugin.Run(r.eventbus)
-			shutdownCh <- shutdown{
-				Name: plugin.Name(),
-				Err:  err,
+// start launches the plugin in a separate Go
+// routine applying its failure policy each time
+// it returns an error. A message is sent on exits
+// when the Go routine returns.
+func (r *Registry) start(name string) error {
+	r.mu.Lock()
+	if r.closing {
+		r.mu.Unlock()
+		return fmt.Errorf("registry is shutting down")
+	}
+	e := r.entries[name]
+	e.active = true
+	e.stopping = false
+	e.exited = make(chan struct{})
+	e.wake = make(chan struct{}, 1)
+	exited, wake := e.exited, e.wake
+	r.mu.Unlock()
+	log.Log.Info(fmt.Sprintf("launching plugin %s", name))
+	go func() {
+		bo := backoff.NewExponentialBackOff()
+		bo.MaxElapsedTime = 0
+		for {
+			r.setState(name, Running, nil)
+			started := time.Now()
+			err := e.plugin.Run(r.eventbus)
+			r.mu.Lock()
+			stopping := e.stopping
+			policy := e.policy
+			r.mu.Unlock()
+			if err == nil || stopping {
+				r.setState(name, Stopped, err)
+				r.exit(name, exited, nil)
+				return
+			}
+			log.Log.Error(fmt.Sprintf("plugin %s encountered an error: %s", name, err.Error()))
+			r.setState(name, Failed, err)
+			switch policy {
+			case Ignore:
+				r.exit(name, exited, nil)
+				return
+			case Fatal:
+				fatal.Fatal()
+				r.exit(name, exited, err)
+				return
 			}
-		}(plugin)
+			if time.Since(started) > RestartReset {
+				bo.Reset()
+			}
+			delay := bo.NextBackOff()
+			log.Log.Warn(fmt.Sprintf("restarting plugin %s in %s", name, delay))
+			select {
+			case <-wake:
+			case <-time.After(delay):
+			}
+			r.mu.Lock()
+			stopping = e.stopping
+			if !stopping {
+				e.restarts++
+			}
+			r.mu.Unlock()
+			if stopping {
+				r.exit(name, exited, nil)
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func (r *Registry) exit(name string, exited chan struct{}, err error) {
+	r.mu.Lock()
+	r.entries[name].active = false
+	r.mu.Unlock()
+	close(exited)
+	select {
+	case r.exits <- shutdown{Name: name, Err: err}:
+	case <-r.done:
+	}
+}
+
+func (r *Registry) setState(name string, state State, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[name].state = state
+	if err != nil {
+		r.entries[name].err = err
 	}
-	// Wait until we recieve the same number
-	// of errors or nil as there are registered
-	// plugins
-	for i := 0; i < len(r.plugins); i++ {
-		msg := <-shutdownCh
+}
+
+// active counts plugin Go routines which have
+// not yet returned or are being restarted.
+func (r *Registry) active() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var count int
+	for _, e := range r.entries {
+		if e.active || e.restarting {
+			count++
+		}
+	}
+	return count
+}
+
+// Run runs a registry of plugins each in a
+// separate Go routine in dependency order. If a
+// plugin implements Readier the plugins after
+// it are not started until it is ready. Plugins
+// returning an error are handled according to
+// their failure policy. Run waits until all
+// plugins have returned or returns immediately
+// if a plugin with the fatal policy fails.
+func (r *Registry) Run() error {
+	order, err := r.Order()
+	if err != nil {
+		return err
+	}
+	r.eventbus.Start()
+	defer r.eventbus.Stop()
+	// Plugins cannot be restarted
+	// once Run has returned.
+	defer func() {
+		r.mu.Lock()
+		r.closing = true
+		r.mu.Unlock()
+		close(r.done)
+	}()
+	ticker := time.NewTicker(liveness.Interval)
+	defer ticker.Stop()
+	defer liveness.Done("registry")
+	next := func(msg shutdown) error {
 		if msg.Err != nil {
-			log.Log.Error(fmt.Sprintf("plugin %s encountered an error: %s", msg.Name, msg.Err.Error()))
-			// Give up immediately when we encounter
-			// a plugin error
+			// Give up immediately when a
+			// fatal plugin fails.
 			return msg.Err
 		}
 		log.Log.Info(fmt.Sprintf("plugin %s has shutdown", msg.Name))
+		return nil
+	}
+	// Launch each plugin in the registry
+	for _, name := range order {
+		if err := r.start(name); err != nil {
+			break
+		}
+		readier, ok := r.plugins[name].(Readier)
+		if !ok {
+			continue
+		}
+		r.mu.Lock()
+		exited := r.entries[name].exited
+		r.mu.Unlock()
+		timeout := time.After(ReadyTimeout)
+	ready:
+		for {
+			liveness.Beat("registry")
+			select {
+			case <-readier.Ready():
+				log.Log.Info(fmt.Sprintf("plugin %s is ready", name))
+				break ready
+			case <-exited:
+				// Returned without becoming ready
+				break ready
+			case <-timeout:
+				log.Log.Warn(fmt.Sprintf("plugin %s was not ready after %s", name, ReadyTimeout))
+				break ready
+			case <-ticker.C:
+			case msg := <-r.exits:
+				if err := next(msg); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	// Wait until every plugin has returned
+	for r.active() > 0 {
+		liveness.Beat("registry")
+		select {
+		case <-ticker.C:
+		case msg := <-r.exits:
+			if err := next(msg); err != nil {
+				return err
+			}
+		}
 	}
 	// All plugins successfully shutdown
 	log.Log.Info("all plugins have shutdown")
 	return nil
 }
+
+// RPC returns the service
+// description of the registry.
+func (r *Registry) RPC() *grpc.ServiceDesc { return &_RPC_serviceDesc }
+
+// List returns the status of each plugin.
+func (r *Registry) List(ctx context.Context, req *ListRequest) (*ListResponse, error) {
+	if err := host.Check(req.Host); err != nil {
+		return nil, err
+	}
+	resp := &ListResponse{Plugins: []*Status{}}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, name := range r.Names() {
+		e := r.entries[name]
+		status := &Status{
+			Name:         name,
+			State:        string(e.state),
+			Policy:       e.policy,
+			Restarts:     e.restarts,
+			Dependencies: []string{},
+		}
+		if e.err != nil {
+			status.Error = e.err.Error()
+		}
+		if dependent, ok := e.plugin.(Dependent); ok {
+			status.Dependencies = dependent.Dependencies()
+		}
+		resp.Plugins = append(resp.Plugins, status)
+	}
+	return resp, nil
+}
+
+// Restart stops the named plugin if it is running
+// and starts it again. Plugins which depend on it
+// are not restarted.
+func (r *Registry) Restart(ctx context.Context, req *RestartRequest) (*RestartResponse, error) {
+	if err := host.Check(req.Host); err != nil {
+		return nil, err
+	}
+	if _, err := r.Plugin(req.Name); err != nil {
+		return nil, err
+	}
+	// The plugin is counted as active
+	// until it is started again so Run
+	// does not return in between.
+	r.mu.Lock()
+	if r.closing {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("registry is shutting down")
+	}
+	e := r.entries[req.Name]
+	if e.restarting {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("plugin %s is already restarting", req.Name)
+	}
+	e.restarting = true
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		e.restarting = false
+		r.mu.Unlock()
+	}()
+	if err := r.Stop(req.Name, StopTimeout); err != nil {
+		return nil, err
+	}
+	if err := r.start(req.Name); err != nil {
+		return nil, err
+	}
+	return &RestartResponse{}, nil
+}
@@ -6,44 +6,64 @@ import (
 	"github.com/mesanine/gaffer/log"
 	"github.com/mesanine/ginit"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/transport"
 	"net"
 	"net/url"
 	"os"
+	"strings"
 )
 
 type Server struct {
 	grpc     *grpc.Server
 	listener net.Listener
+	registry *Registry
 }
 
 func NewServer(cfg config.Config) (*Server, error) {
-	server := &Server{
-		grpc: grpc.NewServer(),
+	auth, err := NewAuth(cfg)
+	if err != nil {
+		return nil, err
+	}
+	listener, err := Listen(cfg.Address)
+	if err != nil {
+		return nil, err
 	}
-	u, err := url.Parse(cfg.Address)
+	s := &Server{listener: listener}
+	s.grpc = grpc.NewServer(
+		grpc.UnaryInterceptor(auth.Unary()),
+		grpc.StreamInterceptor(auth.Stream()),
+		// Calls to services which are not
+		// registered are forwarded to the
+		// plugin proxying them, if any.
+		grpc.UnknownServiceHandler(s.forward),
+	)
+	return s, nil
+}
+
+// Listen opens a listener on a tcp://
+// or unix:// address. Clients of unix
+// sockets have a PeerAddr.
+func Listen(address string) (net.Listener, error) {
+	u, err := url.Parse(address)
 	if err != nil {
 		return nil, err
 	}
 	switch u.Scheme {
 	case "tcp":
-		listener, err := net.Listen(u.Scheme, fmt.Sprintf("%s:%s", u.Hostname(), u.Port()))
-		if err != nil {
-			return nil, err
-		}
-		server.listener = listener
+		return net.Listen(u.Scheme, fmt.Sprintf("%s:%s", u.Hostname(), u.Port()))
 	case "unix":
 		listener, err := net.Listen(u.Scheme, u.Path)
 		if err != nil {
 			return nil, err
 		}
-		server.listener = listener
-	default:
-		return nil, fmt.Errorf("bad address: %s", u)
+		return peerListener{listener}, nil
 	}
-	return server, nil
+	return nil, fmt.Errorf("bad address: %s", u)
 }
 
 func (s *Server) Run(reg *Registry) error {
+	s.registry = reg
 	for _, plugin := range reg.plugins {
 		if rpc, ok := plugin.(RPC); ok {
 			// TODO: Errors here silently os.Exit(1)
@@ -57,9 +77,30 @@ func (s *Server) Run(reg *Registry) error {
 			}
 		}
 	}
+	// The registry exposes the
+	// status of every plugin.
+	s.grpc.RegisterService(reg.RPC(), reg)
+	log.Log.Info("registered plugin registry RPC service")
 	return s.grpc.Serve(s.listener)
 }
 
+// forward forwards a call to an unknown
+// service to the plugin which proxies it.
+func (s *Server) forward(srv interface{}, stream grpc.ServerStream) error {
+	ts, ok := transport.StreamFromContext(stream.Context())
+	if !ok {
+		return grpc.Errorf(codes.Internal, "no transport stream in context")
+	}
+	method := ts.Method()
+	service := strings.SplitN(strings.TrimPrefix(method, "/"), "/", 2)[0]
+	for _, plugin := range s.registry.plugins {
+		if proxy, ok := plugin.(Proxy); ok && proxy.Proxies(service) {
+			return proxy.Forward(method, stream)
+		}
+	}
+	return grpc.Errorf(codes.Unimplemented, "unknown service %s", service)
+}
+
 // Handle implements the ginit.Handler interface.
 func (s Server) Handle(sig os.Signal) error {
 	if ginit.Terminal(sig) {
@@ -2,19 +2,21 @@ package supervisor
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/containerd/go-runc"
 	"github.com/jawher/mow.cli"
 	"github.com/mesanine/gaffer/config"
+	"github.com/mesanine/gaffer/host"
 	"github.com/mesanine/gaffer/util"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"google.golang.org/grpc"
+	"strings"
+	"time"
 )
 
-func (s Supervisor) CLI(cfg *config.Config) cli.CmdInitializer {
+func (s *Supervisor) CLI(cfg *config.Config) cli.CmdInitializer {
 	return func(cmd *cli.Cmd) {
-		var client RPCClient
-		cmd.Before = func() {
-			conn, err := util.NewClientConn(*cfg)
-			util.Maybe(err)
-			client = NewRPCClient(conn)
-		}
 		cmd.Command("restart", "Restart a service", func(cmd *cli.Cmd) {
 			cmd.Spec = "ID"
 			id := cmd.String(cli.StringArg{
@@ -22,22 +24,61 @@ func (s Supervisor) CLI(cfg *config.Config) cli.CmdInitializer {
 				Desc:  "Service ID to restart",
 				Value: "",
 			})
-			var req *RestartRequest
-			cmd.Before = func() {
-				req = &RestartRequest{Id: *id}
-			}
 			cmd.Action = func() {
-				resp, err := client.Restart(context.Background(), req, cfg.CallOpts()...)
-				util.Maybe(err)
-				util.JSONToStdout(resp)
+				util.Remote(*cfg, func(h *host.Host, conn *grpc.ClientConn) (interface{}, error) {
+					req := &RestartRequest{Id: *id, Host: h}
+					return NewRPCClient(conn).Restart(context.Background(), req, cfg.CallOpts()...)
+				})
 			}
 		})
 		cmd.Command("status", "Return the status of a service", func(cmd *cli.Cmd) {
 			cmd.Action = func() {
-				resp, err := client.Status(context.Background(), &StatusRequest{}, cfg.CallOpts()...)
-				util.Maybe(err)
-				util.JSONToStdout(resp)
+				util.Remote(*cfg, func(h *host.Host, conn *grpc.ClientConn) (interface{}, error) {
+					req := &StatusRequest{Host: h}
+					return NewRPCClient(conn).Status(context.Background(), req, cfg.CallOpts()...)
+				})
 			}
 		})
 	}
 }
+
+// Table returns the services as rows of a table
+// with the resources used by those running.
+func (r *StatusResponse) Table(wide bool) ([]string, [][]string) {
+	headers := []string{"SERVICE", "STATE", "CPU", "MEM", "PIDS"}
+	if wide {
+		headers = append(headers, "BUNDLE", "COMMAND")
+	}
+	rows := [][]string{}
+	for _, svc := range r.Services {
+		row := []string{svc.Id, "-", "-", "-", "-"}
+		if state, ok := r.States[svc.Id]; ok {
+			row[1] = state
+		}
+		stats := &runc.Stats{}
+		if len(svc.Stats) > 0 && json.Unmarshal(svc.Stats, stats) == nil {
+			row[2] = (time.Duration(stats.Cpu.Usage.Total) * time.Nanosecond).Truncate(time.Millisecond).String()
+			row[3] = memory(stats.Memory.Usage.Usage, stats.Memory.Usage.Limit)
+			row[4] = fmt.Sprintf("%d", stats.Pids.Current)
+		}
+		if wide {
+			var command string
+			spec := &specs.Spec{}
+			if json.Unmarshal(svc.Spec, spec) == nil && spec.Process != nil {
+				command = strings.Join(spec.Process.Args, " ")
+			}
+			row = append(row, svc.Bundle, command)
+		}
+		rows = append(rows, row)
+	}
+	return headers, rows
+}
+
+// Footer returns the processes reaped
+// by the init process if it is known.
+func (r *StatusResponse) Footer() []string {
+	if r.Reaper == nil {
+		return nil
+	}
+	return []string{fmt.Sprintf("reaped %d unmonitored processes, %d zombies", r.Reaper.Reaped, r.Reaper.Zombies)}
+}
@@ -16,12 +16,16 @@ type IO struct {
 }
 
 func (i *IO) Start() {
+	// Output is logged at the info level by
+	// a logger named after the service so
+	// it reaches sinks at the default level.
+	logger := log.Named(i.id)
 	logFn := func(stream string, rc io.ReadCloser) {
 		scanner := bufio.NewScanner(rc)
 		for scanner.Scan() {
 			text := scanner.Text()
 			if err := scanner.Err(); err != nil {
-				log.Log.Info(
+				logger.Info(
 					i.id,
 					zap.Error(err),
 				)
@@ -29,9 +33,9 @@ func (i *IO) Start() {
 			}
 			switch stream {
 			case "stdout":
-				log.Log.Debug(i.id, zap.String("stdout", text))
+				logger.Info(i.id, zap.String("stdout", text))
 			case "stderr":
-				log.Log.Debug(i.id, zap.String("stderr", text))
+				logger.Info(i.id, zap.String("stderr", text))
 			}
 		}
 	}
@@ -15,9 +15,9 @@ func Once(cfg config.Config, db *store.FSStore) error {
 		return err
 	}
 	for _, svc := range services {
-		log.Log.Info(fmt.Sprintf("starting on-boot service %s", svc.Id))
+		log.Named("supervisor").Info(fmt.Sprintf("starting on-boot service %s", svc.Id))
 		code, err := NewRunc(svc.Id, svc.Bundle, cfg.RuncRoot).Run()
-		log.Log.Info(fmt.Sprintf("on-boot service %s exited with code %d", svc.Id, code))
+		log.Named("supervisor").Info(fmt.Sprintf("on-boot service %s exited with code %d", svc.Id, code))
 		if code != 0 || err != nil {
 			if err == nil {
 				return fmt.Errorf("service %s returned a non-zero exit code %d", svc.Id, code)
@@ -0,0 +1,70 @@
+package supervisor
+
+import (
+	"sort"
+)
+
+// stopLevels groups services so those in each level
+// only depend on services in later levels. Services
+// are stopped one level at a time so each is stopped
+// before any of the services it depends on while
+// independent services are stopped together.
+// Dependency cycles are broken arbitrarily.
+func stopLevels(names []string, dependsOn map[string][]string) [][]string {
+	included := map[string]bool{}
+	for _, name := range names {
+		included[name] = true
+	}
+	// dependents are the services
+	// depending on each service.
+	dependents := map[string][]string{}
+	for name, deps := range dependsOn {
+		for _, dep := range deps {
+			dependents[dep] = append(dependents[dep], name)
+		}
+	}
+	levels := map[string]int{}
+	visiting := map[string]bool{}
+	// level is the number of included services
+	// on the longest chain of dependents.
+	var level func(string) int
+	level = func(name string) int {
+		if l, ok := levels[name]; ok {
+			return l
+		}
+		visiting[name] = true
+		l := 0
+		for _, dependent := range dependents[name] {
+			if visiting[dependent] {
+				continue
+			}
+			dl := level(dependent)
+			if included[dependent] {
+				dl++
+			}
+			if dl > l {
+				l = dl
+			}
+		}
+		visiting[name] = false
+		levels[name] = l
+		return l
+	}
+	grouped := [][]string{}
+	for _, name := range names {
+		l := level(name)
+		for len(grouped) <= l {
+			grouped = append(grouped, []string{})
+		}
+		grouped[l] = append(grouped[l], name)
+	}
+	order := [][]string{}
+	for _, names := range grouped {
+		if len(names) > 0 {
+			// Keep the order stable between calls
+			sort.Strings(names)
+			order = append(order, names)
+		}
+	}
+	return order
+}
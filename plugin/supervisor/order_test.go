@@ -0,0 +1,33 @@
+package supervisor
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestStopLevels(t *testing.T) {
+	dependsOn := map[string][]string{
+		"app":   []string{"db", "dns"},
+		"db":    []string{"dns"},
+		"cycle": []string{"cycle"},
+	}
+	levels := stopLevels([]string{"dns", "db", "app", "other", "cycle"}, dependsOn)
+	assert.Equal(t, [][]string{{"app", "cycle", "other"}, {"db"}, {"dns"}}, levels)
+	// Services not running are skipped
+	assert.Equal(t, [][]string{{"app"}, {"dns"}}, stopLevels([]string{"dns", "app"}, dependsOn))
+	assert.Equal(t, [][]string{{"app", "db"}}, stopLevels([]string{"db", "app"}, map[string][]string{}))
+}
+
+func TestStopTimeout(t *testing.T) {
+	s := New()
+	for _, name := range []string{"app", "db", "web"} {
+		s.runcs[name] = NewRunc(name, "", "")
+	}
+	s.config.Store.GracePeriod = 10
+	s.config.Store.GracePeriods = map[string]int{"web": 30}
+	s.config.Store.DependsOn = map[string][]string{"app": {"db"}}
+	// web and app are stopped together
+	// and db after them.
+	assert.Equal(t, 40*time.Second+2*KillTimeout, s.StopTimeout())
+}
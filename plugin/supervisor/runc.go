@@ -2,9 +2,11 @@ package supervisor
 
 import (
 	"context"
+	"fmt"
 	"github.com/containerd/go-runc"
 	"github.com/mesanine/gaffer/log"
 	"go.uber.org/zap"
+	"sync"
 	"syscall"
 	"time"
 )
@@ -15,6 +17,10 @@ type Runc struct {
 	id      string
 	io      *IO
 	started time.Time
+	mu      sync.Mutex
+	// exited is closed when
+	// the container returns.
+	exited chan struct{}
 }
 
 func (rc *Runc) Container() (*runc.Container, error) {
@@ -39,9 +45,14 @@ func (rc *Runc) Run() (int, error) {
 	if err != nil {
 		return -1, err
 	}
+	exited := make(chan struct{})
+	rc.mu.Lock()
 	rc.io = io
+	rc.exited = exited
+	rc.mu.Unlock()
 	defer func() {
 		io.Close()
+		close(exited)
 		//rc.io = nil
 	}()
 	rc.io.Start()
@@ -65,10 +76,34 @@ func (rc *Runc) Stop() error {
 	)
 }
 
+// Terminate sends SIGTERM to the container and
+// waits up to grace for it to exit before it
+// is killed.
+func (rc *Runc) Terminate(grace time.Duration) error {
+	rc.mu.Lock()
+	exited := rc.exited
+	rc.mu.Unlock()
+	// Never started
+	if exited == nil {
+		return nil
+	}
+	err := rc.rc.Kill(context.Background(), rc.id, int(syscall.SIGTERM), &runc.KillOpts{All: false})
+	if err != nil {
+		return rc.Stop()
+	}
+	select {
+	case <-exited:
+		return nil
+	case <-time.After(grace):
+		log.Named("supervisor").Warn(fmt.Sprintf("container %s did not exit after %s", rc.id, grace))
+		return rc.Stop()
+	}
+}
+
 func (rc *Runc) Running() bool {
 	container, err := rc.rc.State(context.Background(), rc.id)
 	if err != nil {
-		log.Log.Error("couldn't get container state", zap.Error(err))
+		log.Named("supervisor").Error("couldn't get container state", zap.Error(err))
 		return false
 	}
 	return container.Status == "running"
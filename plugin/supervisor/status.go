@@ -0,0 +1,168 @@
+package supervisor
+
+import (
+	"github.com/containerd/go-runc"
+	"github.com/mesanine/gaffer/host"
+	"sort"
+	"time"
+)
+
+// Service states reported by Top
+const (
+	Running = "running"
+	// Exited services are
+	// waiting to restart.
+	Exited  = "exited"
+	Stopped = "stopped"
+)
+
+// status is the runtime state and
+// latest resource usage of a service.
+type status struct {
+	state    string
+	started  time.Time
+	restarts uint32
+	exitCode int32
+	exited   time.Time
+	cpu      float64
+	stats    *runc.Stats
+	// CPU usage in nanoseconds of the previous
+	// sample and when it was collected.
+	usage   uint64
+	sampled time.Time
+}
+
+// statusOf returns the status of the
+// service, s.mu must be held.
+func (s *Supervisor) statusOf(name string) *status {
+	st, ok := s.status[name]
+	if !ok {
+		st = &status{state: Stopped}
+		s.status[name] = st
+	}
+	return st
+}
+
+// setStarted records each launch of a service
+// after the first as a restart.
+func (s *Supervisor) setStarted(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.statusOf(name)
+	if !st.started.IsZero() {
+		st.restarts++
+	}
+	st.state = Running
+	st.started = time.Now()
+	st.stats = nil
+	st.cpu, st.usage, st.sampled = 0, 0, time.Time{}
+}
+
+// stateOf returns the state of a service.
+func (s *Supervisor) stateOf(name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.statusOf(name).state
+}
+
+func (s *Supervisor) setExited(name string, code int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.statusOf(name)
+	st.state = Exited
+	st.exitCode = int32(code)
+	st.exited = time.Now()
+	st.stats = nil
+}
+
+func (s *Supervisor) setStopped(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.statusOf(name).state = Stopped
+}
+
+// sample records the stats of a running service
+// deriving CPU usage from the previous sample.
+func (s *Supervisor) sample(name string, stats *runc.Stats) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.statusOf(name)
+	if st.state != Running {
+		return
+	}
+	now := time.Now()
+	usage := stats.Cpu.Usage.Total
+	if !st.sampled.IsZero() && usage >= st.usage {
+		st.cpu = cpuPercent(usage-st.usage, now.Sub(st.sampled))
+	}
+	st.stats, st.usage, st.sampled = stats, usage, now
+}
+
+// cpuPercent returns the percent of a single CPU
+// used by usage nanoseconds of CPU time over d.
+func cpuPercent(usage uint64, d time.Duration) float64 {
+	if d <= 0 {
+		return 0
+	}
+	return float64(usage) / float64(d.Nanoseconds()) * 100
+}
+
+// top returns the state of every
+// service ordered by ID.
+func (s *Supervisor) top() *TopResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := []string{}
+	for name := range s.runcs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	resp := &TopResponse{Services: []*ServiceState{}}
+	for _, name := range names {
+		st := s.statusOf(name)
+		state := &ServiceState{
+			Id:       name,
+			State:    st.state,
+			Restarts: st.restarts,
+			ExitCode: st.exitCode,
+			Cpu:      st.cpu,
+		}
+		if !st.started.IsZero() {
+			state.Started = st.started.UnixNano()
+		}
+		if !st.exited.IsZero() {
+			state.Exited = st.exited.UnixNano()
+		}
+		if st.stats != nil {
+			state.Memory = st.stats.Memory.Usage.Usage
+			state.MemoryLimit = st.stats.Memory.Usage.Limit
+			state.Pids = st.stats.Pids.Current
+		}
+		resp.Services = append(resp.Services, state)
+	}
+	return resp
+}
+
+// Top streams the state and resource usage
+// of every service at the requested interval.
+func (s *Supervisor) Top(req *TopRequest, stream RPC_TopServer) error {
+	if err := host.Check(req.Host); err != nil {
+		return err
+	}
+	interval := StatsInterval
+	if req.Interval > 0 {
+		interval = time.Duration(req.Interval) * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := stream.Send(s.top()); err != nil {
+			return err
+		}
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
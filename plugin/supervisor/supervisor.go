@@ -2,42 +2,77 @@ package supervisor
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/cenkalti/backoff"
 	"github.com/mesanine/gaffer/config"
 	"github.com/mesanine/gaffer/event"
+	"github.com/mesanine/gaffer/fatal"
+	"github.com/mesanine/gaffer/host"
+	"github.com/mesanine/gaffer/liveness"
 	"github.com/mesanine/gaffer/log"
+	"github.com/mesanine/gaffer/reaper"
 	"github.com/mesanine/gaffer/service"
-
 	"github.com/mesanine/gaffer/store"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"sync"
 	"time"
 )
 
 const (
 	BackoffInterval = 1000 * time.Millisecond
 	StatsInterval   = 2000 * time.Millisecond
+	// KillTimeout is how long services are given
+	// to return after their grace period.
+	KillTimeout = 5 * time.Second
 )
 
 // Supervisor implements a lightweight daemon for controlling
 // containers with the runc executable.
 type Supervisor struct {
-	runcs  map[string]*Runc
-	cancel map[string]context.CancelFunc
+	runcs map[string]*Runc
+	// runs of launched services
+	// guarded by mu.
+	runs map[string]*run
+	// singletons only run while this
+	// host is the cluster leader.
+	singletons map[string]bool
+	// status of each service
+	// guarded by mu.
+	status map[string]*status
 	db     *store.FSStore
 	config config.Config
-	err    chan error
-	stop   chan bool
+	mu     sync.Mutex
+	// stopping is set by Stop once no
+	// more services may be launched.
+	stopping bool
+	err      chan error
+	stop     chan bool
+}
+
+// run is a launched service which is
+// restarted each time it exits until
+// it is canceled.
+type run struct {
+	cancel context.CancelFunc
+	// canceled is set once the service is
+	// killed and relaunch if it is launched
+	// again before the killed run exits.
+	canceled bool
+	relaunch bool
 }
 
 // New creates a new supervisor
 func New() *Supervisor {
 	return &Supervisor{
-		runcs:  map[string]*Runc{},
-		cancel: map[string]context.CancelFunc{},
-		err:    make(chan error),
-		db:     nil,
+		runcs:      map[string]*Runc{},
+		runs:       map[string]*run{},
+		singletons: map[string]bool{},
+		status:     map[string]*status{},
+		err:        make(chan error),
+		stop:       make(chan bool, 1),
+		db:         nil,
 	}
 }
 
@@ -52,34 +87,92 @@ func (s *Supervisor) Configure(cfg config.Config) error {
 	for _, svc := range services {
 		s.runcs[svc.Id] = NewRunc(svc.Id, svc.Bundle, cfg.RuncRoot)
 	}
+	for _, name := range cfg.Store.Singletons {
+		if _, ok := s.runcs[name]; !ok {
+			return fmt.Errorf("singleton service %s does not exist", name)
+		}
+		s.singletons[name] = true
+	}
 	s.config = cfg
+	fatal.Register("services.json", s.diagnostics)
 	return nil
 }
 
+// Dependencies implements plugin.Dependent, services
+// are launched after the plugins which track their
+// events and stopped before them.
+func (s *Supervisor) Dependencies() []string {
+	return []string{"logger", "metrics", "register"}
+}
+
 func (s *Supervisor) RPC() *grpc.ServiceDesc { return &_RPC_serviceDesc }
 
 func (s *Supervisor) Run(eb *event.EventBus) error {
-	// Launch all registered containers
-	s.init(eb)
+	sub := event.NewSubscriber()
+	eb.Subscribe(sub)
+	defer eb.Unsubscribe(sub)
+	ec := sub.Chan()
+	// running counts launched containers
+	// which have not yet returned.
+	var running int
+	// Launch all registered containers, singletons
+	// wait until this host is elected leader.
+	for name := range s.runcs {
+		if !s.singletons[name] && s.launch(eb, name) {
+			running++
+		}
+	}
 	ticker := time.NewTicker(900 * time.Millisecond)
-	var stopped int
-	for stopped < len(s.runcs) {
+	defer ticker.Stop()
+	defer liveness.Done("supervisor")
+	var stopping bool
+	for {
+		liveness.Beat("supervisor")
+		if stopping && running == 0 {
+			return nil
+		}
 		select {
 		case err := <-s.err:
 			if err != nil {
-				log.Log.Error("supervisor runtime error", zap.Error(err))
+				log.Named("supervisor").Error("supervisor runtime error", zap.Error(err))
 				return err
 			}
-			stopped++
+			running--
+		case <-s.stop:
+			stopping = true
+		case evt, ok := <-ec:
+			if !ok {
+				ec = nil
+				continue
+			}
+			switch {
+			case event.Is(event.LEADER_ELECTED)(evt):
+				for name := range s.singletons {
+					if s.launch(eb, name) {
+						running++
+					}
+				}
+			case event.Is(event.LEADER_LOST)(evt):
+				// Singletons are killed concurrently
+				// so the loop keeps beating while
+				// each is given its grace period.
+				for name := range s.singletons {
+					go s.kill(name)
+				}
+			}
 		case <-ticker.C:
 			// periodically publish container metrics
 			// via the eventbus
 			for name, runc := range s.runcs {
+				if !s.launched(name) {
+					continue
+				}
 				stats, err := runc.Stats()
 				if err != nil {
-					log.Log.Warn(fmt.Sprintf("could not collect stats for container %s: %s", name, err))
+					log.Named("supervisor").Warn(fmt.Sprintf("could not collect stats for container %s: %s", name, err))
 					continue
 				}
+				s.sample(name, stats)
 				eb.Push(event.New(
 					event.SERVICE_METRICS,
 					event.WithID(name),
@@ -88,52 +181,111 @@ func (s *Supervisor) Run(eb *event.EventBus) error {
 			}
 		}
 	}
-	return nil
 }
 
 func (s *Supervisor) Stop() error {
-	for name, cancelFn := range s.cancel {
-		log.Log.Warn(fmt.Sprintf("canceling runc service %s", name))
-		// Cancel each runc backoff context
-		// causing each container to not be
-		// restarted when killed.
-		cancelFn()
-		if err := s.runcs[name].Stop(); err != nil {
-			// If we can't stop a container we will log it but continue
-			// trying since the entire process is being shutdown.
-			log.Log.Error(fmt.Sprintf("failed to cancel service %s: %s", name, err.Error()))
-		} else {
-			log.Log.Warn(fmt.Sprintf("killed service %s", name))
-		}
+	s.mu.Lock()
+	// Services are not launched once stopping
+	// so every service is in the snapshot.
+	s.stopping = true
+	names := []string{}
+	for name := range s.runs {
+		names = append(names, name)
 	}
-	// Signial stop to the Run() function
+	s.mu.Unlock()
+	// Signal stop to the Run() function
+	// which returns once every service
+	// has been killed.
+	s.stop <- true
+	// Services are stopped before the services
+	// they depend on, independent services are
+	// killed together. Each may take its grace
+	// period so they are killed in the
+	// background while Run keeps beating.
+	go func() {
+		for _, level := range stopLevels(names, s.config.Store.DependsOn) {
+			var wg sync.WaitGroup
+			for _, name := range level {
+				wg.Add(1)
+				go func(name string) {
+					defer wg.Done()
+					s.kill(name)
+				}(name)
+			}
+			wg.Wait()
+		}
+	}()
 	return nil
 }
 
+// StopTimeout implements plugin.Graceful, services
+// may take the longest grace period of each level
+// they are stopped in.
+func (s *Supervisor) StopTimeout() time.Duration {
+	names := []string{}
+	for name := range s.runcs {
+		names = append(names, name)
+	}
+	var timeout time.Duration
+	for _, level := range stopLevels(names, s.config.Store.DependsOn) {
+		var longest time.Duration
+		for _, name := range level {
+			if grace := s.gracePeriod(name); grace > longest {
+				longest = grace
+			}
+		}
+		timeout += longest + KillTimeout
+	}
+	return timeout
+}
+
 func (s *Supervisor) Status(ctx context.Context, req *StatusRequest) (*StatusResponse, error) {
+	if err := host.Check(req.Host); err != nil {
+		return nil, err
+	}
 	resp := &StatusResponse{
 		Services: []*service.Service{},
+		States:   map[string]string{},
 	}
 	services, err := s.db.Services()
 	if err != nil {
 		return nil, err
 	}
 	for _, svc := range services {
-		stats, err := s.runcs[svc.Id].Stats()
-		if err != nil {
-			return nil, err
+		// Singletons are not running
+		// unless this host is leader.
+		if s.launched(svc.Id) {
+			stats, err := s.runcs[svc.Id].Stats()
+			if err != nil {
+				return nil, err
+			}
+			svc = service.WithStats(*stats)(svc)
 		}
-		svc = service.WithStats(*stats)(svc)
 		resp.Services = append(resp.Services, &svc)
+		resp.States[svc.Id] = s.stateOf(svc.Id)
+	}
+	zombies, err := reaper.Zombies()
+	if err != nil {
+		return nil, err
+	}
+	resp.Reaper = &Reaper{
+		Reaped:  reaper.Default.Reaped(),
+		Zombies: int32(zombies),
 	}
 	return resp, nil
 }
 
 func (s *Supervisor) Restart(ctx context.Context, req *RestartRequest) (*RestartResponse, error) {
+	if err := host.Check(req.Host); err != nil {
+		return nil, err
+	}
 	rc, ok := s.runcs[req.Id]
 	if !ok {
 		return nil, fmt.Errorf("no container with id %s exists", req.Id)
 	}
+	if !s.launched(req.Id) {
+		return nil, fmt.Errorf("container %s is not running on this host", req.Id)
+	}
 	// Kill the underlying runc app
 	// causing the supervisor to start it again.
 	err := rc.Stop()
@@ -143,39 +295,162 @@ func (s *Supervisor) Restart(ctx context.Context, req *RestartRequest) (*Restart
 	return &RestartResponse{}, nil
 }
 
-func (s *Supervisor) init(eb *event.EventBus) {
-	for name, rc := range s.runcs {
-		if _, ok := s.cancel[name]; ok {
-			panic(fmt.Sprintf("container %s was already registered", name))
+// launched checks if the
+// service was launched.
+func (s *Supervisor) launched(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.runs[name]
+	return ok
+}
+
+// launch runs the service restarting it each
+// time it exits until it is killed. It returns
+// false if the service was already launched or
+// the supervisor is stopping. A service which
+// was killed but has not exited yet is launched
+// again once it exits.
+func (s *Supervisor) launch(eb *event.EventBus, name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopping {
+		return false
+	}
+	if r, ok := s.runs[name]; ok {
+		if r.canceled {
+			r.relaunch = true
 		}
-		ctx, cancelFn := context.WithCancel(context.Background())
-		s.cancel[name] = cancelFn
-		go func(ctx context.Context, rc *Runc, name string) {
-			s.err <- backoff.RetryNotify(
-				func() error {
-					log.Log.Info(fmt.Sprintf("launching runc container %s", name))
-					eb.Push(
-						event.New(
-							event.SERVICE_STARTED,
-							event.WithID(name),
-						),
-					)
-					code, err := rc.Run()
-					var msg string
-					if err != nil {
-						msg = err.Error()
-					}
-					return fmt.Errorf("container %s exited with code %d: %s", name, code, msg)
-				},
-				backoff.WithContext(backoff.NewConstantBackOff(BackoffInterval), ctx),
-				func(err error, d time.Duration) {
-					eb.Push(event.New(
-						event.SERVICE_EXITED,
+		return false
+	}
+	ctx, cancelFn := context.WithCancel(context.Background())
+	s.runs[name] = &run{cancel: cancelFn}
+	go s.supervise(ctx, eb, name)
+	return true
+}
+
+// supervise restarts the service until ctx is
+// canceled and it is not relaunched. The result
+// is sent to s.err once it is no longer run.
+func (s *Supervisor) supervise(ctx context.Context, eb *event.EventBus, name string) {
+	rc := s.runcs[name]
+	for {
+		err := backoff.RetryNotify(
+			func() error {
+				log.Named("supervisor").Info(fmt.Sprintf("launching runc container %s", name))
+				eb.Push(
+					event.New(
+						event.SERVICE_STARTED,
 						event.WithID(name),
-					))
-					log.Log.Warn(err.Error(), zap.Duration("runtime", d))
-				},
-			)
-		}(ctx, rc, name)
+					),
+				)
+				s.setStarted(name)
+				code, err := rc.Run()
+				s.setExited(name, code)
+				var msg string
+				if err != nil {
+					msg = err.Error()
+				}
+				return fmt.Errorf("container %s exited with code %d: %s", name, code, msg)
+			},
+			backoff.WithContext(backoff.NewConstantBackOff(BackoffInterval), ctx),
+			func(err error, d time.Duration) {
+				eb.Push(event.New(
+					event.SERVICE_EXITED,
+					event.WithID(name),
+				))
+				log.Named("supervisor").Warn(err.Error(), zap.Duration("runtime", d))
+			},
+		)
+		s.setStopped(name)
+		eb.Push(event.New(
+			event.SERVICE_STOPPED,
+			event.WithID(name),
+		))
+		s.mu.Lock()
+		if r := s.runs[name]; r.relaunch && !s.stopping {
+			var cancelFn context.CancelFunc
+			ctx, cancelFn = context.WithCancel(context.Background())
+			s.runs[name] = &run{cancel: cancelFn}
+			s.mu.Unlock()
+			continue
+		}
+		delete(s.runs, name)
+		s.mu.Unlock()
+		// The container was killed
+		// deliberately if the context
+		// was canceled.
+		if ctx.Err() != nil {
+			err = nil
+		}
+		s.err <- err
+		return
+	}
+}
+
+// gracePeriod returns how long the service
+// is given to exit before it is killed.
+func (s *Supervisor) gracePeriod(name string) time.Duration {
+	if seconds, ok := s.config.Store.GracePeriods[name]; ok {
+		return time.Duration(seconds) * time.Second
+	}
+	return time.Duration(s.config.Store.GracePeriod) * time.Second
+}
+
+// kill stops the service
+// without restarting it.
+func (s *Supervisor) kill(name string) {
+	s.mu.Lock()
+	r, ok := s.runs[name]
+	if !ok {
+		s.mu.Unlock()
+		return
+	}
+	r.canceled, r.relaunch = true, false
+	cancelFn := r.cancel
+	s.mu.Unlock()
+	log.Named("supervisor").Warn(fmt.Sprintf("canceling runc service %s", name))
+	// Cancel the runc backoff context
+	// causing the container to not be
+	// restarted when killed.
+	cancelFn()
+	if err := s.runcs[name].Terminate(s.gracePeriod(name)); err != nil {
+		// If we can't stop a container we will log it but continue
+		// trying since the entire process may be shutting down.
+		log.Named("supervisor").Error(fmt.Sprintf("failed to cancel service %s: %s", name, err.Error()))
+	} else {
+		log.Named("supervisor").Warn(fmt.Sprintf("killed service %s", name))
+	}
+}
+
+// diagnostics describes the state of
+// every service for crash diagnostics.
+func (s *Supervisor) diagnostics() ([]byte, error) {
+	type state struct {
+		ID        string `json:"id"`
+		Bundle    string `json:"bundle"`
+		Launched  bool   `json:"launched"`
+		Singleton bool   `json:"singleton"`
+		Status    string `json:"status"`
+		Uptime    string `json:"uptime"`
+	}
+	states := []state{}
+	for name, rc := range s.runcs {
+		st := state{
+			ID:        name,
+			Bundle:    rc.bundle,
+			Launched:  s.launched(name),
+			Singleton: s.singletons[name],
+		}
+		if st.Launched {
+			st.Uptime = rc.Uptime().String()
+			container, err := rc.rc.State(context.Background(), name)
+			if err != nil {
+				st.Status = err.Error()
+			} else {
+				st.Status = container.Status
+			}
+		}
+		states = append(states, st)
 	}
+	return json.MarshalIndent(states, "", "  ")
 }
@@ -10,8 +10,12 @@ It is generated from these files:
 It has these top-level messages:
 	StatusRequest
 	StatusResponse
+	Reaper
 	RestartRequest
 	RestartResponse
+	TopRequest
+	TopResponse
+	ServiceState
 */
 package supervisor
 
@@ -55,6 +59,10 @@ func (m *StatusRequest) GetHost() *host.Host {
 
 type StatusResponse struct {
 	Services []*service.Service `protobuf:"bytes,2,rep,name=services" json:"services,omitempty"`
+	Reaper   *Reaper            `protobuf:"bytes,3,opt,name=reaper" json:"reaper,omitempty"`
+	// State of each service by ID,
+	// running, exited or stopped
+	States map[string]string `protobuf:"bytes,4,rep,name=states" json:"states,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 }
 
 func (m *StatusResponse) Reset()                    { *m = StatusResponse{} }
@@ -69,6 +77,48 @@ func (m *StatusResponse) GetServices() []*service.Service {
 	return nil
 }
 
+func (m *StatusResponse) GetReaper() *Reaper {
+	if m != nil {
+		return m.Reaper
+	}
+	return nil
+}
+
+func (m *StatusResponse) GetStates() map[string]string {
+	if m != nil {
+		return m.States
+	}
+	return nil
+}
+
+// Reaper describes child processes
+// reaped by the init process.
+type Reaper struct {
+	// Unmonitored processes reaped
+	Reaped uint64 `protobuf:"varint,1,opt,name=reaped" json:"reaped,omitempty"`
+	// Exited children not yet reaped
+	Zombies int32 `protobuf:"varint,2,opt,name=zombies" json:"zombies,omitempty"`
+}
+
+func (m *Reaper) Reset()                    { *m = Reaper{} }
+func (m *Reaper) String() string            { return proto.CompactTextString(m) }
+func (*Reaper) ProtoMessage()               {}
+func (*Reaper) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *Reaper) GetReaped() uint64 {
+	if m != nil {
+		return m.Reaped
+	}
+	return 0
+}
+
+func (m *Reaper) GetZombies() int32 {
+	if m != nil {
+		return m.Zombies
+	}
+	return 0
+}
+
 type RestartRequest struct {
 	Id   string     `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
 	Host *host.Host `protobuf:"bytes,2,opt,name=host" json:"host,omitempty"`
@@ -77,7 +127,7 @@ type RestartRequest struct {
 func (m *RestartRequest) Reset()                    { *m = RestartRequest{} }
 func (m *RestartRequest) String() string            { return proto.CompactTextString(m) }
 func (*RestartRequest) ProtoMessage()               {}
-func (*RestartRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+func (*RestartRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
 
 func (m *RestartRequest) GetId() string {
 	if m != nil {
@@ -99,13 +149,157 @@ type RestartResponse struct {
 func (m *RestartResponse) Reset()                    { *m = RestartResponse{} }
 func (m *RestartResponse) String() string            { return proto.CompactTextString(m) }
 func (*RestartResponse) ProtoMessage()               {}
-func (*RestartResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+func (*RestartResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+type TopRequest struct {
+	Host *host.Host `protobuf:"bytes,1,opt,name=host" json:"host,omitempty"`
+	// Milliseconds between updates
+	Interval int64 `protobuf:"varint,2,opt,name=interval" json:"interval,omitempty"`
+}
+
+func (m *TopRequest) Reset()                    { *m = TopRequest{} }
+func (m *TopRequest) String() string            { return proto.CompactTextString(m) }
+func (*TopRequest) ProtoMessage()               {}
+func (*TopRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *TopRequest) GetHost() *host.Host {
+	if m != nil {
+		return m.Host
+	}
+	return nil
+}
+
+func (m *TopRequest) GetInterval() int64 {
+	if m != nil {
+		return m.Interval
+	}
+	return 0
+}
+
+type TopResponse struct {
+	Services []*ServiceState `protobuf:"bytes,1,rep,name=services" json:"services,omitempty"`
+}
+
+func (m *TopResponse) Reset()                    { *m = TopResponse{} }
+func (m *TopResponse) String() string            { return proto.CompactTextString(m) }
+func (*TopResponse) ProtoMessage()               {}
+func (*TopResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+func (m *TopResponse) GetServices() []*ServiceState {
+	if m != nil {
+		return m.Services
+	}
+	return nil
+}
+
+// ServiceState is the runtime state
+// and resource usage of a service.
+type ServiceState struct {
+	Id string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	// running, exited or stopped
+	State string `protobuf:"bytes,2,opt,name=state" json:"state,omitempty"`
+	// Unix time in nanoseconds the
+	// service last started
+	Started int64 `protobuf:"varint,3,opt,name=started" json:"started,omitempty"`
+	// Times the service was restarted
+	Restarts uint32 `protobuf:"varint,4,opt,name=restarts" json:"restarts,omitempty"`
+	// Exit code of the last run and the Unix
+	// time in nanoseconds it exited, zero if
+	// the service has not exited
+	ExitCode int32 `protobuf:"varint,5,opt,name=exit_code,json=exitCode" json:"exit_code,omitempty"`
+	Exited   int64 `protobuf:"varint,6,opt,name=exited" json:"exited,omitempty"`
+	// Percent of a single CPU used
+	Cpu float64 `protobuf:"fixed64,7,opt,name=cpu" json:"cpu,omitempty"`
+	// Memory usage and limit in bytes
+	Memory      uint64 `protobuf:"varint,8,opt,name=memory" json:"memory,omitempty"`
+	MemoryLimit uint64 `protobuf:"varint,9,opt,name=memory_limit,json=memoryLimit" json:"memory_limit,omitempty"`
+	Pids        uint64 `protobuf:"varint,10,opt,name=pids" json:"pids,omitempty"`
+}
+
+func (m *ServiceState) Reset()                    { *m = ServiceState{} }
+func (m *ServiceState) String() string            { return proto.CompactTextString(m) }
+func (*ServiceState) ProtoMessage()               {}
+func (*ServiceState) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+func (m *ServiceState) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *ServiceState) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *ServiceState) GetStarted() int64 {
+	if m != nil {
+		return m.Started
+	}
+	return 0
+}
+
+func (m *ServiceState) GetRestarts() uint32 {
+	if m != nil {
+		return m.Restarts
+	}
+	return 0
+}
+
+func (m *ServiceState) GetExitCode() int32 {
+	if m != nil {
+		return m.ExitCode
+	}
+	return 0
+}
+
+func (m *ServiceState) GetExited() int64 {
+	if m != nil {
+		return m.Exited
+	}
+	return 0
+}
+
+func (m *ServiceState) GetCpu() float64 {
+	if m != nil {
+		return m.Cpu
+	}
+	return 0
+}
+
+func (m *ServiceState) GetMemory() uint64 {
+	if m != nil {
+		return m.Memory
+	}
+	return 0
+}
+
+func (m *ServiceState) GetMemoryLimit() uint64 {
+	if m != nil {
+		return m.MemoryLimit
+	}
+	return 0
+}
+
+func (m *ServiceState) GetPids() uint64 {
+	if m != nil {
+		return m.Pids
+	}
+	return 0
+}
 
 func init() {
 	proto.RegisterType((*StatusRequest)(nil), "supervisor.StatusRequest")
 	proto.RegisterType((*StatusResponse)(nil), "supervisor.StatusResponse")
+	proto.RegisterType((*Reaper)(nil), "supervisor.Reaper")
 	proto.RegisterType((*RestartRequest)(nil), "supervisor.RestartRequest")
 	proto.RegisterType((*RestartResponse)(nil), "supervisor.RestartResponse")
+	proto.RegisterType((*TopRequest)(nil), "supervisor.TopRequest")
+	proto.RegisterType((*TopResponse)(nil), "supervisor.TopResponse")
+	proto.RegisterType((*ServiceState)(nil), "supervisor.ServiceState")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -121,6 +315,7 @@ const _ = grpc.SupportPackageIsVersion4
 type RPCClient interface {
 	Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusResponse, error)
 	Restart(ctx context.Context, in *RestartRequest, opts ...grpc.CallOption) (*RestartResponse, error)
+	Top(ctx context.Context, in *TopRequest, opts ...grpc.CallOption) (RPC_TopClient, error)
 }
 
 type rPCClient struct {
@@ -149,11 +344,44 @@ func (c *rPCClient) Restart(ctx context.Context, in *RestartRequest, opts ...grp
 	return out, nil
 }
 
+func (c *rPCClient) Top(ctx context.Context, in *TopRequest, opts ...grpc.CallOption) (RPC_TopClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_RPC_serviceDesc.Streams[0], c.cc, "/supervisor.RPC/Top", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &rPCTopClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type RPC_TopClient interface {
+	Recv() (*TopResponse, error)
+	grpc.ClientStream
+}
+
+type rPCTopClient struct {
+	grpc.ClientStream
+}
+
+func (x *rPCTopClient) Recv() (*TopResponse, error) {
+	m := new(TopResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // Server API for RPC service
 
 type RPCServer interface {
 	Status(context.Context, *StatusRequest) (*StatusResponse, error)
 	Restart(context.Context, *RestartRequest) (*RestartResponse, error)
+	Top(*TopRequest, RPC_TopServer) error
 }
 
 func RegisterRPCServer(s *grpc.Server, srv RPCServer) {
@@ -196,6 +424,27 @@ func _RPC_Restart_Handler(srv interface{}, ctx context.Context, dec func(interfa
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RPC_Top_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TopRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RPCServer).Top(m, &rPCTopServer{stream})
+}
+
+type RPC_TopServer interface {
+	Send(*TopResponse) error
+	grpc.ServerStream
+}
+
+type rPCTopServer struct {
+	grpc.ServerStream
+}
+
+func (x *rPCTopServer) Send(m *TopResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _RPC_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "supervisor.RPC",
 	HandlerType: (*RPCServer)(nil),
@@ -209,7 +458,13 @@ var _RPC_serviceDesc = grpc.ServiceDesc{
 			Handler:    _RPC_Restart_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Top",
+			Handler:       _RPC_Top_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "github.com/mesanine/gaffer/plugin/supervisor/supervisor.proto",
 }
 
@@ -218,22 +473,41 @@ func init() {
 }
 
 var fileDescriptor0 = []byte{
-	// 270 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x90, 0xc1, 0x4e, 0xc3, 0x30,
-	0x0c, 0x86, 0x69, 0x87, 0x06, 0x78, 0xa2, 0x40, 0x4e, 0x23, 0x48, 0x68, 0xea, 0x69, 0x42, 0x28,
-	0x41, 0xe5, 0x0c, 0x02, 0xc1, 0x81, 0x23, 0xca, 0x9e, 0xa0, 0xdb, 0xb2, 0x2e, 0x12, 0x6b, 0x4a,
-	0x9c, 0xf0, 0x18, 0x3c, 0x33, 0x5a, 0xb2, 0x94, 0x55, 0xda, 0x76, 0xa9, 0x2b, 0xdb, 0xff, 0xf7,
-	0xff, 0x31, 0x3c, 0x55, 0xca, 0x2e, 0xdd, 0x94, 0xcd, 0xf4, 0x8a, 0xaf, 0x24, 0x96, 0xb5, 0xaa,
-	0x25, 0xaf, 0xca, 0xc5, 0x42, 0x1a, 0xde, 0x7c, 0xb9, 0x4a, 0xd5, 0x1c, 0x5d, 0x23, 0xcd, 0x8f,
-	0x42, 0x6d, 0xb6, 0x7e, 0x59, 0x63, 0xb4, 0xd5, 0x04, 0xfe, 0x3b, 0xf4, 0xee, 0x00, 0x6a, 0xa9,
-	0xd1, 0xfa, 0x4f, 0xd0, 0xd1, 0x87, 0x03, 0xbb, 0xb8, 0x06, 0xce, 0x64, 0xac, 0x41, 0x91, 0x73,
-	0x38, 0x9f, 0xd8, 0xd2, 0x3a, 0x14, 0xf2, 0xdb, 0x49, 0xb4, 0xe4, 0x16, 0x8e, 0xd7, 0xc0, 0x61,
-	0x32, 0x4a, 0xc6, 0x83, 0x02, 0x98, 0xa7, 0x7f, 0x68, 0xb4, 0xc2, 0xf7, 0xf3, 0x67, 0xc8, 0xa2,
-	0x00, 0x1b, 0x5d, 0xa3, 0x24, 0xf7, 0x70, 0xba, 0x61, 0xe2, 0x30, 0x1d, 0xf5, 0xc6, 0x83, 0xe2,
-	0x92, 0x45, 0x93, 0x49, 0xa8, 0xa2, 0xdd, 0xc8, 0x5f, 0x20, 0x13, 0x12, 0x6d, 0x69, 0x6c, 0x74,
-	0xcc, 0x20, 0x55, 0x73, 0xef, 0x77, 0x26, 0x52, 0x35, 0x6f, 0x13, 0xa4, 0x7b, 0x12, 0x5c, 0xc1,
-	0x45, 0x4b, 0x08, 0x11, 0x8a, 0xdf, 0x04, 0x7a, 0xe2, 0xf3, 0x8d, 0xbc, 0x42, 0x3f, 0x84, 0x23,
-	0xd7, 0x6c, 0xeb, 0xa8, 0x9d, 0x17, 0x52, 0xba, 0x6b, 0x14, 0x40, 0xf9, 0x11, 0x79, 0x87, 0x93,
-	0x0d, 0x9d, 0x74, 0x16, 0xbb, 0xa1, 0xe9, 0xcd, 0xce, 0x59, 0xa4, 0x4c, 0xfb, 0xfe, 0xba, 0x8f,
-	0x7f, 0x01, 0x00, 0x00, 0xff, 0xff, 0x66, 0xac, 0x54, 0x5b, 0x08, 0x02, 0x00, 0x00,
+	// 561 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x54, 0xcd, 0x6e, 0xd3, 0x40,
+	0x10, 0xc6, 0x76, 0xe2, 0x26, 0x93, 0x36, 0x94, 0x55, 0x55, 0x16, 0x57, 0x42, 0xc1, 0x07, 0x14,
+	0x55, 0x28, 0xa9, 0x02, 0x07, 0x88, 0x04, 0x02, 0x05, 0xa4, 0x1e, 0x38, 0xa0, 0x6d, 0xef, 0x95,
+	0x13, 0x6f, 0xd3, 0x15, 0xb1, 0xd7, 0x78, 0xd7, 0x11, 0xe1, 0xc4, 0xf3, 0xf1, 0x1e, 0xbc, 0x07,
+	0xda, 0x59, 0xdb, 0x71, 0xd4, 0x1f, 0x71, 0xa9, 0xe7, 0xf7, 0xdb, 0x99, 0xef, 0x9b, 0x06, 0xde,
+	0x2f, 0x85, 0xbe, 0x29, 0xe6, 0xa3, 0x85, 0x4c, 0xc6, 0x09, 0x57, 0x51, 0x2a, 0x52, 0x3e, 0x5e,
+	0x46, 0xd7, 0xd7, 0x3c, 0x1f, 0x67, 0xab, 0x62, 0x29, 0xd2, 0xb1, 0x2a, 0x32, 0x9e, 0xaf, 0x85,
+	0x92, 0x79, 0xc3, 0x1c, 0x65, 0xb9, 0xd4, 0x92, 0xc0, 0x36, 0x12, 0x9c, 0x3e, 0x00, 0x75, 0x23,
+	0x95, 0xc6, 0x3f, 0xb6, 0x2f, 0x38, 0x7b, 0xa0, 0x56, 0x19, 0xc0, 0x05, 0xaf, 0xbe, 0xb6, 0x23,
+	0x1c, 0xc3, 0xc1, 0x85, 0x8e, 0x74, 0xa1, 0x18, 0xff, 0x51, 0x70, 0xa5, 0xc9, 0x73, 0x68, 0x19,
+	0x40, 0xea, 0x0c, 0x9c, 0x61, 0x6f, 0x02, 0x23, 0x44, 0x3f, 0x97, 0x4a, 0x33, 0x8c, 0x87, 0x7f,
+	0x1d, 0xe8, 0x57, 0x1d, 0x2a, 0x93, 0xa9, 0xe2, 0xe4, 0x15, 0x74, 0x4a, 0x50, 0x45, 0xdd, 0x81,
+	0x37, 0xec, 0x4d, 0x0e, 0x47, 0xd5, 0x2b, 0x17, 0xf6, 0xcb, 0xea, 0x0a, 0x72, 0x0a, 0x7e, 0xce,
+	0xa3, 0x8c, 0xe7, 0xd4, 0xc3, 0x27, 0xc8, 0xa8, 0xb1, 0x3e, 0xc3, 0x0c, 0x2b, 0x2b, 0xc8, 0x07,
+	0xf0, 0x95, 0x8e, 0x34, 0x57, 0xb4, 0x85, 0xb8, 0x2f, 0x9b, 0xb5, 0xbb, 0x53, 0xa0, 0xcb, 0xd5,
+	0x97, 0x54, 0xe7, 0x1b, 0x56, 0x76, 0x05, 0xef, 0xa0, 0xd7, 0x08, 0x93, 0x43, 0xf0, 0xbe, 0xf3,
+	0x0d, 0xae, 0xd6, 0x65, 0xc6, 0x24, 0x47, 0xd0, 0x5e, 0x47, 0xab, 0x82, 0x53, 0x17, 0x63, 0xd6,
+	0x99, 0xba, 0x6f, 0x9d, 0x70, 0x0a, 0xbe, 0x1d, 0x86, 0x1c, 0x97, 0x03, 0xc7, 0xd8, 0xd8, 0x2a,
+	0x87, 0x8b, 0x09, 0x85, 0xbd, 0x5f, 0x32, 0x99, 0x0b, 0xdc, 0xda, 0x19, 0xb6, 0x59, 0xe5, 0x86,
+	0x1f, 0xa1, 0xcf, 0xb8, 0xd2, 0x51, 0xae, 0x2b, 0x56, 0xfb, 0xe0, 0x8a, 0xb8, 0x7c, 0xd8, 0x15,
+	0x71, 0xcd, 0xb2, 0x7b, 0x0f, 0xcb, 0x4f, 0xe0, 0x71, 0x8d, 0x60, 0xf7, 0x0b, 0xcf, 0x01, 0x2e,
+	0x65, 0xf6, 0x9f, 0x32, 0x91, 0x00, 0x3a, 0x22, 0xd5, 0x3c, 0x5f, 0x47, 0x2b, 0x7c, 0xc4, 0x63,
+	0xb5, 0x1f, 0xce, 0xa0, 0x87, 0x48, 0xa5, 0x7c, 0x6f, 0x1a, 0xf2, 0x39, 0x48, 0x33, 0xdd, 0xa1,
+	0xd9, 0xe6, 0x90, 0xc7, 0xad, 0x8c, 0xe1, 0x6f, 0x17, 0xf6, 0x9b, 0xa9, 0x5b, 0x2b, 0x1e, 0x41,
+	0x1b, 0x55, 0xa8, 0xa8, 0x45, 0xc7, 0x90, 0x86, 0x6b, 0xf1, 0x18, 0xe5, 0xf7, 0x58, 0xe5, 0x9a,
+	0x89, 0x73, 0xbb, 0xb2, 0x51, 0xdb, 0x19, 0x1e, 0xb0, 0xda, 0x27, 0x27, 0xd0, 0xe5, 0x3f, 0x85,
+	0xbe, 0x5a, 0xc8, 0x98, 0xd3, 0x36, 0x92, 0xdd, 0x31, 0x81, 0x99, 0x8c, 0xb9, 0xd1, 0xc7, 0xd8,
+	0x3c, 0xa6, 0x3e, 0x22, 0x96, 0x9e, 0x51, 0x7b, 0x91, 0x15, 0x74, 0x6f, 0xe0, 0x0c, 0x1d, 0x66,
+	0x4c, 0x53, 0x99, 0xf0, 0x44, 0xe6, 0x1b, 0xda, 0xb1, 0x4a, 0x5a, 0x8f, 0xbc, 0x80, 0x7d, 0x6b,
+	0x5d, 0xad, 0x44, 0x22, 0x34, 0xed, 0x62, 0xb6, 0x67, 0x63, 0x5f, 0x4d, 0x88, 0x10, 0x68, 0x65,
+	0x22, 0x56, 0x14, 0x30, 0x85, 0xf6, 0xe4, 0x8f, 0x03, 0x1e, 0xfb, 0x36, 0x23, 0x9f, 0xc0, 0xb7,
+	0xb7, 0x48, 0x9e, 0xdd, 0x75, 0x9f, 0x28, 0x58, 0x10, 0xdc, 0x7f, 0xba, 0xe1, 0x23, 0xf2, 0x19,
+	0xf6, 0x4a, 0xbd, 0x49, 0xb0, 0xfb, 0xff, 0xd0, 0x3c, 0xa3, 0xe0, 0xe4, 0xce, 0x5c, 0x8d, 0x32,
+	0x05, 0xef, 0x52, 0x66, 0xe4, 0xb8, 0x59, 0xb5, 0xbd, 0x99, 0xe0, 0xe9, 0xad, 0x78, 0xd5, 0x79,
+	0xe6, 0xcc, 0x7d, 0xfc, 0x3d, 0x78, 0xfd, 0x6f, 0x00, 0x13, 0xc1, 0x8c, 0xa2, 0xba, 0x04, 0x00,
+	0x00,
 }
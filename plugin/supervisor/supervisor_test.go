@@ -0,0 +1,28 @@
+package supervisor
+
+import (
+	"github.com/mesanine/gaffer/event"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLaunch(t *testing.T) {
+	s := New()
+	eb := event.NewEventBus()
+	s.runcs["web"] = NewRunc("web", "", "")
+	// Launched services are not launched again
+	s.runs["web"] = &run{cancel: func() {}}
+	assert.False(t, s.launch(eb, "web"))
+	assert.False(t, s.runs["web"].relaunch)
+	// Killed services are launched
+	// again once they exit
+	s.kill("web")
+	assert.True(t, s.runs["web"].canceled)
+	assert.False(t, s.launch(eb, "web"))
+	assert.True(t, s.runs["web"].relaunch)
+	// Nothing is launched once stopping
+	delete(s.runs, "web")
+	assert.NoError(t, s.Stop())
+	assert.False(t, s.launch(eb, "web"))
+	assert.Empty(t, s.runs)
+}
@@ -0,0 +1,388 @@
+package supervisor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jawher/mow.cli"
+	"github.com/mesanine/gaffer/config"
+	"github.com/mesanine/gaffer/host"
+	"github.com/mesanine/gaffer/util"
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"text/tabwriter"
+	"time"
+)
+
+// Columns the services shown by top may be sorted by,
+// pressing s while top is running selects the next.
+var columns = []string{"service", "host", "state", "uptime", "restarts", "cpu", "mem", "pids", "exit"}
+
+// descending columns are sorted
+// from the highest value first.
+var descending = map[string]bool{
+	"uptime":   true,
+	"restarts": true,
+	"cpu":      true,
+	"mem":      true,
+	"pids":     true,
+}
+
+// row is a service of a host.
+type row struct {
+	host  string
+	state *ServiceState
+}
+
+// view holds the latest
+// services of each host.
+type view struct {
+	mu      sync.Mutex
+	hosts   map[string][]*ServiceState
+	errors  map[string]string
+	sortBy  string
+	reverse bool
+	// multi shows the host of each
+	// service if several are called.
+	multi bool
+}
+
+func newView(sortBy string, reverse, multi bool) *view {
+	return &view{
+		hosts:   map[string][]*ServiceState{},
+		errors:  map[string]string{},
+		sortBy:  sortBy,
+		reverse: reverse,
+		multi:   multi,
+	}
+}
+
+func (v *view) update(name string, resp *TopResponse) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.hosts[name] = resp.Services
+	delete(v.errors, name)
+}
+
+func (v *view) fail(name string, err error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.errors[name] = err.Error()
+}
+
+// next sorts by the following column.
+func (v *view) next() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for i, column := range columns {
+		if column == v.sortBy {
+			v.sortBy = columns[(i+1)%len(columns)]
+			break
+		}
+	}
+	if v.sortBy == "host" && !v.multi {
+		v.sortBy = columns[2]
+	}
+}
+
+func (v *view) toggle() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.reverse = !v.reverse
+}
+
+// rows returns the services of every host in the
+// sort order, v.mu must be held.
+func (v *view) rows(now time.Time) []row {
+	rows := []row{}
+	for name, services := range v.hosts {
+		for _, state := range services {
+			rows = append(rows, row{host: name, state: state})
+		}
+	}
+	// Ties are ordered by host and service
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].host != rows[j].host {
+			return rows[i].host < rows[j].host
+		}
+		return rows[i].state.Id < rows[j].state.Id
+	})
+	desc := descending[v.sortBy] != v.reverse
+	sort.SliceStable(rows, func(i, j int) bool {
+		if desc {
+			return less(v.sortBy, rows[j], rows[i], now)
+		}
+		return less(v.sortBy, rows[i], rows[j], now)
+	})
+	return rows
+}
+
+func less(column string, a, b row, now time.Time) bool {
+	switch column {
+	case "host":
+		return a.host < b.host
+	case "state":
+		return a.state.State < b.state.State
+	case "uptime":
+		return uptime(a.state, now) < uptime(b.state, now)
+	case "restarts":
+		return a.state.Restarts < b.state.Restarts
+	case "cpu":
+		return a.state.Cpu < b.state.Cpu
+	case "mem":
+		return a.state.Memory < b.state.Memory
+	case "pids":
+		return a.state.Pids < b.state.Pids
+	case "exit":
+		return a.state.ExitCode < b.state.ExitCode
+	}
+	return a.state.Id < b.state.Id
+}
+
+// uptime returns how long a running
+// service has been running for.
+func uptime(state *ServiceState, now time.Time) time.Duration {
+	if state.State != Running || state.Started == 0 {
+		return 0
+	}
+	return now.Sub(time.Unix(0, state.Started))
+}
+
+// render draws the table of services
+// followed by any errors of each host.
+func (v *view) render(w io.Writer, now time.Time) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	order := "ascending"
+	if descending[v.sortBy] != v.reverse {
+		order = "descending"
+	}
+	fmt.Fprintf(w, "gaffer top - %s - sorted by %s %s (s: sort, r: reverse, q: quit)\n\n", now.Format("15:04:05"), v.sortBy, order)
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	headers := []string{"SERVICE", "STATE", "UPTIME", "RESTARTS", "CPU%", "MEM", "PIDS", "EXIT"}
+	if v.multi {
+		headers = append([]string{"HOST"}, headers...)
+	}
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	for _, r := range v.rows(now) {
+		fields := []string{
+			r.state.Id,
+			r.state.State,
+			"-",
+			fmt.Sprintf("%d", r.state.Restarts),
+			"-",
+			"-",
+			"-",
+			"-",
+		}
+		if r.state.State == Running {
+			fields[2] = uptime(r.state, now).Truncate(time.Second).String()
+			fields[4] = fmt.Sprintf("%.1f", r.state.Cpu)
+			fields[5] = memory(r.state.Memory, r.state.MemoryLimit)
+			fields[6] = fmt.Sprintf("%d", r.state.Pids)
+		}
+		if r.state.Exited != 0 {
+			fields[7] = fmt.Sprintf("%d", r.state.ExitCode)
+		}
+		if v.multi {
+			fields = append([]string{r.host}, fields...)
+		}
+		fmt.Fprintln(tw, strings.Join(fields, "\t"))
+	}
+	tw.Flush()
+	names := []string{}
+	for name := range v.errors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "\n%s: %s", name, v.errors[name])
+	}
+	if len(names) > 0 {
+		fmt.Fprintln(w)
+	}
+}
+
+// memory formats memory usage and the limit
+// unless the service is effectively unlimited.
+func memory(usage, limit uint64) string {
+	if limit == 0 || limit >= 1<<62 {
+		return bytesize(usage)
+	}
+	return fmt.Sprintf("%s/%s", bytesize(usage), bytesize(limit))
+}
+
+func bytesize(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%c", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// rawInput switches the terminal of stdin so each
+// key press is read immediately without being
+// echoed. It returns a function restoring the
+// terminal or an error if stdin is not one.
+func rawInput() (func(), error) {
+	fd := int(os.Stdin.Fd())
+	old, err := unix.IoctlGetTermios(fd, unix.TCGETS)
+	if err != nil {
+		return nil, err
+	}
+	raw := *old
+	raw.Lflag &^= unix.ICANON | unix.ECHO
+	raw.Cc[unix.VMIN] = 1
+	raw.Cc[unix.VTIME] = 0
+	if err := unix.IoctlSetTermios(fd, unix.TCSETS, &raw); err != nil {
+		return nil, err
+	}
+	return func() { unix.IoctlSetTermios(fd, unix.TCSETS, old) }, nil
+}
+
+// TopCMD shows a continuously updated table of the
+// services of the configured address or of every
+// selected host.
+func TopCMD(cfg *config.Config) cli.CmdInitializer {
+	return func(cmd *cli.Cmd) {
+		var (
+			interval = cmd.IntOpt("i interval", 2, "seconds between updates")
+			sortBy   = cmd.StringOpt("s sort", "service", fmt.Sprintf("column to sort by: %s", strings.Join(columns, ", ")))
+			reverse  = cmd.BoolOpt("r reverse", false, "reverse the sort order")
+		)
+		cmd.Spec = "[OPTIONS]"
+		cmd.Before = func() {
+			for _, column := range columns {
+				if column == *sortBy {
+					return
+				}
+			}
+			util.Maybe(fmt.Errorf("unknown column %s", *sortBy))
+		}
+		cmd.Action = func() {
+			v := newView(*sortBy, *reverse, cfg.Remote.Selected())
+			updates := make(chan struct{}, 1)
+			notify := func() {
+				select {
+				case updates <- struct{}{}:
+				default:
+				}
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			call := func(h *host.Host, conn *grpc.ClientConn) (interface{}, error) {
+				name := cfg.Address
+				if h != nil {
+					name = h.Name
+				}
+				err := watch(ctx, cfg, h, conn, *interval, func(resp *TopResponse) {
+					v.update(name, resp)
+					notify()
+				})
+				if err != nil && ctx.Err() == nil {
+					v.fail(name, err)
+					notify()
+				}
+				return nil, err
+			}
+			if cfg.Remote.Selected() {
+				hosts, err := util.Targets(*cfg)
+				util.Maybe(err)
+				go func() {
+					for _, result := range util.FanOut(*cfg, hosts, call) {
+						if result.Error != "" && ctx.Err() == nil {
+							v.fail(result.Host.Name, errors.New(result.Error))
+						}
+					}
+					notify()
+				}()
+			} else {
+				go func() {
+					conn, err := util.NewClientConn(*cfg)
+					if err != nil {
+						v.fail(cfg.Address, err)
+						notify()
+						return
+					}
+					defer conn.Close()
+					call(nil, conn)
+				}()
+			}
+			keys := make(chan byte)
+			if restore, err := rawInput(); err == nil {
+				defer restore()
+				go func() {
+					reader := bufio.NewReader(os.Stdin)
+					for {
+						key, err := reader.ReadByte()
+						if err != nil {
+							return
+						}
+						keys <- key
+					}
+				}()
+			}
+			signals := make(chan os.Signal, 1)
+			signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+			defer signal.Stop(signals)
+			// Uptime is redrawn
+			// between updates.
+			ticker := time.NewTicker(time.Second)
+			defer ticker.Stop()
+			for {
+				buf := bytes.NewBuffer(nil)
+				// Clear the screen
+				buf.WriteString("\033[H\033[2J")
+				v.render(buf, time.Now())
+				os.Stdout.Write(buf.Bytes())
+				select {
+				case <-updates:
+				case <-ticker.C:
+				case <-signals:
+					return
+				case key := <-keys:
+					switch key {
+					case 'q':
+						return
+					case 's':
+						v.next()
+					case 'r':
+						v.toggle()
+					}
+				}
+			}
+		}
+	}
+}
+
+// watch calls fn with each update of
+// the services of a host until ctx is
+// canceled or the stream fails.
+func watch(ctx context.Context, cfg *config.Config, h *host.Host, conn *grpc.ClientConn, interval int, fn func(*TopResponse)) error {
+	req := &TopRequest{Host: h, Interval: int64(interval) * 1000}
+	stream, err := NewRPCClient(conn).Top(ctx, req, cfg.CallOpts()...)
+	if err != nil {
+		return err
+	}
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		fn(resp)
+	}
+}
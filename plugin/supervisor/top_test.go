@@ -0,0 +1,87 @@
+package supervisor
+
+import (
+	"bytes"
+	"github.com/containerd/go-runc"
+	"github.com/mesanine/gaffer/config"
+	"github.com/mesanine/gaffer/service"
+	"github.com/mesanine/gaffer/util"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStatus(t *testing.T) {
+	s := New()
+	s.runcs["web"] = NewRunc("web", "", "")
+	s.runcs["db"] = NewRunc("db", "", "")
+	s.setStarted("web")
+	s.setExited("web", 2)
+	s.setStarted("web")
+	stats := &runc.Stats{}
+	stats.Cpu.Usage.Total = 1000
+	stats.Memory.Usage.Usage = 2048
+	stats.Pids.Current = 3
+	s.sample("web", stats)
+	resp := s.top()
+	assert.Len(t, resp.Services, 2)
+	db, web := resp.Services[0], resp.Services[1]
+	assert.Equal(t, "db", db.Id)
+	assert.Equal(t, Stopped, db.State)
+	assert.Equal(t, Running, web.State)
+	assert.Equal(t, uint32(1), web.Restarts)
+	assert.Equal(t, int32(2), web.ExitCode)
+	assert.NotZero(t, web.Exited)
+	assert.Equal(t, uint64(2048), web.Memory)
+	assert.Equal(t, uint64(3), web.Pids)
+	assert.Equal(t, 50.0, cpuPercent(uint64(time.Second/2), time.Second))
+}
+
+func TestView(t *testing.T) {
+	now := time.Now()
+	v := newView("cpu", false, true)
+	v.update("a", &TopResponse{Services: []*ServiceState{
+		{Id: "web", State: Running, Cpu: 10, Started: now.Add(-time.Minute).UnixNano()},
+		{Id: "db", State: Exited, ExitCode: 1, Exited: now.UnixNano()},
+	}})
+	v.update("b", &TopResponse{Services: []*ServiceState{
+		{Id: "web", State: Running, Cpu: 50, Memory: 3 << 20, MemoryLimit: 1 << 30},
+	}})
+	ids := func() []string {
+		ids := []string{}
+		for _, r := range v.rows(now) {
+			ids = append(ids, r.host+"/"+r.state.Id)
+		}
+		return ids
+	}
+	assert.Equal(t, []string{"b/web", "a/web", "a/db"}, ids())
+	v.toggle()
+	assert.Equal(t, []string{"a/db", "a/web", "b/web"}, ids())
+	v.next()
+	assert.Equal(t, "mem", v.sortBy)
+	buf := bytes.NewBuffer(nil)
+	v.fail("c", assert.AnError)
+	v.render(buf, now)
+	out := buf.String()
+	assert.Contains(t, out, "1m0s")
+	assert.Contains(t, out, "3.0M/1.0G")
+	assert.Contains(t, out, "c: "+assert.AnError.Error())
+	lines := strings.Split(out, "\n")
+	assert.True(t, strings.HasPrefix(lines[2], "HOST"))
+}
+
+func TestStatusTable(t *testing.T) {
+	resp := &StatusResponse{
+		Services: []*service.Service{{Id: "web"}, {Id: "db"}},
+		States:   map[string]string{"web": Running},
+		Reaper:   &Reaper{Reaped: 3, Zombies: 1},
+	}
+	buf := bytes.NewBuffer(nil)
+	assert.NoError(t, util.Format(buf, config.Output{Format: util.Table}, resp))
+	assert.Equal(t, `SERVICE  STATE    CPU  MEM  PIDS
+web      running  -    -    -
+db       -        -    -    -
+reaped 3 unmonitored processes, 1 zombies
+`, buf.String())
+}
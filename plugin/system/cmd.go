@@ -0,0 +1,67 @@
+package system
+
+import (
+	"context"
+	"github.com/jawher/mow.cli"
+	"github.com/mesanine/gaffer/config"
+	"github.com/mesanine/gaffer/host"
+	"github.com/mesanine/gaffer/util"
+	"google.golang.org/grpc"
+	"strconv"
+)
+
+func (s *System) CLI(cfg *config.Config) cli.CmdInitializer {
+	return func(cmd *cli.Cmd) {
+		cmd.Command("cgroups", "List cgroup controllers", func(cmd *cli.Cmd) {
+			cmd.Action = func() {
+				util.Remote(*cfg, func(h *host.Host, conn *grpc.ClientConn) (interface{}, error) {
+					req := &CgroupsRequest{Host: h}
+					return NewRPCClient(conn).Cgroups(context.Background(), req, cfg.CallOpts()...)
+				})
+			}
+		})
+		shutdowns := []struct {
+			name string
+			desc string
+			call func(RPCClient, *ShutdownRequest) (*ShutdownResponse, error)
+		}{
+			{"reboot", "Reboot the system", func(c RPCClient, req *ShutdownRequest) (*ShutdownResponse, error) {
+				return c.Reboot(context.Background(), req, cfg.CallOpts()...)
+			}},
+			{"poweroff", "Power off the system", func(c RPCClient, req *ShutdownRequest) (*ShutdownResponse, error) {
+				return c.Poweroff(context.Background(), req, cfg.CallOpts()...)
+			}},
+			{"halt", "Halt the system", func(c RPCClient, req *ShutdownRequest) (*ShutdownResponse, error) {
+				return c.Halt(context.Background(), req, cfg.CallOpts()...)
+			}},
+		}
+		for _, shutdown := range shutdowns {
+			call := shutdown.call
+			cmd.Command(shutdown.name, shutdown.desc, func(cmd *cli.Cmd) {
+				cmd.Action = func() {
+					util.Remote(*cfg, func(h *host.Host, conn *grpc.ClientConn) (interface{}, error) {
+						return call(NewRPCClient(conn), &ShutdownRequest{Host: h})
+					})
+				}
+			})
+		}
+	}
+}
+
+// Table returns the cgroup controllers
+// as rows of a table.
+func (r *CgroupsResponse) Table(wide bool) ([]string, [][]string) {
+	headers := []string{"NAME", "VERSION", "ENABLED", "CGROUPS", "PATH"}
+	if wide {
+		headers = append(headers, "HIERARCHY", "SKIPPED")
+	}
+	rows := [][]string{}
+	for _, c := range r.Controllers {
+		row := []string{c.Name, c.Version, strconv.FormatBool(c.Enabled), strconv.Itoa(int(c.NumCgroups)), c.Path}
+		if wide {
+			row = append(row, strconv.Itoa(int(c.Hierarchy)), strconv.FormatBool(c.Skipped))
+		}
+		rows = append(rows, row)
+	}
+	return headers, rows
+}
@@ -0,0 +1,123 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"github.com/mesanine/gaffer/boot"
+	"github.com/mesanine/gaffer/config"
+	"github.com/mesanine/gaffer/event"
+	"github.com/mesanine/gaffer/host"
+	"github.com/mesanine/gaffer/log"
+	"github.com/mesanine/ginit"
+	"google.golang.org/grpc"
+	"os"
+	"syscall"
+	"time"
+)
+
+// ShutdownDelay is how long shutdown RPCs
+// wait before the shutdown begins.
+const ShutdownDelay = 1 * time.Second
+
+// System is an RPC service for inspecting
+// and controlling the operating system.
+type System struct {
+	err  chan error
+	stop chan bool
+}
+
+func New() *System {
+	return &System{
+		err:  make(chan error, 1),
+		stop: make(chan bool, 1),
+	}
+}
+
+func (s *System) Name() string { return "system" }
+
+func (s *System) Configure(cfg config.Config) error { return nil }
+
+func (s *System) Run(*event.EventBus) error {
+	select {
+	case err := <-s.err:
+		return err
+	case <-s.stop:
+		return nil
+	}
+}
+
+func (s *System) Stop() error {
+	s.stop <- true
+	return nil
+}
+
+func (s *System) RPC() *grpc.ServiceDesc { return &_RPC_serviceDesc }
+
+// Cgroups returns the cgroup controllers listed
+// in /proc/cgroups and where they are mounted.
+func (s *System) Cgroups(ctx context.Context, req *CgroupsRequest) (*CgroupsResponse, error) {
+	if err := host.Check(req.Host); err != nil {
+		return nil, err
+	}
+	controllers, err := ginit.ReadControllers()
+	if err != nil {
+		return nil, err
+	}
+	mounts, err := boot.CgroupMounts()
+	if err != nil {
+		return nil, err
+	}
+	resp := &CgroupsResponse{Controllers: []*Controller{}}
+	for _, c := range controllers {
+		controller := &Controller{
+			Name:       c.Name,
+			Hierarchy:  int32(c.Hierarchy),
+			NumCgroups: int32(c.NumCgroups),
+			Enabled:    c.Enabled,
+		}
+	loop:
+		for _, mount := range mounts {
+			for _, name := range mount.Controllers {
+				if name == c.Name {
+					controller.Path = mount.Path
+					controller.Version = mount.Version
+					break loop
+				}
+			}
+		}
+		controller.Skipped = !c.Enabled && controller.Path == ""
+		resp.Controllers = append(resp.Controllers, controller)
+	}
+	return resp, nil
+}
+
+// Reboot shuts down Gaffer and reboots the system.
+func (s *System) Reboot(ctx context.Context, req *ShutdownRequest) (*ShutdownResponse, error) {
+	return s.shutdown(req, syscall.SIGTERM)
+}
+
+// Poweroff shuts down Gaffer and powers off the system.
+func (s *System) Poweroff(ctx context.Context, req *ShutdownRequest) (*ShutdownResponse, error) {
+	return s.shutdown(req, syscall.SIGUSR2)
+}
+
+// Halt shuts down Gaffer and halts the system.
+func (s *System) Halt(ctx context.Context, req *ShutdownRequest) (*ShutdownResponse, error) {
+	return s.shutdown(req, syscall.SIGUSR1)
+}
+
+// shutdown signals this process after ShutdownDelay
+// so the response is sent before the RPC server is
+// closed. The signal is handled by the shutdown
+// manager exactly as if it were sent by the kernel
+// or another process.
+func (s *System) shutdown(req *ShutdownRequest, sig syscall.Signal) (*ShutdownResponse, error) {
+	if err := host.Check(req.Host); err != nil {
+		return nil, err
+	}
+	log.Named("system").Info(fmt.Sprintf("shutdown requested with signal %s", sig))
+	time.AfterFunc(ShutdownDelay, func() {
+		syscall.Kill(os.Getpid(), sig)
+	})
+	return &ShutdownResponse{}, nil
+}
@@ -0,0 +1,375 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: github.com/mesanine/gaffer/plugin/system/system.proto
+
+/*
+Package system is a generated protocol buffer package.
+
+It is generated from these files:
+	github.com/mesanine/gaffer/plugin/system/system.proto
+
+It has these top-level messages:
+	CgroupsRequest
+	CgroupsResponse
+	Controller
+	ShutdownRequest
+	ShutdownResponse
+*/
+package system
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import host "github.com/mesanine/gaffer/host"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type CgroupsRequest struct {
+	Host *host.Host `protobuf:"bytes,1,opt,name=host" json:"host,omitempty"`
+}
+
+func (m *CgroupsRequest) Reset()                    { *m = CgroupsRequest{} }
+func (m *CgroupsRequest) String() string            { return proto.CompactTextString(m) }
+func (*CgroupsRequest) ProtoMessage()               {}
+func (*CgroupsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+func (m *CgroupsRequest) GetHost() *host.Host {
+	if m != nil {
+		return m.Host
+	}
+	return nil
+}
+
+type CgroupsResponse struct {
+	Controllers []*Controller `protobuf:"bytes,1,rep,name=controllers" json:"controllers,omitempty"`
+}
+
+func (m *CgroupsResponse) Reset()                    { *m = CgroupsResponse{} }
+func (m *CgroupsResponse) String() string            { return proto.CompactTextString(m) }
+func (*CgroupsResponse) ProtoMessage()               {}
+func (*CgroupsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *CgroupsResponse) GetControllers() []*Controller {
+	if m != nil {
+		return m.Controllers
+	}
+	return nil
+}
+
+// Controller is a cgroup controller
+// detected on the host.
+type Controller struct {
+	Name       string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Hierarchy  int32  `protobuf:"varint,2,opt,name=hierarchy" json:"hierarchy,omitempty"`
+	NumCgroups int32  `protobuf:"varint,3,opt,name=num_cgroups,json=numCgroups" json:"num_cgroups,omitempty"`
+	Enabled    bool   `protobuf:"varint,4,opt,name=enabled" json:"enabled,omitempty"`
+	// Path the controller is mounted at
+	Path string `protobuf:"bytes,5,opt,name=path" json:"path,omitempty"`
+	// Cgroup version of the hierarchy
+	Version string `protobuf:"bytes,6,opt,name=version" json:"version,omitempty"`
+	// Controller is disabled so it
+	// was not mounted at boot
+	Skipped bool `protobuf:"varint,7,opt,name=skipped" json:"skipped,omitempty"`
+}
+
+func (m *Controller) Reset()                    { *m = Controller{} }
+func (m *Controller) String() string            { return proto.CompactTextString(m) }
+func (*Controller) ProtoMessage()               {}
+func (*Controller) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *Controller) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Controller) GetHierarchy() int32 {
+	if m != nil {
+		return m.Hierarchy
+	}
+	return 0
+}
+
+func (m *Controller) GetNumCgroups() int32 {
+	if m != nil {
+		return m.NumCgroups
+	}
+	return 0
+}
+
+func (m *Controller) GetEnabled() bool {
+	if m != nil {
+		return m.Enabled
+	}
+	return false
+}
+
+func (m *Controller) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *Controller) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *Controller) GetSkipped() bool {
+	if m != nil {
+		return m.Skipped
+	}
+	return false
+}
+
+type ShutdownRequest struct {
+	Host *host.Host `protobuf:"bytes,1,opt,name=host" json:"host,omitempty"`
+}
+
+func (m *ShutdownRequest) Reset()                    { *m = ShutdownRequest{} }
+func (m *ShutdownRequest) String() string            { return proto.CompactTextString(m) }
+func (*ShutdownRequest) ProtoMessage()               {}
+func (*ShutdownRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *ShutdownRequest) GetHost() *host.Host {
+	if m != nil {
+		return m.Host
+	}
+	return nil
+}
+
+type ShutdownResponse struct {
+}
+
+func (m *ShutdownResponse) Reset()                    { *m = ShutdownResponse{} }
+func (m *ShutdownResponse) String() string            { return proto.CompactTextString(m) }
+func (*ShutdownResponse) ProtoMessage()               {}
+func (*ShutdownResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+func init() {
+	proto.RegisterType((*CgroupsRequest)(nil), "system.CgroupsRequest")
+	proto.RegisterType((*CgroupsResponse)(nil), "system.CgroupsResponse")
+	proto.RegisterType((*Controller)(nil), "system.Controller")
+	proto.RegisterType((*ShutdownRequest)(nil), "system.ShutdownRequest")
+	proto.RegisterType((*ShutdownResponse)(nil), "system.ShutdownResponse")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for RPC service
+
+type RPCClient interface {
+	Cgroups(ctx context.Context, in *CgroupsRequest, opts ...grpc.CallOption) (*CgroupsResponse, error)
+	Reboot(ctx context.Context, in *ShutdownRequest, opts ...grpc.CallOption) (*ShutdownResponse, error)
+	Poweroff(ctx context.Context, in *ShutdownRequest, opts ...grpc.CallOption) (*ShutdownResponse, error)
+	Halt(ctx context.Context, in *ShutdownRequest, opts ...grpc.CallOption) (*ShutdownResponse, error)
+}
+
+type rPCClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewRPCClient(cc *grpc.ClientConn) RPCClient {
+	return &rPCClient{cc}
+}
+
+func (c *rPCClient) Cgroups(ctx context.Context, in *CgroupsRequest, opts ...grpc.CallOption) (*CgroupsResponse, error) {
+	out := new(CgroupsResponse)
+	err := grpc.Invoke(ctx, "/system.RPC/Cgroups", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rPCClient) Reboot(ctx context.Context, in *ShutdownRequest, opts ...grpc.CallOption) (*ShutdownResponse, error) {
+	out := new(ShutdownResponse)
+	err := grpc.Invoke(ctx, "/system.RPC/Reboot", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rPCClient) Poweroff(ctx context.Context, in *ShutdownRequest, opts ...grpc.CallOption) (*ShutdownResponse, error) {
+	out := new(ShutdownResponse)
+	err := grpc.Invoke(ctx, "/system.RPC/Poweroff", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rPCClient) Halt(ctx context.Context, in *ShutdownRequest, opts ...grpc.CallOption) (*ShutdownResponse, error) {
+	out := new(ShutdownResponse)
+	err := grpc.Invoke(ctx, "/system.RPC/Halt", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for RPC service
+
+type RPCServer interface {
+	Cgroups(context.Context, *CgroupsRequest) (*CgroupsResponse, error)
+	Reboot(context.Context, *ShutdownRequest) (*ShutdownResponse, error)
+	Poweroff(context.Context, *ShutdownRequest) (*ShutdownResponse, error)
+	Halt(context.Context, *ShutdownRequest) (*ShutdownResponse, error)
+}
+
+func RegisterRPCServer(s *grpc.Server, srv RPCServer) {
+	s.RegisterService(&_RPC_serviceDesc, srv)
+}
+
+func _RPC_Cgroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CgroupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCServer).Cgroups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/system.RPC/Cgroups",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCServer).Cgroups(ctx, req.(*CgroupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RPC_Reboot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShutdownRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCServer).Reboot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/system.RPC/Reboot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCServer).Reboot(ctx, req.(*ShutdownRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RPC_Poweroff_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShutdownRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCServer).Poweroff(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/system.RPC/Poweroff",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCServer).Poweroff(ctx, req.(*ShutdownRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RPC_Halt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShutdownRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCServer).Halt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/system.RPC/Halt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCServer).Halt(ctx, req.(*ShutdownRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _RPC_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "system.RPC",
+	HandlerType: (*RPCServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Cgroups",
+			Handler:    _RPC_Cgroups_Handler,
+		},
+		{
+			MethodName: "Reboot",
+			Handler:    _RPC_Reboot_Handler,
+		},
+		{
+			MethodName: "Poweroff",
+			Handler:    _RPC_Poweroff_Handler,
+		},
+		{
+			MethodName: "Halt",
+			Handler:    _RPC_Halt_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "github.com/mesanine/gaffer/plugin/system/system.proto",
+}
+
+func init() {
+	proto.RegisterFile("github.com/mesanine/gaffer/plugin/system/system.proto", fileDescriptor0)
+}
+
+var fileDescriptor0 = []byte{
+	// 371 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x52, 0x4d, 0xaf, 0x93, 0x40,
+	0x14, 0x95, 0x96, 0xd2, 0xf6, 0x92, 0x58, 0x33, 0x0b, 0x9d, 0x34, 0x46, 0x09, 0x2b, 0xe2, 0x02,
+	0xb4, 0xea, 0x4a, 0x8d, 0x8b, 0x2e, 0xec, 0xb2, 0x19, 0x7f, 0x80, 0x01, 0x7a, 0xf9, 0x88, 0x30,
+	0x83, 0x33, 0x83, 0x4d, 0x77, 0xfe, 0x35, 0xff, 0x99, 0x61, 0xa0, 0xd4, 0xf7, 0x9a, 0xbc, 0xbc,
+	0x74, 0x03, 0xf7, 0xde, 0x73, 0xce, 0x3d, 0x27, 0x17, 0xe0, 0x63, 0x5e, 0xea, 0xa2, 0x4d, 0xc2,
+	0x54, 0xd4, 0x51, 0x8d, 0x2a, 0xe6, 0x25, 0xc7, 0x28, 0x8f, 0xb3, 0x0c, 0x65, 0xd4, 0x54, 0x6d,
+	0x5e, 0xf2, 0x48, 0x9d, 0x94, 0xc6, 0x7a, 0x78, 0x85, 0x8d, 0x14, 0x5a, 0x10, 0xa7, 0xef, 0xd6,
+	0x6f, 0x1e, 0x90, 0x17, 0x42, 0x69, 0xf3, 0xe8, 0x35, 0xfe, 0x5b, 0x78, 0xba, 0xcd, 0xa5, 0x68,
+	0x1b, 0xc5, 0xf0, 0x57, 0x8b, 0x4a, 0x93, 0x57, 0x60, 0x77, 0x38, 0xb5, 0x3c, 0x2b, 0x70, 0x37,
+	0x10, 0x1a, 0xf2, 0x4e, 0x28, 0xcd, 0xcc, 0xdc, 0xff, 0x06, 0xab, 0x51, 0xa1, 0x1a, 0xc1, 0x15,
+	0x92, 0x0f, 0xe0, 0xa6, 0x82, 0x6b, 0x29, 0xaa, 0x0a, 0xa5, 0xa2, 0x96, 0x37, 0x0d, 0xdc, 0x0d,
+	0x09, 0x87, 0x70, 0xdb, 0x11, 0x62, 0xff, 0xd3, 0xfc, 0xbf, 0x16, 0xc0, 0x05, 0x23, 0x04, 0x6c,
+	0x1e, 0xd7, 0x68, 0x7c, 0x97, 0xcc, 0xd4, 0xe4, 0x25, 0x2c, 0x8b, 0x12, 0x65, 0x2c, 0xd3, 0xe2,
+	0x44, 0x27, 0x9e, 0x15, 0xcc, 0xd8, 0x65, 0x40, 0x5e, 0x83, 0xcb, 0xdb, 0xfa, 0x47, 0xda, 0xa7,
+	0xa1, 0x53, 0x83, 0x03, 0x6f, 0xeb, 0x21, 0x1f, 0xa1, 0x30, 0x47, 0x1e, 0x27, 0x15, 0x1e, 0xa8,
+	0xed, 0x59, 0xc1, 0x82, 0x9d, 0xdb, 0xce, 0xac, 0x89, 0x75, 0x41, 0x67, 0xbd, 0x59, 0x57, 0x77,
+	0xec, 0xdf, 0x28, 0x55, 0x29, 0x38, 0x75, 0xcc, 0xf8, 0xdc, 0x76, 0x88, 0xfa, 0x59, 0x36, 0x0d,
+	0x1e, 0xe8, 0xbc, 0xdf, 0x33, 0xb4, 0xfe, 0x3b, 0x58, 0x7d, 0x2f, 0x5a, 0x7d, 0x10, 0x47, 0xfe,
+	0xd8, 0xfb, 0x11, 0x78, 0x76, 0x91, 0xf4, 0x07, 0xdc, 0xfc, 0x99, 0xc0, 0x94, 0xed, 0xb7, 0xe4,
+	0x33, 0xcc, 0xcf, 0xd9, 0x9f, 0x8f, 0xe7, 0xbb, 0xf3, 0x79, 0xd6, 0x2f, 0xae, 0xe6, 0xfd, 0x0e,
+	0xff, 0x09, 0xf9, 0x02, 0x0e, 0xc3, 0x44, 0x08, 0x4d, 0x46, 0xd2, 0xbd, 0x70, 0x6b, 0x7a, 0x0d,
+	0x8c, 0xf2, 0xaf, 0xb0, 0xd8, 0x8b, 0x23, 0x4a, 0x91, 0x65, 0xb7, 0x2d, 0xf8, 0x04, 0xf6, 0x2e,
+	0xae, 0x6e, 0x73, 0x4f, 0x1c, 0xf3, 0x3f, 0xbe, 0xff, 0x37, 0x00, 0xfa, 0x5f, 0x75, 0xb5, 0xfc,
+	0x02, 0x00, 0x00,
+}
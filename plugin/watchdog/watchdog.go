@@ -0,0 +1,179 @@
+package watchdog
+
+import (
+	"fmt"
+	"github.com/mesanine/gaffer/config"
+	"github.com/mesanine/gaffer/event"
+	"github.com/mesanine/gaffer/fatal"
+	"github.com/mesanine/gaffer/liveness"
+	"github.com/mesanine/gaffer/log"
+	"go.uber.org/zap"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// Stall actions
+const (
+	// Fatal calls fatal.Fatal and returns
+	// an error if it does not crash the system.
+	Fatal = "fatal"
+	// Reboot requests an orderly reboot.
+	Reboot = "reboot"
+	// Log only logs the stall and stops
+	// petting the hardware watchdog.
+	Log = "log"
+)
+
+// WDIOC_SETTIMEOUT from linux/watchdog.h
+const setTimeout = 0xc0045706
+
+// Watchdog pets a hardware watchdog device while
+// the core Gaffer goroutines are making progress
+// and takes an action if any of them stall.
+type Watchdog struct {
+	config config.Watchdog
+	device *os.File
+	err    chan error
+	stop   chan bool
+}
+
+func New() *Watchdog {
+	return &Watchdog{
+		err:  make(chan error, 1),
+		stop: make(chan bool, 1),
+	}
+}
+
+func (w *Watchdog) Name() string { return "watchdog" }
+
+func (w *Watchdog) Configure(cfg config.Config) error {
+	switch cfg.Watchdog.Action {
+	case Fatal, Reboot, Log:
+	default:
+		return fmt.Errorf("unknown watchdog action %s", cfg.Watchdog.Action)
+	}
+	if cfg.Watchdog.Threshold <= 0 {
+		return fmt.Errorf("watchdog threshold must be positive")
+	}
+	w.config = cfg.Watchdog
+	return nil
+}
+
+func (w *Watchdog) Run(*event.EventBus) error {
+	if err := w.open(); err != nil {
+		return err
+	}
+	threshold := time.Duration(w.config.Threshold) * time.Second
+	// Pet the device three times
+	// within each timeout.
+	interval := time.Duration(w.config.Timeout) * time.Second / 3
+	if interval < liveness.Interval {
+		interval = liveness.Interval
+	}
+	ticker := time.NewTicker(liveness.Interval)
+	defer ticker.Stop()
+	var (
+		petted  time.Time
+		stalled bool
+	)
+	for {
+		select {
+		case err := <-w.err:
+			return err
+		case <-w.stop:
+			w.disarm()
+			return nil
+		case <-ticker.C:
+			if stalled {
+				continue
+			}
+			if names := liveness.Stalled(threshold); len(names) > 0 {
+				stalled = true
+				if err := w.stalled(names); err != nil {
+					return err
+				}
+				continue
+			}
+			if time.Since(petted) >= interval {
+				if err := w.pet(); err != nil {
+					return err
+				}
+				petted = time.Now()
+			}
+		}
+	}
+}
+
+func (w *Watchdog) Stop() error {
+	w.stop <- true
+	return nil
+}
+
+// stalled takes the configured action after
+// which the device is no longer pet.
+func (w *Watchdog) stalled(names []string) error {
+	log.Named("watchdog").Error(
+		"core goroutines have stalled",
+		zap.Strings("stalled", names),
+		zap.String("action", w.config.Action),
+	)
+	switch w.config.Action {
+	case Fatal:
+		fatal.Fatal()
+		return fmt.Errorf("core goroutines have stalled: %s", strings.Join(names, ","))
+	case Reboot:
+		return syscall.Kill(os.Getpid(), syscall.SIGTERM)
+	}
+	return nil
+}
+
+// open opens the device unless it is still open
+// from a previous run which returned an error as
+// the device can only be opened once.
+func (w *Watchdog) open() error {
+	if w.config.Device == "" || w.device != nil {
+		return nil
+	}
+	fd, err := os.OpenFile(w.config.Device, os.O_WRONLY, 0)
+	if err != nil {
+		if os.IsNotExist(err) {
+			log.Named("watchdog").Warn(fmt.Sprintf("watchdog device %s does not exist", w.config.Device))
+			return nil
+		}
+		return err
+	}
+	w.device = fd
+	if w.config.Timeout > 0 {
+		timeout := int32(w.config.Timeout)
+		_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd.Fd(), setTimeout, uintptr(unsafe.Pointer(&timeout)))
+		if errno != 0 {
+			log.Named("watchdog").Warn(fmt.Sprintf("could not set watchdog timeout: %s", errno.Error()))
+		}
+	}
+	log.Named("watchdog").Info(fmt.Sprintf("opened watchdog device %s", w.config.Device))
+	return nil
+}
+
+func (w *Watchdog) pet() error {
+	if w.device == nil {
+		return nil
+	}
+	_, err := w.device.Write([]byte{0})
+	return err
+}
+
+// disarm writes the magic character before
+// closing the device so it does not reset the
+// system. The device is deliberately left armed
+// and open if Run returns an error.
+func (w *Watchdog) disarm() {
+	if w.device == nil {
+		return
+	}
+	w.device.Write([]byte("V"))
+	w.device.Close()
+	w.device = nil
+}
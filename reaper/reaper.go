@@ -0,0 +1,226 @@
+/*
+package reaper reaps exited child processes. When
+Gaffer runs as PID 1 every orphaned process on the
+system is reparented to it and must be waited on or
+it remains a zombie. The Reaper also implements the
+go-runc ProcessMonitor so the exit status of runc
+commands is delivered to the caller rather than
+being lost to the reaper.
+*/
+package reaper
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/containerd/go-runc"
+	"github.com/mesanine/gaffer/log"
+	"golang.org/x/sys/unix"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// PollInterval is how often waiting callers reap
+// in case a SIGCHLD was delivered before the
+// signal handler was installed.
+const PollInterval = 1 * time.Second
+
+// Default is the Reaper installed
+// as the go-runc process monitor.
+var Default = New()
+
+// Reaper waits on child processes handing
+// the exit status of monitored commands
+// back to their callers.
+type Reaper struct {
+	mu sync.Mutex
+	// waiters are monitored commands
+	// by process ID.
+	waiters map[int]chan int
+	// reaped counts processes
+	// which were not monitored.
+	reaped uint64
+}
+
+func New() *Reaper {
+	return &Reaper{waiters: map[int]chan int{}}
+}
+
+// Install registers the Reaper as the go-runc
+// process monitor and makes this process a
+// child subreaper so orphans of its children
+// are reparented to it even if it is not PID 1.
+func (r *Reaper) Install() error {
+	runc.Monitor = r
+	if os.Getpid() == 1 {
+		return nil
+	}
+	return unix.Prctl(unix.PR_SET_CHILD_SUBREAPER, 1, 0, 0, 0)
+}
+
+// Handle implements the ginit.Handler interface
+// reaping children each time SIGCHLD is received.
+func (r *Reaper) Handle(sig os.Signal) error {
+	if sig == unix.SIGCHLD {
+		r.Reap()
+	}
+	return nil
+}
+
+// Reap waits on every exited child
+// without blocking.
+func (r *Reaper) Reap() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for {
+		var status syscall.WaitStatus
+		pid, err := syscall.Wait4(-1, &status, syscall.WNOHANG, nil)
+		if pid <= 0 || err != nil {
+			return
+		}
+		if ch, ok := r.waiters[pid]; ok {
+			ch <- exitStatus(status)
+			continue
+		}
+		r.reaped++
+		log.Log.Debug(fmt.Sprintf("reaped orphaned process %d with status %d", pid, exitStatus(status)))
+	}
+}
+
+// Reaped returns the number of
+// unmonitored processes reaped.
+func (r *Reaper) Reaped() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.reaped
+}
+
+// Start starts a monitored command.
+func (r *Reaper) Start(c *exec.Cmd) error {
+	// Reaping is blocked until the command
+	// is registered so it cannot exit
+	// before it has a waiter.
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := c.Start(); err != nil {
+		return err
+	}
+	r.waiters[c.Process.Pid] = make(chan int, 1)
+	return nil
+}
+
+// Wait waits for a command started
+// with Start and returns its status.
+func (r *Reaper) Wait(c *exec.Cmd) (int, error) {
+	r.mu.Lock()
+	ch, ok := r.waiters[c.Process.Pid]
+	r.mu.Unlock()
+	if !ok {
+		return -1, fmt.Errorf("process %d is not monitored", c.Process.Pid)
+	}
+	ticker := time.NewTicker(PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case status := <-ch:
+			r.mu.Lock()
+			delete(r.waiters, c.Process.Pid)
+			r.mu.Unlock()
+			// The process was already reaped
+			// but Wait releases its resources
+			// and flushes any output.
+			c.Wait()
+			return status, nil
+		case <-ticker.C:
+			r.Reap()
+		}
+	}
+}
+
+func (r *Reaper) Run(c *exec.Cmd) error {
+	if err := r.Start(c); err != nil {
+		return err
+	}
+	status, err := r.Wait(c)
+	if err != nil {
+		return err
+	}
+	if status != 0 {
+		return fmt.Errorf("exit status %d", status)
+	}
+	return nil
+}
+
+func (r *Reaper) Output(c *exec.Cmd) ([]byte, error) {
+	stdout := bytes.NewBuffer(nil)
+	c.Stdout = stdout
+	err := r.Run(c)
+	return stdout.Bytes(), err
+}
+
+func (r *Reaper) CombinedOutput(c *exec.Cmd) ([]byte, error) {
+	output := bytes.NewBuffer(nil)
+	c.Stdout = output
+	c.Stderr = output
+	err := r.Run(c)
+	return output.Bytes(), err
+}
+
+// Zombies counts the child processes which
+// have exited but have not been reaped.
+func Zombies() (int, error) {
+	paths, err := filepath.Glob("/proc/[0-9]*/stat")
+	if err != nil {
+		return 0, err
+	}
+	self := os.Getpid()
+	var count int
+	for _, path := range paths {
+		raw, err := ioutil.ReadFile(path)
+		if err != nil {
+			// The process exited
+			continue
+		}
+		state, ppid, err := parseStat(string(raw))
+		if err != nil {
+			return 0, err
+		}
+		if state == "Z" && ppid == self {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// parseStat returns the state and parent
+// process ID from /proc/<pid>/stat.
+func parseStat(stat string) (string, int, error) {
+	// The command name is in parentheses
+	// and may itself contain spaces.
+	i := strings.LastIndex(stat, ")")
+	if i < 0 {
+		return "", 0, fmt.Errorf("bad process stat: %s", stat)
+	}
+	fields := strings.Fields(stat[i+1:])
+	if len(fields) < 2 {
+		return "", 0, fmt.Errorf("bad process stat: %s", stat)
+	}
+	ppid, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return "", 0, err
+	}
+	return fields[0], ppid, nil
+}
+
+func exitStatus(status syscall.WaitStatus) int {
+	if status.Signaled() {
+		return 128 + int(status.Signal())
+	}
+	return status.ExitStatus()
+}
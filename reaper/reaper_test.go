@@ -0,0 +1,29 @@
+package reaper
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os/exec"
+	"testing"
+)
+
+func TestParseStat(t *testing.T) {
+	state, ppid, err := parseStat("1234 (some (odd) name) Z 1 1234 1234 0 -1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Z", state)
+	assert.Equal(t, 1, ppid)
+	_, _, err = parseStat("1234 bad")
+	assert.Error(t, err)
+}
+
+func TestReaperMonitor(t *testing.T) {
+	r := New()
+	out, err := r.Output(exec.Command("echo", "hello"))
+	assert.NoError(t, err)
+	assert.Equal(t, "hello\n", string(out))
+	cmd := exec.Command("sh", "-c", "exit 3")
+	assert.NoError(t, r.Start(cmd))
+	r.Reap()
+	status, err := r.Wait(cmd)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, status)
+}
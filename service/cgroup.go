@@ -0,0 +1,54 @@
+package service
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Proc is where the cgroups
+// of processes are read from.
+const Proc = "/proc"
+
+// Cgroups returns the cgroup paths of a process
+// from proc. The process may have exited
+// already in which case none are returned.
+func Cgroups(proc string, pid int) []string {
+	fd, err := os.Open(fmt.Sprintf("%s/%d/cgroup", proc, pid))
+	if err != nil {
+		return nil
+	}
+	defer fd.Close()
+	return parseCgroups(fd)
+}
+
+// parseCgroups parses lines of
+// hierarchy-ID:controllers:path
+func parseCgroups(r io.Reader) []string {
+	paths := []string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), ":", 3)
+		if len(parts) == 3 {
+			paths = append(paths, parts[2])
+		}
+	}
+	return paths
+}
+
+// Owner returns the service of the first cgroup
+// path with a component matching a service ID.
+// Runc places containers into cgroups named
+// after the container ID by default.
+func Owner(paths []string, services map[string]bool) string {
+	for _, path := range paths {
+		for _, name := range strings.Split(path, "/") {
+			if services[name] {
+				return name
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,14 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestOwner(t *testing.T) {
+	paths := parseCgroups(strings.NewReader("12:memory:/gaffer/web\n0::/init.scope\n"))
+	assert.Equal(t, []string{"/gaffer/web", "/init.scope"}, paths)
+	assert.Equal(t, "web", Owner(paths, map[string]bool{"web": true, "db": true}))
+	assert.Equal(t, "", Owner(paths, map[string]bool{"db": true}))
+}
@@ -27,3 +27,29 @@ func Stats(svc Service) *runc.Stats {
 	}
 	return stats
 }
+
+// MarshalJSON encodes the spec and stats
+// as nested objects rather than the base64
+// encoding of their bytes.
+func (svc Service) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Id     string          `json:"id,omitempty"`
+		Bundle string          `json:"bundle,omitempty"`
+		Spec   json.RawMessage `json:"spec,omitempty"`
+		Stats  json.RawMessage `json:"stats,omitempty"`
+	}{
+		Id:     svc.Id,
+		Bundle: svc.Bundle,
+		Spec:   RawJSON(svc.Spec),
+		Stats:  RawJSON(svc.Stats),
+	})
+}
+
+// RawJSON returns raw as a json.RawMessage or
+// nil if it is not valid JSON.
+func RawJSON(raw []byte) json.RawMessage {
+	if !json.Valid(raw) {
+		return nil
+	}
+	return json.RawMessage(raw)
+}
@@ -0,0 +1,154 @@
+/*
+package shutdown sequences an orderly shutdown of
+Gaffer before the operating system is halted,
+powered off or rebooted. Services are stopped in
+reverse dependency order, plugins are shutdown,
+container root filesystems are unmounted and
+the logger is flushed before any remaining
+processes are terminated.
+*/
+package shutdown
+
+import (
+	"fmt"
+	"github.com/mesanine/gaffer/log"
+	"github.com/mesanine/gaffer/plugin"
+	"github.com/mesanine/gaffer/reaper"
+	"github.com/mesanine/gaffer/store"
+	"github.com/mesanine/ginit"
+	"go.uber.org/zap"
+	"golang.org/x/sys/unix"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// PluginTimeout is how long each plugin
+	// is given to return after it is stopped.
+	PluginTimeout = 60 * time.Second
+	// GracePeriod is how long remaining processes
+	// are given to exit after SIGTERM before
+	// they are killed.
+	GracePeriod = 5 * time.Second
+)
+
+// Signals which trigger a shutdown and the
+// reboot command issued afterwards. These
+// match the signals handled by ginit.Init.
+var Signals = map[os.Signal]int{
+	unix.SIGUSR1: unix.LINUX_REBOOT_CMD_HALT,
+	unix.SIGUSR2: unix.LINUX_REBOOT_CMD_POWER_OFF,
+	unix.SIGTERM: unix.LINUX_REBOOT_CMD_RESTART,
+}
+
+// Manager stops the plugins in a
+// Registry and closes the stores.
+type Manager struct {
+	registry *plugin.Registry
+	stores   []*store.FSStore
+}
+
+func New(reg *plugin.Registry, stores ...*store.FSStore) *Manager {
+	return &Manager{
+		registry: reg,
+		stores:   stores,
+	}
+}
+
+// Init replaces ginit.Init propagating every signal
+// it receives to the handlers. SIGINT shuts Gaffer
+// down and returns while SIGUSR1, SIGUSR2 and SIGTERM
+// shut it down before halting, powering off or
+// rebooting the system.
+func (m *Manager) Init(handlers ...ginit.Handler) error {
+	sigCh := make(chan os.Signal, 10)
+	signal.Reset()
+	signal.Notify(sigCh)
+	for {
+		sig := <-sigCh
+		for _, handler := range handlers {
+			if err := handler.Handle(sig); err != nil {
+				return err
+			}
+		}
+		if sig == unix.SIGINT {
+			return m.Shutdown()
+		}
+		if cmd, ok := Signals[sig]; ok {
+			if err := m.Shutdown(); err != nil {
+				// Continue so the system is
+				// not left half shutdown.
+				log.Log.Error("shutdown failed", zap.Error(err))
+			}
+			return Reboot(cmd)
+		}
+	}
+}
+
+// Shutdown stops the plugins in reverse dependency
+// order so services are stopped by the supervisor
+// while the plugins it depends on are still running.
+// Finally the stores are closed and the logger
+// is flushed.
+func (m *Manager) Shutdown() error {
+	log.Log.Info("shutting down")
+	failed := m.registry.Shutdown(PluginTimeout)
+	for _, db := range m.stores {
+		if err := db.Close(); err != nil {
+			log.Log.Error(fmt.Sprintf("failed to close store %s", db.BasePath), zap.Error(err))
+			failed = err
+		}
+	}
+	log.Log.Info("shutdown complete")
+	log.Log.Sync()
+	return failed
+}
+
+// Reboot terminates every remaining process, syncs
+// the filesystems and issues the reboot command. It
+// does nothing unless this process is PID 1.
+func Reboot(cmd int) error {
+	if os.Getpid() != 1 {
+		log.Log.Info("not running as PID 1, will not reboot")
+		return nil
+	}
+	// SIGTERM all processes except pid 1
+	if err := unix.Kill(-1, unix.SIGTERM); err != nil && err != unix.ESRCH {
+		return err
+	}
+	deadline := time.Now().Add(GracePeriod)
+	for time.Now().Before(deadline) && processes() > 0 {
+		reaper.Default.Reap()
+		time.Sleep(100 * time.Millisecond)
+	}
+	// SIGKILL all processes except pid 1
+	if err := unix.Kill(-1, unix.SIGKILL); err != nil && err != unix.ESRCH {
+		return err
+	}
+	reaper.Default.Reap()
+	unix.Sync()
+	// Bye Bye!
+	return unix.Reboot(cmd)
+}
+
+// processes counts the running user space
+// processes other than this one. Kernel
+// threads and zombies have no command line.
+func processes() int {
+	paths, _ := filepath.Glob("/proc/[0-9]*/cmdline")
+	self := fmt.Sprintf("/proc/%d/cmdline", os.Getpid())
+	var count int
+	for _, path := range paths {
+		if path == self {
+			continue
+		}
+		raw, err := ioutil.ReadFile(path)
+		if err == nil && len(raw) > 0 {
+			count++
+		}
+	}
+	return count
+}
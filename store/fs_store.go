@@ -7,11 +7,18 @@ import (
 	"github.com/mesanine/gaffer/log"
 	"github.com/mesanine/gaffer/service"
 	"github.com/mesanine/ginit"
+	"github.com/opencontainers/runtime-spec/specs-go"
 	"io/ioutil"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
+// SocketEnv is set to the address of the RPC
+// socket within services it is mounted into.
+const SocketEnv = "GAFFER_ADDRESS"
+
 // FSStore reads runc Service
 // configuration from a base
 // path. It is is compatible
@@ -22,6 +29,10 @@ type FSStore struct {
 	Mount       bool
 	MoveRoot    bool
 	Environment map[string]map[string]string
+	Sockets     map[string]string
+	// Socket is the path of the RPC
+	// socket if it listens on one.
+	Socket string
 }
 
 func (s FSStore) Services() ([]service.Service, error) {
@@ -94,13 +105,20 @@ func (s FSStore) Init() error {
 				return err
 			}
 		}
-		if envs, ok := s.Environment[svc.Id]; ok {
+		envs, hasEnv := s.Environment[svc.Id]
+		target, hasSocket := s.Sockets[svc.Id]
+		if hasEnv || hasSocket {
 			updated := service.Spec(svc)
 			// Append any existing environment variables
 			// in the config.json file
 			for key, value := range envs {
 				updated.Process.Env = append(updated.Process.Env, fmt.Sprintf("%s=%s", key, value))
 			}
+			if hasSocket {
+				if err := s.mountSocket(updated, target); err != nil {
+					return err
+				}
+			}
 			raw, err := json.Marshal(updated)
 			if err != nil {
 				return err
@@ -143,11 +161,48 @@ func (s FSStore) Init() error {
 	return nil
 }
 
+// mountSocket bind mounts the directory of the RPC
+// socket into a service at target. The directory is
+// mounted rather than the socket so the service can
+// still connect once the socket is recreated.
+func (s FSStore) mountSocket(spec *specs.Spec, target string) error {
+	if s.Socket == "" {
+		return fmt.Errorf("cannot mount the RPC socket into %s, the RPC address is not a unix socket", target)
+	}
+	mounted := false
+	for _, mount := range spec.Mounts {
+		if mount.Destination == target {
+			mounted = true
+		}
+	}
+	if !mounted {
+		spec.Mounts = append(spec.Mounts, specs.Mount{
+			Destination: target,
+			Type:        "bind",
+			Source:      filepath.Dir(s.Socket),
+			Options:     []string{"rbind", "rw"},
+		})
+	}
+	env := []string{}
+	for _, value := range spec.Process.Env {
+		if !strings.HasPrefix(value, SocketEnv+"=") {
+			env = append(env, value)
+		}
+	}
+	spec.Process.Env = append(env, fmt.Sprintf("%s=unix://%s", SocketEnv, filepath.Join(target, filepath.Base(s.Socket))))
+	return nil
+}
+
 func New(cfg config.Config, dir string) *FSStore {
-	return &FSStore{
+	store := &FSStore{
 		BasePath:    filepath.Join(cfg.Store.BasePath, dir),
 		Environment: cfg.Store.Environment,
+		Sockets:     cfg.Store.Sockets,
 		Mount:       cfg.Store.Mount,
 		MoveRoot:    cfg.Store.MoveRoot,
 	}
+	if u, err := url.Parse(cfg.Address); err == nil && u.Scheme == "unix" {
+		store.Socket = u.Path
+	}
+	return store
 }
@@ -1,15 +1,46 @@
 package user
 
 import (
+	"context"
+	"crypto/subtle"
 	"fmt"
 	"strings"
 )
 
+const (
+	// MetadataID is the RPC metadata key
+	// holding the ID of the calling user.
+	MetadataID = "gaffer-user"
+	// MetadataToken is the RPC metadata key
+	// holding the token of the calling user.
+	MetadataToken = "gaffer-token"
+)
+
 type User struct {
 	ID    string
 	Token string
 }
 
+// Match checks if the given credentials
+// belong to this user comparing the token
+// in constant time.
+func (u User) Match(id, token string) bool {
+	return u.ID == id && subtle.ConstantTimeCompare([]byte(u.Token), []byte(token)) == 1
+}
+
+// GetRequestMetadata implements the
+// credentials.PerRPCCredentials interface.
+func (u User) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{
+		MetadataID:    u.ID,
+		MetadataToken: u.Token,
+	}, nil
+}
+
+// RequireTransportSecurity implements the
+// credentials.PerRPCCredentials interface.
+func (u User) RequireTransportSecurity() bool { return false }
+
 func FromString(str string) (*User, error) {
 	split := strings.Split(str, ":")
 	if len(split) != 2 {
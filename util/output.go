@@ -0,0 +1,167 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/mesanine/gaffer/config"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+)
+
+// Output formats
+const (
+	// Table shows list-style responses as a table
+	// and other responses as YAML.
+	Table = "table"
+	// Wide is a table with additional columns.
+	Wide     = "wide"
+	JSON     = "json"
+	YAML     = "yaml"
+	Template = "template"
+)
+
+// Tabular is implemented by
+// responses shown as a table.
+type Tabular interface {
+	// Table returns the headers and each row, wide
+	// includes columns which are otherwise omitted.
+	Table(wide bool) ([]string, [][]string)
+}
+
+// Footer is implemented by tabular responses
+// with lines shown below the table.
+type Footer interface {
+	Footer() []string
+}
+
+// CheckOutput validates the output configuration.
+func CheckOutput(out config.Output) error {
+	switch out.Format {
+	case Table, Wide, JSON, YAML:
+	case Template:
+		if out.Template == "" {
+			return fmt.Errorf("the template output format requires a template")
+		}
+		if _, err := template.New("output").Parse(out.Template); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown output format %s", out.Format)
+	}
+	return nil
+}
+
+// Print writes v to stdout
+// in the configured format.
+func Print(cfg config.Config, v interface{}) {
+	Maybe(Format(os.Stdout, cfg.Output, v))
+}
+
+// Format writes v to w in the format of out. Every
+// format other than JSON is rendered from the JSON
+// encoding of v so keys are the same in each.
+func Format(w io.Writer, out config.Output, v interface{}) error {
+	switch out.Format {
+	case JSON:
+		return json.NewEncoder(w).Encode(v)
+	case YAML:
+		return writeYAML(w, v)
+	case Template:
+		generic, err := toGeneric(v)
+		if err != nil {
+			return err
+		}
+		tmpl, err := template.New("output").Parse(out.Template)
+		if err != nil {
+			return err
+		}
+		return tmpl.Execute(w, generic)
+	}
+	wide := out.Format == Wide
+	if t, ok := v.(Tabular); ok {
+		headers, rows := t.Table(wide)
+		if err := writeTable(w, headers, rows); err != nil {
+			return err
+		}
+		if f, ok := v.(Footer); ok {
+			for _, line := range f.Footer() {
+				fmt.Fprintln(w, line)
+			}
+		}
+		return nil
+	}
+	if results, ok := v.([]Result); ok {
+		if headers, rows, ok := resultsTable(results, wide); ok {
+			if err := writeTable(w, headers, rows); err != nil {
+				return err
+			}
+			for _, result := range results {
+				if result.Error != "" {
+					fmt.Fprintf(w, "%s: %s\n", result.Host.Name, result.Error)
+					continue
+				}
+				if f, ok := result.Response.(Footer); ok {
+					for _, line := range f.Footer() {
+						fmt.Fprintf(w, "%s: %s\n", result.Host.Name, line)
+					}
+				}
+			}
+			return nil
+		}
+	}
+	return writeYAML(w, v)
+}
+
+// resultsTable joins the tables of the response of
+// each host prefixed with the host name. It returns
+// false unless every response is shown as a table.
+func resultsTable(results []Result, wide bool) ([]string, [][]string, bool) {
+	var (
+		headers []string
+		rows    = [][]string{}
+	)
+	for _, result := range results {
+		if result.Error != "" {
+			continue
+		}
+		t, ok := result.Response.(Tabular)
+		if !ok {
+			return nil, nil, false
+		}
+		h, r := t.Table(wide)
+		headers = append([]string{"HOST"}, h...)
+		for _, row := range r {
+			rows = append(rows, append([]string{result.Host.Name}, row...))
+		}
+	}
+	return headers, rows, headers != nil
+}
+
+func writeTable(w io.Writer, headers []string, rows [][]string) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// toGeneric returns the JSON encoding of v
+// decoded into maps, slices and scalars.
+func toGeneric(v interface{}) (interface{}, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+	return generic, nil
+}
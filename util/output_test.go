@@ -0,0 +1,68 @@
+package util
+
+import (
+	"bytes"
+	"github.com/mesanine/gaffer/config"
+	"github.com/mesanine/gaffer/host"
+	"github.com/mesanine/gaffer/service"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func format(t *testing.T, out config.Output, v interface{}) string {
+	buf := bytes.NewBuffer(nil)
+	assert.NoError(t, Format(buf, out, v))
+	return buf.String()
+}
+
+func TestFormat(t *testing.T) {
+	svc := service.Service{Id: "web", Spec: []byte(`{"ociVersion":"1.0"}`)}
+	assert.Equal(t, `{"id":"web","spec":{"ociVersion":"1.0"}}`+"\n", format(t, config.Output{Format: JSON}, svc))
+	assert.Equal(t, "web 1.0", format(t, config.Output{Format: Template, Template: "{{.id}} {{.spec.ociVersion}}"}, svc))
+	assert.Equal(t, `id: web
+spec:
+  ociVersion: "1.0"
+`, format(t, config.Output{Format: YAML}, svc))
+	// Responses which are not tabular are YAML
+	assert.Equal(t, format(t, config.Output{Format: YAML}, svc), format(t, config.Output{Format: Table}, svc))
+	hosts := host.Hosts{{Name: "a", Address: "10.0.0.1", Labels: map[string]string{"b": "2", "a": "1"}}}
+	assert.Equal(t, `NAME  ADDRESS   LEADER  SERVICES  LABELS
+a     10.0.0.1  false   -         a=1,b=2
+`, format(t, config.Output{Format: Table}, hosts))
+	results := []Result{
+		{Host: &host.Host{Name: "x"}, Response: hosts},
+		{Host: &host.Host{Name: "y"}, Error: "unreachable"},
+	}
+	assert.Equal(t, `HOST  NAME  ADDRESS   LEADER  SERVICES  LABELS
+x     a     10.0.0.1  false   -         a=1,b=2
+y: unreachable
+`, format(t, config.Output{Format: Table}, results))
+	assert.Error(t, CheckOutput(config.Output{Format: "xml"}))
+	assert.Error(t, CheckOutput(config.Output{Format: Template}))
+	assert.NoError(t, CheckOutput(config.Output{Format: Wide}))
+}
+
+func TestYAML(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	assert.NoError(t, writeYAML(buf, map[string]interface{}{
+		"list":   []interface{}{"a", map[string]interface{}{"k": 1, "l": true}, []interface{}{}},
+		"quoted": []string{"", "true", "a: b", "- x", "line\nbreak", " pad"},
+		"empty":  map[string]interface{}{},
+		"null":   nil,
+	}))
+	assert.Equal(t, `empty: {}
+list:
+- a
+- k: 1
+  l: true
+- []
+"null": null
+quoted:
+- ""
+- "true"
+- "a: b"
+- "- x"
+- "line\nbreak"
+- " pad"
+`, buf.String())
+}
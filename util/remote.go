@@ -0,0 +1,143 @@
+package util
+
+import (
+	"fmt"
+	"github.com/mesanine/gaffer/client"
+	"github.com/mesanine/gaffer/config"
+	"github.com/mesanine/gaffer/host"
+	"google.golang.org/grpc"
+	"regexp"
+	"sync"
+)
+
+// Call makes an RPC call over conn. The host
+// is nil when calling the configured address
+// rather than a registered host.
+type Call func(h *host.Host, conn *grpc.ClientConn) (interface{}, error)
+
+// Result is the outcome of
+// a Call against a single host.
+type Result struct {
+	Host     *host.Host  `json:"host"`
+	Response interface{} `json:"response,omitempty"`
+	Error    string      `json:"error,omitempty"`
+}
+
+// Remote runs call against the configured RPC address
+// and prints the response in the configured format. If
+// any host selectors are configured it instead runs call
+// concurrently against every matching registered host
+// and prints the results of each.
+func Remote(cfg config.Config, call Call) {
+	if !cfg.Remote.Selected() {
+		conn, err := NewClientConn(cfg)
+		Maybe(err)
+		defer conn.Close()
+		resp, err := call(nil, conn)
+		Maybe(err)
+		if resp != nil {
+			Print(cfg, resp)
+		}
+		return
+	}
+	hosts, err := Targets(cfg)
+	Maybe(err)
+	results := FanOut(cfg, hosts, call)
+	Print(cfg, results)
+	var failed int
+	for _, result := range results {
+		if result.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		Maybe(fmt.Errorf("%d of %d hosts failed", failed, len(results)))
+	}
+}
+
+// Targets returns the registered hosts
+// matching the configured selectors.
+func Targets(cfg config.Config) (host.Hosts, error) {
+	filters, err := Selectors(cfg.Remote)
+	if err != nil {
+		return nil, err
+	}
+	cli, err := client.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer cli.Close()
+	hosts, _, err := cli.Hosts()
+	if err != nil {
+		return nil, err
+	}
+	matched := host.Hosts(hosts).Filter(filters...)
+	if len(matched) == 0 {
+		return nil, fmt.Errorf("no registered hosts match")
+	}
+	return matched, nil
+}
+
+// Selectors returns a filter for each configured
+// selector, hosts must match all of them. All
+// selects every host so adds no filter.
+func Selectors(remote config.Remote) ([]host.Filter, error) {
+	filters := []host.Filter{}
+	if remote.Name != "" {
+		if _, err := regexp.Compile(remote.Name); err != nil {
+			return nil, err
+		}
+		filters = append(filters, host.ByName(remote.Name))
+	}
+	if remote.IP != "" {
+		filters = append(filters, host.ByIP(remote.IP))
+	}
+	if remote.MAC != "" {
+		filters = append(filters, host.ByMAC(remote.MAC))
+	}
+	if len(remote.Labels) > 0 {
+		filters = append(filters, host.ByLabels(remote.Labels))
+	}
+	return filters, nil
+}
+
+// FanOut runs call concurrently
+// against each of the hosts.
+func FanOut(cfg config.Config, hosts host.Hosts, call Call) []Result {
+	results := make([]Result, len(hosts))
+	var wg sync.WaitGroup
+	for i, h := range hosts {
+		wg.Add(1)
+		go func(i int, h *host.Host) {
+			defer wg.Done()
+			results[i] = Result{Host: h}
+			resp, err := callHost(cfg, h, call)
+			if err != nil {
+				results[i].Error = err.Error()
+				return
+			}
+			results[i].Response = resp
+		}(i, h)
+	}
+	wg.Wait()
+	return results
+}
+
+func callHost(cfg config.Config, h *host.Host, call Call) (interface{}, error) {
+	cfg.Address = fmt.Sprintf("tcp://%s:%d", h.Address, port(cfg, h))
+	conn, err := NewClientConn(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return call(h, conn)
+}
+
+// port returns the RPC port of h or the configured
+// port if the host did not register one.
+func port(cfg config.Config, h *host.Host) int {
+	if h.Port == 0 {
+		return cfg.Remote.Port
+	}
+	return int(h.Port)
+}
@@ -0,0 +1,44 @@
+package util
+
+import (
+	"github.com/mesanine/gaffer/config"
+	"github.com/mesanine/gaffer/host"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSelectors(t *testing.T) {
+	hosts := host.Hosts{
+		{Name: "web-1", Address: "10.0.0.1", Mac: "aa", Labels: map[string]string{"zone": "a"}},
+		{Name: "web-2", Address: "10.0.0.2", Mac: "bb", Labels: map[string]string{"zone": "b"}},
+		{Name: "db-1", Address: "10.0.0.3", Mac: "cc", Labels: map[string]string{"zone": "a"}},
+	}
+	for _, test := range []struct {
+		remote  config.Remote
+		matched []string
+	}{
+		{config.Remote{All: true}, []string{"web-1", "web-2", "db-1"}},
+		{config.Remote{Name: "web"}, []string{"web-1", "web-2"}},
+		{config.Remote{Name: "web", Labels: map[string]string{"zone": "a"}}, []string{"web-1"}},
+		{config.Remote{All: true, Labels: map[string]string{"zone": "a"}}, []string{"web-1", "db-1"}},
+		{config.Remote{IP: "10.0.0.2", MAC: "bb"}, []string{"web-2"}},
+		{config.Remote{IP: "10.0.0.2", MAC: "aa"}, []string{}},
+		{config.Remote{Name: "db", IP: "10.0.0.1"}, []string{}},
+	} {
+		filters, err := Selectors(test.remote)
+		assert.NoError(t, err)
+		matched := []string{}
+		for _, h := range hosts.Filter(filters...) {
+			matched = append(matched, h.Name)
+		}
+		assert.Equal(t, test.matched, matched, "%+v", test.remote)
+	}
+	_, err := Selectors(config.Remote{Name: "("})
+	assert.Error(t, err)
+}
+
+func TestPort(t *testing.T) {
+	cfg := config.Config{Remote: config.Remote{Port: 10000}}
+	assert.Equal(t, 10000, port(cfg, &host.Host{}))
+	assert.Equal(t, 9000, port(cfg, &host.Host{Port: 9000}))
+}
@@ -1,7 +1,6 @@
 package util
 
 import (
-	"encoding/json"
 	"fmt"
 	"github.com/mesanine/gaffer/config"
 	"github.com/mesanine/gaffer/log"
@@ -20,10 +19,6 @@ func Maybe(err error) {
 	}
 }
 
-func JSONToStdout(o interface{}) {
-	Maybe(json.NewEncoder(os.Stdout).Encode(o))
-}
-
 func NewClientConn(cfg config.Config) (*grpc.ClientConn, error) {
 	log.Log.Info(fmt.Sprintf("dailing %s", cfg.Address))
 	opts, err := cfg.DailOpts()
@@ -0,0 +1,113 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// writeYAML writes the JSON encoding of v as
+// block style YAML with keys in sorted order.
+func writeYAML(w io.Writer, v interface{}) error {
+	generic, err := toGeneric(v)
+	if err != nil {
+		return err
+	}
+	for _, line := range yamlLines(generic) {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// yamlLines returns the lines of a decoded
+// JSON value, a scalar is a single line.
+func yamlLines(v interface{}) []string {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		if len(t) == 0 {
+			return []string{"{}"}
+		}
+		keys := []string{}
+		for key := range t {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		lines := []string{}
+		for _, key := range keys {
+			value := t[key]
+			if !isBlock(value) {
+				lines = append(lines, fmt.Sprintf("%s: %s", yamlString(key), yamlLines(value)[0]))
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("%s:", yamlString(key)))
+			// Sequences are not indented
+			// beneath their key.
+			indent := "  "
+			if _, ok := value.([]interface{}); ok {
+				indent = ""
+			}
+			for _, line := range yamlLines(value) {
+				lines = append(lines, indent+line)
+			}
+		}
+		return lines
+	case []interface{}:
+		if len(t) == 0 {
+			return []string{"[]"}
+		}
+		lines := []string{}
+		for _, item := range t {
+			nested := yamlLines(item)
+			lines = append(lines, "- "+nested[0])
+			for _, line := range nested[1:] {
+				lines = append(lines, "  "+line)
+			}
+		}
+		return lines
+	case json.Number:
+		return []string{t.String()}
+	case bool:
+		return []string{strconv.FormatBool(t)}
+	case string:
+		return []string{yamlString(t)}
+	}
+	return []string{"null"}
+}
+
+// isBlock reports if v is a non-empty
+// mapping or sequence.
+func isBlock(v interface{}) bool {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		return len(t) > 0
+	case []interface{}:
+		return len(t) > 0
+	}
+	return false
+}
+
+// yamlString quotes s if it would otherwise be
+// read as another type or is not a plain scalar.
+func yamlString(s string) string {
+	if s == "" || s != strings.TrimSpace(s) {
+		return strconv.Quote(s)
+	}
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "null", "~":
+		return strconv.Quote(s)
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return strconv.Quote(s)
+	}
+	if strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`") ||
+		strings.Contains(s, ": ") || strings.Contains(s, " #") ||
+		strings.HasSuffix(s, ":") || strings.IndexFunc(s, func(r rune) bool { return r < ' ' }) >= 0 {
+		return strconv.Quote(s)
+	}
+	return s
+}